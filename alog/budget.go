@@ -0,0 +1,36 @@
+package alog
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WarnIfLowBudget logs a Warning log if less than the threshold is left before the deadline of the context, which
+// helps to pinpoint the steps of a handler which eat up the budget of requests with tight deadlines.
+//
+// The remaining duration is written to the numeric remainingMs field, in milliseconds, and is zero if the deadline
+// has already passed. Nothing is logged if the context has no deadline.
+//
+//	alog.WarnIfLowBudget(ctx, 500*time.Millisecond, "about to query spanner")
+func WarnIfLowBudget(ctx context.Context, threshold time.Duration, msg string) {
+	if loggingLevel > LevelWarning {
+		return
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	remaining := max(time.Until(deadline), 0)
+	if remaining >= threshold {
+		return
+	}
+
+	remainingMs := float64(remaining) / float64(time.Millisecond)
+	(&entry{
+		Message:     fmt.Sprintf("%s (%s of budget remaining)", msg, remaining),
+		Level:       LevelWarning,
+		Ctx:         ctx,
+		RemainingMs: &remainingMs,
+	}).Output()
+}
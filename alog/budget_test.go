@@ -0,0 +1,59 @@
+package alog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWarnIfLowBudget(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	loggingEnvironment = EnvironmentGoogle
+	t.Cleanup(func() {
+		ResetRoutes()
+		SetLoggingEnvironment(EnvironmentLocal)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	WarnIfLowBudget(ctx, time.Second, "about to query spanner")
+
+	var got struct {
+		Message     string   `json:"message"`
+		Severity    string   `json:"severity"`
+		RemainingMs *float64 `json:"remainingMs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, output = %s", err, buf.String())
+	}
+	if got.Severity != LevelWarning.String() {
+		t.Errorf("severity = %q, want %q", got.Severity, LevelWarning)
+	}
+	if got.RemainingMs == nil || *got.RemainingMs > 50 {
+		t.Errorf("remainingMs = %v, want at most 50", got.RemainingMs)
+	}
+	if !bytes.Contains([]byte(got.Message), []byte("about to query spanner")) {
+		t.Errorf("message = %q, want it to contain the provided message", got.Message)
+	}
+}
+
+func TestWarnIfLowBudget_NoWarning(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	t.Cleanup(ResetRoutes)
+
+	// No deadline
+	WarnIfLowBudget(context.Background(), time.Second, "no deadline")
+
+	// Plenty of budget left
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	WarnIfLowBudget(ctx, time.Second, "plenty of budget")
+
+	if buf.Len() != 0 {
+		t.Errorf("WarnIfLowBudget() logged %q, want nothing", buf.String())
+	}
+}
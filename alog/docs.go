@@ -12,5 +12,13 @@ flexible), the structured logs will be written as JSON objects to stdout or stde
 the Logging agent and sent to Cloud Logging as the jsonPayload of the LogEntry structure.
 
 The package also includes a local environment mode that formats logs in a human-friendly way while developing locally.
+
+In the Google environment, the trace of the context passed to the logging functions is added to the log entries so
+that they are correlated with Cloud Trace. The trace is read from the X-Cloud-Trace-Context or traceparent header of
+the incoming gRPC metadata, or from a function registered with SetTraceExtractor, and the project of the trace is set
+with SetProjectID.
+
+The minimum logging level can be set with SetLevel, or at start up with the ALOG_LEVEL environment variable, for
+example ALOG_LEVEL=DEBUG. Unknown values are reported with a warning and the default level is used.
 */
 package alog //import "go.alis.build/alog"
@@ -4,10 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"google.golang.org/grpc/metadata"
-	"io"
 	"log"
-	"os"
 	"runtime"
 	"strings"
 )
@@ -26,10 +23,6 @@ const (
 	EnvironmentGoogle LoggingEnvironment = "GOOGLE"
 )
 
-var (
-	w io.Writer = os.Stderr
-)
-
 // LogEntrySourceLocation provides additional information about the source code location that produced the log entry.
 type logEntrySourceLocation struct {
 	File     string `json:"file,omitempty"`
@@ -54,8 +47,16 @@ type entry struct {
 	Severity       string                  `json:"severity,omitempty"`
 	Level          LogLevel                `json:"-"`
 	Trace          string                  `json:"logging.googleapis.com/trace,omitempty"`
+	SpanID         string                  `json:"logging.googleapis.com/spanId,omitempty"`
+	TraceSampled   bool                    `json:"logging.googleapis.com/trace_sampled,omitempty"`
 	SourceLocation *logEntrySourceLocation `json:"logging.googleapis.com/sourceLocation,omitempty"`
+	Labels         map[string]string       `json:"logging.googleapis.com/labels,omitempty"`
+	Timer          string                  `json:"timer,omitempty"`
+	DurationMs     *float64                `json:"durationMs,omitempty"`
+	RemainingMs    *float64                `json:"remainingMs,omitempty"`
 	Ctx            context.Context         `json:"-"`
+	// Payload holds structured fields which are merged into the top level of the entry, see InfoJSON.
+	Payload map[string]any `json:"-"`
 }
 
 // Bytes renders an entry structure to the JSON format expected by Cloud Logging.
@@ -101,16 +102,36 @@ func (e entry) Bytes() []byte {
 			color = 101
 		}
 
+		// Append any structured payload and the fields of the context to the message.
+		message := e.Message
+		if len(e.Payload) > 0 {
+			if payload, err := json.Marshal(e.Payload); err == nil {
+				message = strings.TrimSpace(message + " " + string(payload))
+			}
+		}
+		if fields := getFields(e.Ctx); len(fields) > 0 {
+			message = strings.TrimSpace(message + " " + formatFields(fields))
+		}
+
 		if loggingLevel == LevelDebug {
-			return []byte(fmt.Sprintf("\x1b[%dm%s\x1b[0m \u001B[34m%s:%v\u001B[0m %s", color, e.Severity, e.SourceLocation.File, e.SourceLocation.Line, e.Message))
+			return []byte(fmt.Sprintf("\x1b[%dm%s\x1b[0m \u001B[34m%s:%v\u001B[0m %s", color, e.Severity, e.SourceLocation.File, e.SourceLocation.Line, message))
 		} else {
-			return []byte(fmt.Sprintf("\x1b[%dm%s\x1b[0m %s", color, e.Severity, e.Message))
+			return []byte(fmt.Sprintf("\x1b[%dm%s\x1b[0m %s", color, e.Severity, message))
 		}
 
 	} else {
 		// Attempt to extract the trace from the context.
-		if e.Trace == "" && e.Ctx != nil {
-			e.Trace = getTrace(e.Ctx)
+		if e.Trace == "" {
+			if tc, ok := getTraceContext(e.Ctx); ok {
+				e.Trace = tc.name()
+				e.SpanID = tc.spanID
+				e.TraceSampled = tc.sampled
+			}
+		}
+
+		// Add the default labels and any labels set on the context.
+		if e.Labels == nil {
+			e.Labels = getLabels(e.Ctx)
 		}
 
 		// Log a structured log inline with the LogEntry definition.
@@ -118,32 +139,54 @@ func (e entry) Bytes() []byte {
 		if err != nil {
 			log.Printf("json.Marshal: %v", err)
 		}
+
+		// Merge any structured payload and the fields of the context into the entry, the payload taking precedence.
+		payload := e.Payload
+		if fields := getFields(e.Ctx); len(fields) > 0 {
+			payload = make(map[string]any, len(fields)+len(e.Payload))
+			for k, v := range fields {
+				payload[k] = v
+			}
+			for k, v := range e.Payload {
+				payload[k] = v
+			}
+		}
+		if len(payload) > 0 && err == nil {
+			merged, err := mergePayload(out, payload)
+			if err != nil {
+				log.Printf("merge payload: %v", err)
+				return out
+			}
+			return merged
+		}
 		return out
 	}
 }
 
-// Output writes the Entry object to the standard out.
+// Output writes the Entry object to all the writers routed for its level, which is stderr by default.
+// The writes of concurrent calls are serialized, so the writers do not need to be safe for concurrent use.
 func (e entry) Output() error {
 	b := e.Bytes()
 	// Appends a newline to the output.
 	b = append(b, '\n')
-	_, err := w.Write(b)
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	var err error
+	for _, writer := range writersFor(e.Level) {
+		if _, writeErr := writer.Write(b); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
 	return err
 }
 
-// GetTrace retrieves a trace header from the provided context.
+// GetTrace retrieves the fully-qualified trace name from the provided context.
 // Returns an empty string if not found.
 func getTrace(ctx context.Context) string {
-	// Derive the traceID associated with the current request.
-	var trace string
-	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		traceHeaders := md.Get("x-cloud-trace-context")
-		if len(traceHeaders) > 0 {
-			traceParts := strings.Split(traceHeaders[0], "/")
-			if len(traceParts) > 0 && len(traceParts[0]) > 0 {
-				trace = fmt.Sprintf("projects/%s/traces/%s", os.Getenv("ALIS_OS_PROJECT"), traceParts[0])
-			}
-		}
+	if tc, ok := getTraceContext(ctx); ok {
+		return tc.name()
 	}
-	return trace
+	return ""
 }
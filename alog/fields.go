@@ -0,0 +1,63 @@
+package alog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fieldsContextKey is the context key used to store the per context fields.
+type fieldsContextKey struct{}
+
+// WithFields returns a copy of the parent context which carries the provided key/value fields, e.g. request or user
+// ids, which are added to every log entry written with the context.
+//
+// In the Google logging environment, the fields are written as top level fields of the entry, which Cloud Logging
+// keeps in the jsonPayload so they can be queried in the Logs Explorer, e.g. jsonPayload.requestId="abc". The fields
+// of the entry itself, e.g. message or severity, take precedence over fields with the same key. In the local logging
+// environment, the fields are appended to the line as key=value pairs, sorted by key.
+//
+// Fields already present on the parent context are retained, unless overwritten by a field with the same key.
+// Nil or empty fields return the parent context as is.
+//
+//	ctx = alog.WithFields(ctx, map[string]any{"requestId": req.GetRequestId(), "userId": userId})
+//	alog.Info(ctx, "order placed")
+func WithFields(ctx context.Context, fields map[string]any) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	parent, _ := ctx.Value(fieldsContextKey{}).(map[string]any)
+	merged := make(map[string]any, len(parent)+len(fields))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsContextKey{}, merged)
+}
+
+// getFields returns the fields on the provided context, or nil if there are none.
+func getFields(ctx context.Context) map[string]any {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsContextKey{}).(map[string]any)
+	return fields
+}
+
+// formatFields renders the fields as key=value pairs, sorted by key.
+func formatFields(fields map[string]any) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, fields[k]))
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,74 @@
+package alog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	loggingEnvironment = EnvironmentGoogle
+	t.Cleanup(func() {
+		ResetRoutes()
+		SetLoggingEnvironment(EnvironmentLocal)
+	})
+
+	ctx := WithFields(context.Background(), map[string]any{"requestId": "abc", "userId": "123"})
+	ctx = WithFields(ctx, map[string]any{"userId": "456", "attempt": 2, "severity": "DEBUG"})
+	Info(ctx, "order placed")
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, output = %s", err, buf.String())
+	}
+	want := map[string]any{
+		"message":   "order placed",
+		"severity":  LevelInfo.String(),
+		"requestId": "abc",
+		"userId":    "456",
+		"attempt":   float64(2),
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestWithFields_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	t.Cleanup(ResetRoutes)
+
+	for _, env := range []LoggingEnvironment{EnvironmentGoogle, EnvironmentLocal} {
+		SetLoggingEnvironment(env)
+		for _, fields := range []map[string]any{nil, {}} {
+			buf.Reset()
+			Info(context.Background(), "hello")
+			want := buf.String()
+
+			buf.Reset()
+			Info(WithFields(context.Background(), fields), "hello")
+			if got := buf.String(); got != want {
+				t.Errorf("Info() with fields %v = %q, want %q", fields, got, want)
+			}
+		}
+	}
+	SetLoggingEnvironment(EnvironmentLocal)
+}
+
+func TestWithFields_Local(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	SetLoggingEnvironment(EnvironmentLocal)
+	t.Cleanup(ResetRoutes)
+
+	Info(WithFields(context.Background(), map[string]any{"userId": "123", "requestId": "abc"}), "order placed")
+	if got := buf.String(); !strings.Contains(got, "order placed requestId=abc userId=123") {
+		t.Errorf("Info() = %q, want the fields appended as sorted key=value pairs", got)
+	}
+}
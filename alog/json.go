@@ -0,0 +1,75 @@
+package alog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// InfoJSON logs an Info level log whose payload is already structured, e.g. a struct, a map or a string holding a
+// JSON object.
+//
+// The fields of the payload are merged into the structured entry, alongside the trace and labels of the context, so
+// Cloud Logging indexes them as nested fields of the jsonPayload rather than a single escaped string. A "message"
+// field of the payload is used as the message of the entry. The fields of the entry itself, e.g. severity, take
+// precedence over payload fields with the same name.
+//
+// Payloads which cannot be marshalled to a JSON object are logged as a plain message instead.
+//
+//	alog.InfoJSON(ctx, map[string]any{"message": "order placed", "order": map[string]any{"id": "123", "items": 2}})
+func InfoJSON(ctx context.Context, payload any) {
+	if loggingLevel <= LevelInfo && sampled(LevelInfo) {
+		jsonEntry(ctx, LevelInfo, payload).Output()
+	}
+}
+
+// jsonEntry returns the entry for the provided structured payload, falling back to a plain message if the payload is
+// not a JSON object.
+func jsonEntry(ctx context.Context, level LogLevel, payload any) *entry {
+	var data []byte
+	switch p := payload.(type) {
+	case json.RawMessage:
+		data = p
+	case []byte:
+		data = p
+	case string:
+		data = []byte(p)
+	default:
+		var err error
+		data, err = json.Marshal(payload)
+		if err != nil {
+			return &entry{Message: fmt.Sprintf("%v", payload), Level: level, Ctx: ctx}
+		}
+	}
+
+	fields := map[string]any{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil || fields == nil {
+		return &entry{Message: string(data), Level: level, Ctx: ctx}
+	}
+
+	e := &entry{Level: level, Ctx: ctx, Payload: fields}
+	if message, ok := fields["message"].(string); ok {
+		e.Message = message
+		delete(fields, "message")
+	}
+	return e
+}
+
+// mergePayload adds the payload fields to the marshalled entry, without overriding the fields of the entry itself.
+func mergePayload(out []byte, payload map[string]any) ([]byte, error) {
+	fields := map[string]any{}
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	for key, value := range payload {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,98 @@
+package alog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestInfoJSON(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	loggingEnvironment = EnvironmentGoogle
+	t.Cleanup(func() {
+		ResetRoutes()
+		SetLoggingEnvironment(EnvironmentLocal)
+	})
+
+	type order struct {
+		Id    string `json:"id"`
+		Items int    `json:"items"`
+	}
+	tests := []struct {
+		name    string
+		payload any
+	}{
+		{name: "struct", payload: struct {
+			Message  string `json:"message"`
+			Order    order  `json:"order"`
+			Severity string `json:"severity"`
+		}{Message: "order placed", Order: order{Id: "123", Items: 2}, Severity: "DEBUG"}},
+		{name: "json string", payload: `{"message": "order placed", "order": {"id": "123", "items": 2}, "severity": "DEBUG"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			InfoJSON(context.Background(), tt.payload)
+
+			var got struct {
+				Message  string `json:"message"`
+				Severity string `json:"severity"`
+				Order    *order `json:"order"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v, output = %s", err, buf.String())
+			}
+			if got.Message != "order placed" {
+				t.Errorf("message = %q, want %q", got.Message, "order placed")
+			}
+			// The fields of the entry take precedence over the payload.
+			if got.Severity != LevelInfo.String() {
+				t.Errorf("severity = %q, want %q", got.Severity, LevelInfo)
+			}
+			if got.Order == nil || got.Order.Id != "123" || got.Order.Items != 2 {
+				t.Errorf("order = %+v, want the nested fields unescaped, output = %s", got.Order, buf.String())
+			}
+		})
+	}
+}
+
+func TestInfoJSON_Fallback(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	loggingEnvironment = EnvironmentGoogle
+	t.Cleanup(func() {
+		ResetRoutes()
+		SetLoggingEnvironment(EnvironmentLocal)
+	})
+
+	tests := []struct {
+		name        string
+		payload     any
+		wantMessage string
+	}{
+		{name: "not serializable", payload: make(chan int), wantMessage: ""},
+		{name: "not an object", payload: `["a", "b"]`, wantMessage: `["a", "b"]`},
+		{name: "invalid json", payload: "plain text", wantMessage: "plain text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			InfoJSON(context.Background(), tt.payload)
+
+			var got struct {
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v, output = %s", err, buf.String())
+			}
+			if tt.wantMessage != "" && got.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", got.Message, tt.wantMessage)
+			}
+			if got.Message == "" {
+				t.Errorf("message is empty, want the payload as a string")
+			}
+		})
+	}
+}
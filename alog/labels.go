@@ -0,0 +1,80 @@
+package alog
+
+import (
+	"context"
+	"sync"
+)
+
+// ComponentLabel is the label key used by WithComponent.
+const ComponentLabel = "component"
+
+var (
+	defaultLabels   map[string]string
+	defaultLabelsMu sync.RWMutex
+)
+
+// labelsContextKey is the context key used to store the per context labels.
+type labelsContextKey struct{}
+
+// SetDefaultLabels sets the labels which are added to every log entry.
+//
+// Labels set on the context using WithLabels or WithComponent take precedence over the default labels with the same
+// key. In the Google logging environment, the labels are written to the logging.googleapis.com/labels field, which
+// Cloud Logging maps onto the labels of the LogEntry.
+func SetDefaultLabels(labels map[string]string) {
+	defaultLabelsMu.Lock()
+	defer defaultLabelsMu.Unlock()
+
+	defaultLabels = make(map[string]string, len(labels))
+	for k, v := range labels {
+		defaultLabels[k] = v
+	}
+}
+
+// WithLabels returns a copy of the parent context which carries the provided labels.
+//
+// Labels already present on the parent context are retained, unless overwritten by a label with the same key.
+func WithLabels(ctx context.Context, labels map[string]string) context.Context {
+	parent, _ := ctx.Value(labelsContextKey{}).(map[string]string)
+	merged := make(map[string]string, len(parent)+len(labels))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, labelsContextKey{}, merged)
+}
+
+// WithComponent returns a copy of the parent context which carries the "component" label.
+//
+// This allows all the logs of a given subsystem to be filtered consistently in Cloud Logging, for example:
+//
+//	labels.component="billing"
+func WithComponent(ctx context.Context, component string) context.Context {
+	return WithLabels(ctx, map[string]string{ComponentLabel: component})
+}
+
+// getLabels returns the default labels merged with the labels on the provided context.
+// Returns nil if there are no labels.
+func getLabels(ctx context.Context) map[string]string {
+	var ctxLabels map[string]string
+	if ctx != nil {
+		ctxLabels, _ = ctx.Value(labelsContextKey{}).(map[string]string)
+	}
+
+	defaultLabelsMu.RLock()
+	defer defaultLabelsMu.RUnlock()
+	if len(defaultLabels) == 0 && len(ctxLabels) == 0 {
+		return nil
+	}
+
+	labels := make(map[string]string, len(defaultLabels)+len(ctxLabels))
+	for k, v := range defaultLabels {
+		labels[k] = v
+	}
+	for k, v := range ctxLabels {
+		labels[k] = v
+	}
+	return labels
+}
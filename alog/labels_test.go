@@ -0,0 +1,55 @@
+package alog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestWithComponent(t *testing.T) {
+	var buf bytes.Buffer
+	previous := output
+	SetOutput(&buf)
+	loggingEnvironment = EnvironmentGoogle
+	SetDefaultLabels(map[string]string{"service": "orders", ComponentLabel: "default"})
+	t.Cleanup(func() {
+		SetOutput(previous)
+		SetDefaultLabels(nil)
+		SetLoggingEnvironment(EnvironmentLocal)
+	})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want map[string]string
+	}{
+		{
+			name: "DefaultLabels",
+			ctx:  context.Background(),
+			want: map[string]string{"service": "orders", ComponentLabel: "default"},
+		},
+		{
+			name: "ContextLabelsOverrideDefaults",
+			ctx:  WithLabels(WithComponent(context.Background(), "billing"), map[string]string{"region": "eu"}),
+			want: map[string]string{"service": "orders", ComponentLabel: "billing", "region": "eu"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			Info(tt.ctx, "hello")
+
+			var got struct {
+				Labels map[string]string `json:"logging.googleapis.com/labels"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v, output = %s", err, buf.String())
+			}
+			if !reflect.DeepEqual(got.Labels, tt.want) {
+				t.Errorf("labels = %v, want %v", got.Labels, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package alog
 
+import (
+	"fmt"
+	"strings"
+)
+
 // LogLevel int is used to map the logging levels consistent with Google Cloud Logging.
 type LogLevel int
 
@@ -23,6 +28,9 @@ const (
 	LevelAlert LogLevel = 12
 	// LevelEmergency One or more systems are unusable.
 	LevelEmergency LogLevel = 14
+	// LevelSilent suppresses all logs, including the log line of Fatal and Fatalf, which still exit the program.
+	// It is only intended to be used with SetLevel, for example to keep the output of CLI tools clean.
+	LevelSilent LogLevel = 100
 )
 
 // String returns a name for the level.
@@ -48,7 +56,39 @@ func (l LogLevel) String() string {
 		return "ALERT"
 	case LevelEmergency:
 		return "EMERGENCY"
+	case LevelSilent:
+		return "SILENT"
 	default:
 		return "INFO"
 	}
 }
+
+// ParseLevel returns the level named by s, as returned by LogLevel.String.
+//
+// The name is case-insensitive, so both "warning" and "WARNING" return LevelWarning.
+func ParseLevel(s string) (LogLevel, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "DEFAULT":
+		return LevelDefault, nil
+	case "DEBUG":
+		return LevelDebug, nil
+	case "INFO":
+		return LevelInfo, nil
+	case "NOTICE":
+		return LevelNotice, nil
+	case "WARNING":
+		return LevelWarning, nil
+	case "ERROR":
+		return LevelError, nil
+	case "CRITICAL":
+		return LevelCritical, nil
+	case "ALERT":
+		return LevelAlert, nil
+	case "EMERGENCY":
+		return LevelEmergency, nil
+	case "SILENT":
+		return LevelSilent, nil
+	default:
+		return LevelDefault, fmt.Errorf("unknown log level %q", s)
+	}
+}
@@ -0,0 +1,107 @@
+package alog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestSetLevel_Silent(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelSilent, &buf)
+	SetLevel(LevelSilent)
+	t.Cleanup(func() {
+		ResetRoutes()
+		SetLevel(LevelDefault)
+	})
+
+	ctx := context.Background()
+	Debug(ctx, "debug")
+	Info(ctx, "info")
+	Notice(ctx, "notice")
+	Warn(ctx, "warning")
+	Error(ctx, "error")
+	Critical(ctx, "critical")
+	Alert(ctx, "alert")
+	Emergencyf(ctx, "%s", "emergency")
+
+	if buf.Len() != 0 {
+		t.Errorf("got %d bytes written in silent mode, want 0: %s", buf.Len(), buf.String())
+	}
+}
+
+func TestFatal_Silent(t *testing.T) {
+	// Fatal exits the program, so it is run in a separate process.
+	if os.Getenv("ALOG_TEST_FATAL") == "1" {
+		SetLevel(LevelSilent)
+		Fatal(context.Background(), "fatal")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFatal_Silent$")
+	cmd.Env = append(os.Environ(), "ALOG_TEST_FATAL=1")
+	output, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("Fatal() error = %v, want exit status 1", err)
+	}
+	if len(output) != 0 {
+		t.Errorf("got %d bytes written in silent mode, want 0: %s", len(output), output)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    LogLevel
+		wantErr bool
+	}{
+		{in: "DEBUG", want: LevelDebug},
+		{in: "info", want: LevelInfo},
+		{in: " Warning ", want: LevelWarning},
+		{in: "EMERGENCY", want: LevelEmergency},
+		{in: "silent", want: LevelSilent},
+		{in: "verbose", want: LevelDefault, wantErr: true},
+		{in: "", want: LevelDefault, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLevel(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevelFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    LogLevel
+		wantErr bool
+	}{
+		{name: "unset", value: "", want: LevelDefault},
+		{name: "valid", value: "error", want: LevelError},
+		{name: "invalid", value: "loud", want: LevelDefault, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(LevelEnvVar, tt.value)
+			got, err := levelFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("levelFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("levelFromEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"os"
 )
 
+// LevelEnvVar is the environment variable read at init to set the logging level, for example ALOG_LEVEL=DEBUG.
+const LevelEnvVar = "ALOG_LEVEL"
+
 var (
 	loggingLevel       LogLevel
 	loggingEnvironment LoggingEnvironment
@@ -29,6 +32,27 @@ func init() {
 	} else {
 		loggingEnvironment = EnvironmentLocal
 	}
+
+	// Override the default Log Level from the environment, if set
+	level, err := levelFromEnv()
+	loggingLevel = level
+	if err != nil {
+		(&entry{Message: err.Error(), Level: LevelWarning, Ctx: context.Background()}).Output()
+	}
+}
+
+// levelFromEnv returns the level set in the LevelEnvVar environment variable.
+// LevelDefault is returned if the variable is unset, along with an error if it cannot be parsed.
+func levelFromEnv() (LogLevel, error) {
+	value, ok := os.LookupEnv(LevelEnvVar)
+	if !ok || value == "" {
+		return LevelDefault, nil
+	}
+	level, err := ParseLevel(value)
+	if err != nil {
+		return LevelDefault, fmt.Errorf("alog: ignoring %s: %w, using the default level", LevelEnvVar, err)
+	}
+	return level, nil
 }
 
 // Debug logs a Debug level log.
@@ -37,7 +61,7 @@ func init() {
 // also include a SourceLocation attribute which will provide file, method and line number details of
 // the particular log.
 func Debug(ctx context.Context, msg string) {
-	if loggingLevel <= LevelDebug {
+	if loggingLevel <= LevelDebug && sampled(LevelDebug) {
 		(&entry{Message: msg, Level: LevelDebug, Ctx: ctx}).Output()
 	}
 }
@@ -48,21 +72,21 @@ func Debug(ctx context.Context, msg string) {
 // also include a SourceLocation attribute which will provide file, method and line number details of
 // the particular log.
 func Debugf(ctx context.Context, format string, a ...any) {
-	if loggingLevel <= LevelDebug {
+	if loggingLevel <= LevelDebug && sampled(LevelDebug) {
 		(&entry{Message: fmt.Sprintf(format, a...), Level: LevelDebug, Ctx: ctx}).Output()
 	}
 }
 
 // Info logs an Info level log.
 func Info(ctx context.Context, msg string) {
-	if loggingLevel <= LevelInfo {
+	if loggingLevel <= LevelInfo && sampled(LevelInfo) {
 		(&entry{Message: msg, Level: LevelInfo, Ctx: ctx}).Output()
 	}
 }
 
 // Infof logs an Info level log with the given context.
 func Infof(ctx context.Context, format string, a ...any) {
-	if loggingLevel <= LevelInfo {
+	if loggingLevel <= LevelInfo && sampled(LevelInfo) {
 		(&entry{Message: fmt.Sprintf(format, a...), Level: LevelInfo, Ctx: ctx}).Output()
 	}
 }
@@ -168,6 +192,7 @@ func Emergencyf(ctx context.Context, format string, a ...any) {
 }
 
 // SetLevel sets the minimum logging level.
+// Use LevelSilent to suppress all logs.
 func SetLevel(level LogLevel) {
 	loggingLevel = level
 }
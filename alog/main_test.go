@@ -3,6 +3,8 @@ package alog
 import (
 	"context"
 	"testing"
+
+	"google.golang.org/grpc/metadata"
 )
 
 func init() {
@@ -341,8 +343,19 @@ func Test_getTrace(t *testing.T) {
 		args args
 		want string
 	}{
-		// TODO: Add test cases.
+		{
+			name: "no trace",
+			args: args{ctx: context.Background()},
+			want: "",
+		},
+		{
+			name: "cloud trace header",
+			args: args{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-cloud-trace-context", "105445aa7843bc8bf206b12000100000/1;o=1"))},
+			want: "projects/my-project/traces/105445aa7843bc8bf206b12000100000",
+		},
 	}
+	SetProjectID("my-project")
+	t.Cleanup(func() { SetProjectID("") })
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if got := getTrace(tt.args.ctx); got != tt.want {
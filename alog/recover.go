@@ -0,0 +1,43 @@
+package alog
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// repanic indicates whether RecoverAndLog re-panics after logging the recovered panic.
+var repanic atomic.Bool
+
+// SetRepanic configures whether RecoverAndLog re-panics after logging a recovered panic.
+//
+// By default the panic is swallowed, keeping the process alive.
+func SetRepanic(enabled bool) {
+	repanic.Store(enabled)
+}
+
+// RecoverAndLog recovers from a panic in the calling goroutine and logs a Critical log with the panic value and the
+// stack trace. Depending on SetRepanic, the panic is then swallowed or raised again.
+//
+// It must be deferred directly at the top of the goroutine, since recover only has an effect when called by a deferred
+// function:
+//
+//	go func() {
+//		defer alog.RecoverAndLog(ctx)
+//		// ...
+//	}()
+func RecoverAndLog(ctx context.Context) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	if loggingLevel <= LevelCritical {
+		(&entry{Message: fmt.Sprintf("recovered from panic: %v\n%s", r, debug.Stack()), Level: LevelCritical, Ctx: ctx}).Output()
+	}
+
+	if repanic.Load() {
+		panic(r)
+	}
+}
@@ -0,0 +1,56 @@
+package alog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRecoverAndLog(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	t.Cleanup(ResetRoutes)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer RecoverAndLog(context.Background())
+		panic("boom")
+	}()
+	<-done
+
+	got := buf.String()
+	if !strings.Contains(got, "recovered from panic: boom") {
+		t.Errorf("log = %q, want the panic value", got)
+	}
+	if !strings.Contains(got, "TestRecoverAndLog") {
+		t.Errorf("log = %q, want the stack trace", got)
+	}
+	if !strings.Contains(got, LevelCritical.String()) {
+		t.Errorf("log = %q, want a %s log", got, LevelCritical)
+	}
+}
+
+func TestRecoverAndLog_Repanic(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	SetRepanic(true)
+	t.Cleanup(func() {
+		ResetRoutes()
+		SetRepanic(false)
+	})
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recover() = %v, want boom", r)
+		}
+		if !strings.Contains(buf.String(), "recovered from panic: boom") {
+			t.Errorf("log = %q, want the panic value", buf.String())
+		}
+	}()
+	func() {
+		defer RecoverAndLog(context.Background())
+		panic("boom")
+	}()
+}
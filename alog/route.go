@@ -0,0 +1,96 @@
+package alog
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// route writes the log entries with a level within [minLevel, maxLevel] to writer.
+type route struct {
+	minLevel LogLevel
+	maxLevel LogLevel
+	writer   io.Writer
+}
+
+var (
+	routes   []route
+	routesMu sync.RWMutex
+
+	// output and errorOutput are the writers used when no routes are registered, see SetOutput and SetErrorOutput.
+	output      io.Writer = os.Stderr
+	errorOutput io.Writer = os.Stderr
+
+	// writeMu serializes the writes of the log entries.
+	writeMu sync.Mutex
+)
+
+// AddRoute registers a writer for the log entries with a level between minLevel and maxLevel, inclusive.
+//
+// Each log entry is written to all the routes matching its level. If no routes are registered, the log entries are
+// written to the writers set with SetOutput and SetErrorOutput, which are stderr by default.
+//
+// Example, writing Info and below to stdout, and Warning and above to both stdout and an alerting sink:
+//
+//	alog.AddRoute(alog.LevelDebug, alog.LevelEmergency, os.Stdout)
+//	alog.AddRoute(alog.LevelWarning, alog.LevelEmergency, alertingSink)
+func AddRoute(minLevel, maxLevel LogLevel, writer io.Writer) {
+	routesMu.Lock()
+	defer routesMu.Unlock()
+
+	routes = append(routes, route{minLevel: minLevel, maxLevel: maxLevel, writer: writer})
+}
+
+// SetOutput sets the writer of the log entries below LevelError, which is stderr by default.
+//
+// It is only used when no routes are registered, see AddRoute. For example, to assert on the logs of a unit test:
+//
+//	var buf bytes.Buffer
+//	alog.SetOutput(&buf)
+//	defer alog.SetOutput(os.Stderr)
+func SetOutput(writer io.Writer) {
+	routesMu.Lock()
+	defer routesMu.Unlock()
+
+	output = writer
+}
+
+// SetErrorOutput sets the writer of the log entries at LevelError and above, which is stderr by default.
+//
+// It is only used when no routes are registered, see AddRoute.
+func SetErrorOutput(writer io.Writer) {
+	routesMu.Lock()
+	defer routesMu.Unlock()
+
+	errorOutput = writer
+}
+
+// ResetRoutes removes all the registered routes, restoring the default of writing the log entries to the writers set
+// with SetOutput and SetErrorOutput.
+func ResetRoutes() {
+	routesMu.Lock()
+	defer routesMu.Unlock()
+
+	routes = nil
+}
+
+// writersFor returns the writers for the provided level.
+func writersFor(level LogLevel) []io.Writer {
+	routesMu.RLock()
+	defer routesMu.RUnlock()
+
+	if len(routes) == 0 {
+		if level >= LevelError {
+			return []io.Writer{errorOutput}
+		}
+		return []io.Writer{output}
+	}
+
+	var writers []io.Writer
+	for _, r := range routes {
+		if level >= r.minLevel && level <= r.maxLevel {
+			writers = append(writers, r.writer)
+		}
+	}
+	return writers
+}
@@ -0,0 +1,110 @@
+package alog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAddRoute(t *testing.T) {
+	var stdout, alerts bytes.Buffer
+	SetLevel(LevelDebug)
+	AddRoute(LevelDebug, LevelEmergency, &stdout)
+	AddRoute(LevelWarning, LevelEmergency, &alerts)
+	t.Cleanup(func() {
+		ResetRoutes()
+		SetLevel(LevelDefault)
+	})
+
+	tests := []struct {
+		name      string
+		log       func(ctx context.Context, msg string)
+		wantAlert bool
+	}{
+		{name: "Debug", log: Debug, wantAlert: false},
+		{name: "Info", log: Info, wantAlert: false},
+		{name: "Notice", log: Notice, wantAlert: false},
+		{name: "Warning", log: Warn, wantAlert: true},
+		{name: "Error", log: Error, wantAlert: true},
+		{name: "Critical", log: Critical, wantAlert: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout.Reset()
+			alerts.Reset()
+			tt.log(context.Background(), "routed message")
+
+			if !strings.Contains(stdout.String(), "routed message") {
+				t.Errorf("stdout = %q, want the message", stdout.String())
+			}
+			if got := strings.Contains(alerts.String(), "routed message"); got != tt.wantAlert {
+				t.Errorf("alerts contains message = %v, want %v", got, tt.wantAlert)
+			}
+		})
+	}
+}
+
+func TestSetOutput(t *testing.T) {
+	var out, errOut bytes.Buffer
+	SetOutput(&out)
+	SetErrorOutput(&errOut)
+	loggingEnvironment = EnvironmentGoogle
+	t.Cleanup(func() {
+		SetOutput(os.Stderr)
+		SetErrorOutput(os.Stderr)
+		SetLoggingEnvironment(EnvironmentLocal)
+	})
+
+	Info(context.Background(), "info message")
+	Error(context.Background(), "error message")
+
+	var got map[string]any
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, output = %s", err, out.String())
+	}
+	if got["message"] != "info message" || got["severity"] != "INFO" {
+		t.Errorf("output = %v, want the info entry", got)
+	}
+	if err := json.Unmarshal(errOut.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, output = %s", err, errOut.String())
+	}
+	if got["message"] != "error message" || got["severity"] != "ERROR" {
+		t.Errorf("error output = %v, want the error entry", got)
+	}
+
+	// Routes take precedence over the outputs.
+	var routed bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &routed)
+	t.Cleanup(ResetRoutes)
+	out.Reset()
+	Info(context.Background(), "routed message")
+	if out.Len() != 0 || !strings.Contains(routed.String(), "routed message") {
+		t.Errorf("output = %q, routed = %q, want the entry routed only", out.String(), routed.String())
+	}
+}
+
+func TestSetOutput_Concurrent(t *testing.T) {
+	// bytes.Buffer is not safe for concurrent use, the writes must be serialized by alog.
+	var out bytes.Buffer
+	SetOutput(&out)
+	t.Cleanup(func() { SetOutput(os.Stderr) })
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Info(context.Background(), "concurrent message")
+		}()
+	}
+	wg.Wait()
+
+	if got := strings.Count(out.String(), "concurrent message\n"); got != n {
+		t.Errorf("got %d complete lines, want %d: %q", got, n, out.String())
+	}
+}
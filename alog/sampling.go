@@ -0,0 +1,42 @@
+package alog
+
+import "sync/atomic"
+
+var (
+	// sampling is the rate set with SetSampling, zero or one meaning no sampling.
+	sampling atomic.Int64
+	// debugCount and infoCount count the Debug and Info logs which passed the level filter, for sampling.
+	debugCount atomic.Uint64
+	infoCount  atomic.Uint64
+)
+
+// SetSampling only writes every nth Debug and Info log, starting with the first one, to reduce the cost of
+// high-volume logs. The logs of each level are counted separately. Use an n of 0 or 1 to disable sampling, which is
+// the default.
+//
+// The sampling decision is made before the message is formatted, so the logs which are dropped cost no formatting.
+// Sampling applies after the level set with SetLevel: the logs below the level are neither written nor counted.
+// The logs at LevelNotice and above are never sampled.
+func SetSampling(n int) {
+	sampling.Store(int64(n))
+	debugCount.Store(0)
+	infoCount.Store(0)
+}
+
+// sampled reports whether the next log at the provided level should be written.
+func sampled(level LogLevel) bool {
+	n := sampling.Load()
+	if n <= 1 {
+		return true
+	}
+	var count uint64
+	switch level {
+	case LevelDebug:
+		count = debugCount.Add(1)
+	case LevelInfo:
+		count = infoCount.Add(1)
+	default:
+		return true
+	}
+	return (count-1)%uint64(n) == 0
+}
@@ -0,0 +1,93 @@
+package alog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSetSampling(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	SetLevel(LevelDebug)
+	t.Cleanup(func() {
+		ResetRoutes()
+		SetLevel(LevelDefault)
+		SetSampling(0)
+	})
+
+	// counted counts the times it is formatted, to check that the dropped logs are not formatted.
+	var formatted int
+	counted := stringerFunc(func() string {
+		formatted++
+		return "message"
+	})
+
+	tests := []struct {
+		name          string
+		n             int
+		wantDebug     int
+		wantInfo      int
+		wantWarning   int
+		wantFormatted int
+	}{
+		{name: "disabled", n: 0, wantDebug: 7, wantInfo: 7, wantWarning: 7, wantFormatted: 7},
+		{name: "every log", n: 1, wantDebug: 7, wantInfo: 7, wantWarning: 7, wantFormatted: 7},
+		{name: "every third log", n: 3, wantDebug: 3, wantInfo: 3, wantWarning: 7, wantFormatted: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			formatted = 0
+			SetSampling(tt.n)
+			ctx := context.Background()
+			for i := 0; i < 7; i++ {
+				Debugf(ctx, "debug %s", counted)
+				Info(ctx, "info message")
+				Warn(ctx, "warning message")
+			}
+
+			output := buf.String()
+			if got := strings.Count(output, "debug message"); got != tt.wantDebug {
+				t.Errorf("got %d Debug logs, want %d", got, tt.wantDebug)
+			}
+			if got := strings.Count(output, "info message"); got != tt.wantInfo {
+				t.Errorf("got %d Info logs, want %d", got, tt.wantInfo)
+			}
+			if got := strings.Count(output, "warning message"); got != tt.wantWarning {
+				t.Errorf("got %d Warning logs, want %d", got, tt.wantWarning)
+			}
+			if formatted != tt.wantFormatted {
+				t.Errorf("formatted %d Debug logs, want %d", formatted, tt.wantFormatted)
+			}
+		})
+	}
+}
+
+func TestSetSampling_BelowLevel(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	SetSampling(2)
+	t.Cleanup(func() {
+		ResetRoutes()
+		SetLevel(LevelDefault)
+		SetSampling(0)
+	})
+
+	// The Debug logs filtered out by the level are not counted.
+	SetLevel(LevelInfo)
+	Debug(context.Background(), "dropped")
+	SetLevel(LevelDebug)
+	Debug(context.Background(), "first")
+	Debug(context.Background(), "second")
+
+	output := buf.String()
+	if strings.Contains(output, "dropped") || !strings.Contains(output, "first") || strings.Contains(output, "second") {
+		t.Errorf("output = %q, want only the first log after the level change", output)
+	}
+}
+
+type stringerFunc func() string
+
+func (f stringerFunc) String() string { return f() }
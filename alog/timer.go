@@ -0,0 +1,48 @@
+package alog
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// timerLevel is the level at which the logs of Timer are written.
+var timerLevel atomic.Int64
+
+func init() {
+	timerLevel.Store(int64(LevelInfo))
+}
+
+// SetTimerLevel configures the level at which Timer logs the elapsed durations.
+//
+// The default is LevelInfo.
+func SetTimerLevel(level LogLevel) {
+	timerLevel.Store(int64(level))
+}
+
+// Timer starts a timer and returns a function which logs the duration elapsed since the call to Timer.
+//
+// The duration is written to the numeric durationMs field, in milliseconds, and the name to the timer field, which
+// allows Cloud Logging to chart the latencies using a log-based distribution metric. Typically, the returned function
+// is deferred:
+//
+//	defer alog.Timer(ctx, "spanner.read")()
+func Timer(ctx context.Context, name string) func() {
+	start := time.Now()
+	return func() {
+		level := LogLevel(timerLevel.Load())
+		if loggingLevel > level {
+			return
+		}
+		elapsed := time.Since(start)
+		durationMs := float64(elapsed) / float64(time.Millisecond)
+		(&entry{
+			Message:    fmt.Sprintf("%s took %s", name, elapsed),
+			Level:      level,
+			Ctx:        ctx,
+			Timer:      name,
+			DurationMs: &durationMs,
+		}).Output()
+	}
+}
@@ -0,0 +1,44 @@
+package alog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	loggingEnvironment = EnvironmentGoogle
+	SetTimerLevel(LevelNotice)
+	t.Cleanup(func() {
+		ResetRoutes()
+		SetLoggingEnvironment(EnvironmentLocal)
+		SetTimerLevel(LevelInfo)
+	})
+
+	func() {
+		defer Timer(context.Background(), "spanner.read")()
+		time.Sleep(10 * time.Millisecond)
+	}()
+
+	var got struct {
+		Severity   string   `json:"severity"`
+		Timer      string   `json:"timer"`
+		DurationMs *float64 `json:"durationMs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, output = %s", err, buf.String())
+	}
+	if got.Timer != "spanner.read" {
+		t.Errorf("timer = %q, want %q", got.Timer, "spanner.read")
+	}
+	if got.DurationMs == nil || *got.DurationMs < 10 {
+		t.Errorf("durationMs = %v, want at least 10", got.DurationMs)
+	}
+	if got.Severity != LevelNotice.String() {
+		t.Errorf("severity = %q, want %q", got.Severity, LevelNotice)
+	}
+}
@@ -0,0 +1,122 @@
+package alog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	// projectID is the Google Cloud project of the traces, see SetProjectID.
+	projectID string
+	// traceExtractor, if set, is used to extract the trace from the context before the incoming headers.
+	traceExtractor TraceExtractor
+)
+
+// TraceExtractor returns the trace and span IDs, in hexadecimal, and the sampling decision of the trace in the
+// provided context. An empty trace ID indicates that the context has no trace.
+type TraceExtractor func(ctx context.Context) (traceID, spanID string, sampled bool)
+
+// SetProjectID sets the Google Cloud project used to build the fully-qualified trace name of the log entries,
+// projects/{projectID}/traces/{traceID}, which Cloud Logging requires to correlate the logs with Cloud Trace.
+//
+// It defaults to the ALIS_OS_PROJECT environment variable.
+func SetProjectID(id string) {
+	projectID = id
+}
+
+// SetTraceExtractor registers a function to extract the trace from the context passed to the logging functions, for
+// example from an OpenTelemetry span:
+//
+//	alog.SetTraceExtractor(func(ctx context.Context) (string, string, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		if !sc.IsValid() {
+//			return "", "", false
+//		}
+//		return sc.TraceID().String(), sc.SpanID().String(), sc.IsSampled()
+//	})
+//
+// Without an extractor, or if it finds no trace, the trace is read from the X-Cloud-Trace-Context or the W3C
+// traceparent header of the incoming gRPC metadata.
+func SetTraceExtractor(extractor TraceExtractor) {
+	traceExtractor = extractor
+}
+
+// traceContext identifies the trace and span a log entry was written in.
+type traceContext struct {
+	traceID string
+	spanID  string
+	sampled bool
+}
+
+// name returns the fully-qualified name of the trace expected by Cloud Logging.
+func (t traceContext) name() string {
+	project := projectID
+	if project == "" {
+		project = os.Getenv("ALIS_OS_PROJECT")
+	}
+	return fmt.Sprintf("projects/%s/traces/%s", project, t.traceID)
+}
+
+// getTraceContext retrieves the trace from the provided context.
+// Returns false if the context has no trace.
+func getTraceContext(ctx context.Context) (traceContext, bool) {
+	if ctx == nil {
+		return traceContext{}, false
+	}
+	if traceExtractor != nil {
+		if traceID, spanID, sampled := traceExtractor(ctx); traceID != "" {
+			return traceContext{traceID: traceID, spanID: spanID, sampled: sampled}, true
+		}
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return traceContext{}, false
+	}
+	if headers := md.Get("x-cloud-trace-context"); len(headers) > 0 {
+		if tc, ok := parseCloudTraceContext(headers[0]); ok {
+			return tc, true
+		}
+	}
+	if headers := md.Get("traceparent"); len(headers) > 0 {
+		if tc, ok := parseTraceparent(headers[0]); ok {
+			return tc, true
+		}
+	}
+	return traceContext{}, false
+}
+
+// parseCloudTraceContext parses a X-Cloud-Trace-Context header, formatted as TRACE_ID/SPAN_ID;o=OPTIONS where the span
+// ID is a decimal number and both the span ID and the options are optional.
+func parseCloudTraceContext(header string) (traceContext, bool) {
+	traceID, rest, _ := strings.Cut(header, "/")
+	if traceID == "" {
+		return traceContext{}, false
+	}
+	tc := traceContext{traceID: traceID}
+	spanID, options, _ := strings.Cut(rest, ";")
+	if id, err := strconv.ParseUint(spanID, 10, 64); err == nil && id != 0 {
+		// Cloud Logging expects the span ID in hexadecimal.
+		tc.spanID = fmt.Sprintf("%016x", id)
+	}
+	tc.sampled = options == "o=1"
+	return tc, true
+}
+
+// parseTraceparent parses a W3C traceparent header, formatted as VERSION-TRACE_ID-SPAN_ID-FLAGS, see
+// https://www.w3.org/TR/trace-context/#traceparent-header.
+func parseTraceparent(header string) (traceContext, bool) {
+	parts := strings.Split(header, "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return traceContext{}, false
+	}
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return traceContext{}, false
+	}
+	return traceContext{traceID: parts[1], spanID: parts[2], sampled: flags&1 == 1}, true
+}
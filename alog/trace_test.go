@@ -0,0 +1,120 @@
+package alog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func Test_parseCloudTraceContext(t *testing.T) {
+	tests := []struct {
+		header string
+		want   traceContext
+		wantOk bool
+	}{
+		{header: "105445aa7843bc8bf206b12000100000/1;o=1", want: traceContext{traceID: "105445aa7843bc8bf206b12000100000", spanID: "0000000000000001", sampled: true}, wantOk: true},
+		{header: "105445aa7843bc8bf206b12000100000/2000;o=0", want: traceContext{traceID: "105445aa7843bc8bf206b12000100000", spanID: "00000000000007d0"}, wantOk: true},
+		{header: "105445aa7843bc8bf206b12000100000", want: traceContext{traceID: "105445aa7843bc8bf206b12000100000"}, wantOk: true},
+		{header: "/1;o=1", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, ok := parseCloudTraceContext(tt.header)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("parseCloudTraceContext() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_parseTraceparent(t *testing.T) {
+	tests := []struct {
+		header string
+		want   traceContext
+		wantOk bool
+	}{
+		{header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", want: traceContext{traceID: "4bf92f3577b34da6a3ce929d0e0e4736", spanID: "00f067aa0ba902b7", sampled: true}, wantOk: true},
+		{header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", want: traceContext{traceID: "4bf92f3577b34da6a3ce929d0e0e4736", spanID: "00f067aa0ba902b7"}, wantOk: true},
+		{header: "00-4bf92f3577b34da6-00f067aa0ba902b7-01", wantOk: false},
+		{header: "garbage", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, ok := parseTraceparent(tt.header)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("parseTraceparent() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestInfo_Trace(t *testing.T) {
+	var buf bytes.Buffer
+	AddRoute(LevelDebug, LevelEmergency, &buf)
+	loggingEnvironment = EnvironmentGoogle
+	SetProjectID("my-project")
+	t.Cleanup(func() {
+		ResetRoutes()
+		SetLoggingEnvironment(EnvironmentLocal)
+		SetProjectID("")
+		SetTraceExtractor(nil)
+	})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		extractor TraceExtractor
+		want      map[string]any
+	}{
+		{
+			name: "cloud trace header",
+			ctx:  metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-cloud-trace-context", "105445aa7843bc8bf206b12000100000/1;o=1")),
+			want: map[string]any{
+				"logging.googleapis.com/trace":         "projects/my-project/traces/105445aa7843bc8bf206b12000100000",
+				"logging.googleapis.com/spanId":        "0000000000000001",
+				"logging.googleapis.com/trace_sampled": true,
+			},
+		},
+		{
+			name: "extractor",
+			ctx:  context.Background(),
+			extractor: func(ctx context.Context) (string, string, bool) {
+				return "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", false
+			},
+			want: map[string]any{
+				"logging.googleapis.com/trace":         "projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736",
+				"logging.googleapis.com/spanId":        "00f067aa0ba902b7",
+				"logging.googleapis.com/trace_sampled": nil,
+			},
+		},
+		{
+			name: "no trace",
+			ctx:  context.Background(),
+			want: map[string]any{
+				"logging.googleapis.com/trace":         nil,
+				"logging.googleapis.com/spanId":        nil,
+				"logging.googleapis.com/trace_sampled": nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			SetTraceExtractor(tt.extractor)
+			Info(tt.ctx, "hello")
+
+			var got map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v, output = %s", err, buf.String())
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %v, want %v", k, got[k], v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+// blockingDial holds the dial timeout configured using WithBlockingDial.
+type blockingDial struct {
+	timeout time.Duration
+}
+
+/*
+WithBlockingDial makes Dial wait for the connection to the target to be established before returning it, so that
+an unreachable host is reported up front rather than on the first RPC.
+
+If the connection is not ready within the timeout, or before the context of Dial is done, the connection is closed
+and Dial returns an Unavailable error. A timeout of zero or less waits as long as the context of Dial allows,
+i.e. until its deadline if it has one.
+
+By default Dial does not block, and the connection is only established on the first RPC.
+
+Example:
+
+	conn, err := client.Dial(ctx, host, client.WithBlockingDial(10*time.Second))
+*/
+func WithBlockingDial(timeout time.Duration) ConnOption {
+	return func(o *ConnOptions) {
+		o.blockingDial = &blockingDial{timeout: timeout}
+	}
+}
+
+// waitForReady connects the connection and waits for it to be ready, returning an Unavailable error if it is not.
+func waitForReady(ctx context.Context, conn *grpc.ClientConn, host string, dial blockingDial) error {
+	if dial.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, dial.timeout)
+		defer cancel()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return status.Errorf(codes.Unavailable, "unable to connect to %s: %s", host, err)
+	}
+	conn.Connect()
+	for state := conn.GetState(); state != connectivity.Ready; state = conn.GetState() {
+		if state == connectivity.Idle {
+			conn.Connect()
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return status.Errorf(codes.Unavailable, "unable to connect to %s, connection is %s: %s", host, state, ctx.Err())
+		}
+	}
+
+	return nil
+}
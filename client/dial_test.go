@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestWithBlockingDial(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	reachable := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})
+	unreachable := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	})
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		dialer   grpc.DialOption
+		timeout  time.Duration
+		wantCode codes.Code
+	}{
+		{name: "reachable", ctx: context.Background(), dialer: reachable, timeout: time.Second, wantCode: codes.OK},
+		{name: "unreachable", ctx: context.Background(), dialer: unreachable, timeout: 100 * time.Millisecond, wantCode: codes.Unavailable},
+		{name: "canceled context", ctx: canceled, dialer: reachable, timeout: 0, wantCode: codes.Unavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := Dial(tt.ctx, "localhost:8080", WithInsecure(), WithDialOptions(tt.dialer), WithBlockingDial(tt.timeout))
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("Dial() error = %v, want code %v", err, tt.wantCode)
+			}
+			if err == nil {
+				conn.Close()
+			} else if conn != nil {
+				t.Errorf("Dial() conn = %v, want nil on error", conn)
+			}
+		})
+	}
+
+	// Without the option, Dial does not connect to the host.
+	conn, err := Dial(context.Background(), "localhost:8080", WithInsecure(), WithDialOptions(unreachable))
+	if err != nil {
+		t.Fatalf("Dial() error = %v, want no error without WithBlockingDial", err)
+	}
+	conn.Close()
+}
@@ -6,10 +6,10 @@ import (
 	"log"
 )
 
-func ExampleNewConn() {
+func ExampleDial() {
 
 	ctx := context.Background()
-	conn, err := client.NewConn(ctx, "cloudrun-service.app:443", false)
+	conn, err := client.Dial(ctx, "cloudrun-service.app:443")
 	if err != nil {
 		log.Println(err)
 	}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// healthCheck holds the health check configured using WithHealthCheck.
+type healthCheck struct {
+	service string
+	timeout time.Duration
+}
+
+/*
+WithHealthCheck verifies that the target of Dial is SERVING, using the standard grpc.health.v1.Health/Check
+method, before the connection is returned. Use an empty service name to check the overall health of the server.
+
+If the check fails, or does not complete within the timeout, the connection is closed and Dial returns an
+Unavailable error. A timeout of zero or less waits as long as the context of Dial allows.
+
+No health check is performed by default.
+
+Example:
+
+	conn, err := client.Dial(ctx, host, client.WithHealthCheck("", 5*time.Second))
+*/
+func WithHealthCheck(serviceName string, timeout time.Duration) ConnOption {
+	return func(o *ConnOptions) {
+		o.healthCheck = &healthCheck{service: serviceName, timeout: timeout}
+	}
+}
+
+// checkHealth calls the health service of the target and returns an Unavailable error if it is not SERVING.
+func checkHealth(ctx context.Context, conn *grpc.ClientConn, host string, check healthCheck) error {
+	if check.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, check.timeout)
+		defer cancel()
+	}
+
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: check.service}, grpc.WaitForReady(true))
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "health check of %s failed: %s", host, err)
+	}
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return status.Errorf(codes.Unavailable, "health check of %s failed: service %q is %s", host, check.service, res.GetStatus())
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestWithHealthCheck(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("serving", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("not-serving", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	tests := []struct {
+		name     string
+		service  string
+		wantCode codes.Code
+	}{
+		{name: "serving", service: "serving", wantCode: codes.OK},
+		{name: "not serving", service: "not-serving", wantCode: codes.Unavailable},
+		{name: "unknown service", service: "unknown", wantCode: codes.Unavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := Dial(context.Background(), "localhost:8080", WithInsecure(),
+				WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+					return lis.DialContext(ctx)
+				})),
+				WithHealthCheck(tt.service, time.Second),
+			)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("Dial() error = %v, want code %v", err, tt.wantCode)
+			}
+			if err == nil {
+				conn.Close()
+			} else if conn != nil {
+				t.Errorf("Dial() conn = %v, want nil on error", conn)
+			}
+		})
+	}
+}
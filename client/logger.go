@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+/*
+WithLogger traces the lifecycle of connections created using Dial, by logging the dial target, the credential
+mode, ID token refreshes and connectivity state transitions via the provided function.
+
+Nothing is logged by default.
+
+Example:
+
+	conn, err := client.Dial(ctx, host, client.WithLogger(func(format string, args ...any) {
+		alog.Debugf(ctx, format, args...)
+	}))
+*/
+func WithLogger(logf func(format string, args ...any)) ConnOption {
+	return func(o *ConnOptions) {
+		o.logf = logf
+	}
+}
+
+// logger returns the logging function set using WithLogger, or a no-op and false if there is none.
+func (o *ConnOptions) logger() (func(format string, args ...any), bool) {
+	if o.logf == nil {
+		return func(format string, args ...any) {}, false
+	}
+	return o.logf, true
+}
+
+// logStateTransitions logs the connectivity state transitions of the connection until it is closed.
+func logStateTransitions(conn *grpc.ClientConn, host string, logf func(format string, args ...any)) {
+	state := conn.GetState()
+	logf("client: connection to %s is %s", host, state)
+	for state != connectivity.Shutdown {
+		if !conn.WaitForStateChange(context.Background(), state) {
+			return
+		}
+		state = conn.GetState()
+		logf("client: connection to %s is %s", host, state)
+	}
+}
+
+// loggingTokenSource logs whenever the underlying token source returns a new token, i.e. after a refresh.
+type loggingTokenSource struct {
+	oauth2.TokenSource
+	audience string
+	logf     func(format string, args ...any)
+
+	mu     sync.Mutex
+	expiry time.Time
+}
+
+// Token returns the token of the underlying token source, logging refreshes and failures.
+func (s *loggingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.TokenSource.Token()
+	if err != nil {
+		s.logf("client: unable to retrieve an ID token for %s: %v", s.audience, err)
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !token.Expiry.Equal(s.expiry) {
+		s.expiry = token.Expiry
+		s.logf("client: retrieved a new ID token for %s, expiring at %s", s.audience, token.Expiry.Format(time.RFC3339))
+	}
+
+	return token, nil
+}
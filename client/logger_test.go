@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestWithLogger(t *testing.T) {
+	lis := newTestServer(t)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var logs []string
+	conn, err := Dial(ctx, "localhost:8080", WithInsecure(),
+		WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		})),
+		WithLogger(func(format string, args ...any) {
+			mu.Lock()
+			defer mu.Unlock()
+			logs = append(logs, fmt.Sprintf(format, args...))
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(logs) == 0 || !strings.Contains(logs[0], "dialing localhost:8080 using insecure credentials") {
+		t.Errorf("logs = %q, want the dial to be logged first", logs)
+	}
+}
+
+func TestWithLogger_NotConfigured(t *testing.T) {
+	if _, ok := newConnOptions([]ConnOption{WithDialOptions(grpc.WithAuthority("localhost"))}).logger(); ok {
+		t.Errorf("logger() ok = true, want false without WithLogger")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	"golang.org/x/oauth2"
 	"google.golang.org/api/idtoken"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
@@ -23,9 +24,150 @@ type grpcTokenSource struct {
 }
 
 /*
-NewConn creates a new gRPC connection.
+ConnOptions holds the configuration of the connections created using Dial, set using the ConnOption functions.
+*/
+type ConnOptions struct {
+	insecure          bool
+	audience          string
+	dialOptions       []grpc.DialOption
+	retry             []grpc_retry.CallOption
+	clientCerts       []tls.Certificate
+	rootCAs           *x509.CertPool
+	logf              func(format string, args ...any)
+	blockingDial      *blockingDial
+	healthCheck       *healthCheck
+	tokenErrorHandler func(error)
+}
+
+// ConnOption configures the connections created using Dial.
+type ConnOption func(*ConnOptions)
+
+// newConnOptions returns the configuration set using the provided options.
+func newConnOptions(opts []ConnOption) *ConnOptions {
+	options := &ConnOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+/*
+WithInsecure dials the host without TLS and ID tokens, typically when testing your gRPC server locally.
+*/
+func WithInsecure() ConnOption {
+	return func(o *ConnOptions) {
+		o.insecure = true
+	}
+}
+
+/*
+WithAudience sets the audience of the ID tokens sent with each request, which defaults to the URL of the host, i.e.
+https://{domain}. Use it for services fronted by a custom domain, an API Gateway or a load balancer, whose expected
+audience differs from the host.
+
+The audience must be an absolute URL, Dial returns an InvalidArgument error otherwise, rather than the RPCs failing
+with Unauthenticated. It is ignored when used with WithInsecure or WithClientCertificate.
+
+Example:
+
+	conn, err := client.Dial(ctx, "api.example.com:443", client.WithAudience("https://my-service-abcdef-ew.a.run.app"))
+*/
+func WithAudience(audience string) ConnOption {
+	return func(o *ConnOptions) {
+		o.audience = audience
+	}
+}
+
+/*
+WithClientCertificate authenticates the connection using mutual TLS with the provided client certificate, instead of
+sending ID tokens with each request. Use it for backends protected by mTLS rather than Cloud Run IAM.
+
+Example:
+
+	cert, err := tls.LoadX509KeyPair("client.crt", "client.key")
+	if err != nil {
+		return err
+	}
+	conn, err := client.Dial(ctx, "internal.example.com:443", client.WithClientCertificate(cert))
+*/
+func WithClientCertificate(cert tls.Certificate) ConnOption {
+	return func(o *ConnOptions) {
+		o.clientCerts = append(o.clientCerts, cert)
+	}
+}
+
+/*
+WithRootCAs verifies the certificate of the host using the provided certificate authorities, instead of the system
+root CAs. Use it for backends whose certificates are issued by a private CA.
+*/
+func WithRootCAs(pool *x509.CertPool) ConnOption {
+	return func(o *ConnOptions) {
+		o.rootCAs = pool
+	}
+}
+
+/*
+WithDialOptions adds gRPC dial options. The ConnOptions wrapped using AsDialOption are applied as if passed to Dial
+directly.
+*/
+func WithDialOptions(opts ...grpc.DialOption) ConnOption {
+	return func(o *ConnOptions) {
+		for _, opt := range opts {
+			if wrapped, ok := opt.(connOptionsDialOption); ok {
+				for _, connOpt := range wrapped.opts {
+					connOpt(o)
+				}
+				continue
+			}
+			o.dialOptions = append(o.dialOptions, opt)
+		}
+	}
+}
+
+// connOptionsDialOption carries the ConnOptions wrapped using AsDialOption. It does not alter the dial configuration
+// itself.
+type connOptionsDialOption struct {
+	grpc.EmptyDialOption
+	opts []ConnOption
+}
+
+/*
+AsDialOption wraps the provided ConnOptions, such as WithLogger or WithHealthCheck, as a grpc.DialOption, so that they
+can be passed to the functions which predate Dial and only accept dial options, e.g. NewConn or NewManagedConn.
+The options are ignored when passed to grpc.Dial directly.
+
+Example:
+
+	conn, err := client.NewManagedConn(ctx, host, false, client.AsDialOption(client.WithLogger(logf)))
+*/
+func AsDialOption(opts ...ConnOption) grpc.DialOption {
+	return connOptionsDialOption{opts: opts}
+}
+
+/*
+WithRetry retries the unary RPCs using the provided retry options, for example on temporary TCP connection resets,
+which are common when connecting to Cloud Run services.
+
+Without options, Unavailable errors are retried up to 5 times with an exponential backoff starting at 100ms.
+*/
+func WithRetry(opts ...grpc_retry.CallOption) ConnOption {
+	return func(o *ConnOptions) {
+		if len(opts) == 0 {
+			opts = []grpc_retry.CallOption{
+				grpc_retry.WithBackoff(grpc_retry.BackoffExponential(100 * time.Millisecond)),
+				grpc_retry.WithCodes(codes.Unavailable),
+				grpc_retry.WithMax(5),
+			}
+		}
+		o.retry = opts
+	}
+}
+
+/*
+Dial creates a new gRPC connection.
   - host should be of the form domain:port, for example: `your-app-on-cloudrun-abcdef-ew.a.run.app:443`
-  - set insecure to `true` when testing your gRPC server locally.
+  - use WithInsecure when testing your gRPC server locally.
+  - use WithClientCertificate for backends protected by mutual TLS rather than ID tokens.
 
 This approach was inspired by the example provided on the following URL:
 https://cloud.google.com/run/docs/samples/cloudrun-grpc-request-auth.
@@ -36,63 +178,206 @@ gRPC request.
 
 Tokens generally have a one-hour expiration time, and the TokenSource logic caches and automatically
 refreshes the token upon expiration. This greatly simplifies token recycling within your service.
+
+The connection is established lazily on the first RPC, use WithBlockingDial to establish it up front instead.
+
+Example:
+
+	conn, err := client.Dial(ctx, host, client.WithRetry(), client.WithBlockingDial(10*time.Second))
 */
-func NewConn(ctx context.Context, host string, insecure bool, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+func Dial(ctx context.Context, host string, opts ...ConnOption) (*grpc.ClientConn, error) {
+	options := newConnOptions(opts)
+
 	// Validate the host argument using a regular expression to ensure it matches the required format
 	// of "hostname:port".
 	err := validateArgument("host", host, `^[a-zA-Z0-9.-]+:\d+$`)
 	if err != nil {
 		return nil, err
 	}
+	if options.usesIDTokens() {
+		if _, err := options.tokenAudience(host); err != nil {
+			return nil, err
+		}
+	}
 
+	dialOpts := append([]grpc.DialOption{}, options.dialOptions...)
 	if host != "" {
-		opts = append(opts, grpc.WithAuthority(host))
+		dialOpts = append(dialOpts, grpc.WithAuthority(host))
+	}
+	if options.retry != nil {
+		dialOpts = append(dialOpts, grpc.WithUnaryInterceptor(grpc_retry.UnaryClientInterceptor(options.retry...)))
 	}
 
-	if insecure {
+	logf, logging := options.logger()
+	if options.insecure {
+		logf("client: dialing %s using insecure credentials", host)
+
 		// If the connection is insecure, add an insecure transport credentials option to the opts array.
-		opts = append(opts, grpc.WithTransportCredentials(insecureGrpc.NewCredentials()))
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecureGrpc.NewCredentials()))
+	} else if len(options.clientCerts) > 0 {
+		logf("client: dialing %s using mutual TLS", host)
+
+		// With mutual TLS, the client certificate authenticates the requests instead of ID tokens.
+		tlsConfig, err := options.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	} else {
-		// If the connection is secure, get the system root CAs and create a transport credentials option
-		// using TLS with the system root CAs.
-		systemRoots, err := x509.SystemCertPool()
+		// If the connection is secure, create a transport credentials option using TLS with the root CAs, the system
+		// ones by default.
+		tlsConfig, err := options.tlsConfig()
 		if err != nil {
 			return nil, err
 		}
-		cred := credentials.NewTLS(&tls.Config{RootCAs: systemRoots})
-		opts = append(opts, grpc.WithTransportCredentials(cred))
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 
 		// use a tokenSource to automatically inject tokens with each underlying client request
-		// With Cloud Run, the audience is the URL of the service you are invoking.
-		audience := "https://" + strings.Split(host, ":")[0]
-		tokenSource, err := idtoken.NewTokenSource(ctx, audience, option.WithAudiences(audience))
+		audience, err := options.tokenAudience(host)
 		if err != nil {
-			return nil, status.Errorf(
-				codes.Unauthenticated,
-				"NewTokenSource: %s", err,
-			)
+			return nil, err
+		}
+		tokenSource, err := newTokenSource(ctx, host, audience, options)
+		if err != nil {
+			return nil, err
 		}
 		// Add a per-RPC credentials option to the opts array using a grpcTokenSource instance created
 		// with an oauth.TokenSource instance created from the tokenSource.
-
-		opts = append(opts, grpc.WithPerRPCCredentials(grpcTokenSource{
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(grpcTokenSource{
 			TokenSource: oauth.TokenSource{
 				TokenSource: tokenSource,
 			},
 		}))
 	}
 
-	return grpc.Dial(host, opts...)
+	conn, err := grpc.Dial(host, dialOpts...)
+	if err != nil {
+		logf("client: unable to dial %s: %v", host, err)
+		return nil, err
+	}
+	if logging {
+		go logStateTransitions(conn, host, logf)
+	}
+	if options.blockingDial != nil {
+		if err := waitForReady(ctx, conn, host, *options.blockingDial); err != nil {
+			logf("client: %v", err)
+			conn.Close()
+			return nil, err
+		}
+	}
+	if options.healthCheck != nil {
+		if err := checkHealth(ctx, conn, host, *options.healthCheck); err != nil {
+			logf("client: %v", err)
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+/*
+NewConn creates a new gRPC connection, see Dial.
+  - host should be of the form domain:port, for example: `your-app-on-cloudrun-abcdef-ew.a.run.app:443`
+  - set insecure to `true` when testing your gRPC server locally.
+
+Deprecated: Use Dial, with WithInsecure and WithDialOptions.
+*/
+func NewConn(ctx context.Context, host string, insecure bool, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return Dial(ctx, host, connOptions(insecure, opts)...)
+}
+
+// rootCertPool returns the root CAs set using WithRootCAs, or the system root CAs by default.
+func (o *ConnOptions) rootCertPool() (*x509.CertPool, error) {
+	if o.rootCAs != nil {
+		return o.rootCAs, nil
+	}
+	return x509.SystemCertPool()
 }
 
-// Does the same as NewConn, but retries on temporary TCP connection resets, which is common when connecting to Cloud Run services.
+// tlsConfig returns the TLS configuration of the connections, using the root CAs and client certificates set using
+// WithRootCAs and WithClientCertificate.
+func (o *ConnOptions) tlsConfig() (*tls.Config, error) {
+	rootCAs, err := o.rootCertPool()
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{RootCAs: rootCAs, Certificates: o.clientCerts}, nil
+}
+
+// usesIDTokens reports whether the requests are authenticated using ID tokens, i.e. neither WithInsecure nor
+// WithClientCertificate are used.
+func (o *ConnOptions) usesIDTokens() bool {
+	return !o.insecure && len(o.clientCerts) == 0
+}
+
+// tokenAudience returns the audience of the ID tokens sent to the provided host, set using WithAudience or the default
+// one for the host, or an InvalidArgument error if the audience set is invalid.
+func (o *ConnOptions) tokenAudience(host string) (string, error) {
+	if o.audience == "" {
+		return defaultAudience(host), nil
+	}
+	if err := validateAudience(o.audience); err != nil {
+		return "", err
+	}
+	return o.audience, nil
+}
+
+// connOptions converts the arguments of the functions predating Dial to their ConnOption equivalent.
+func connOptions(insecure bool, opts []grpc.DialOption) []ConnOption {
+	connOpts := []ConnOption{WithDialOptions(opts...)}
+	if insecure {
+		connOpts = append(connOpts, WithInsecure())
+	}
+	return connOpts
+}
+
+// defaultAudience returns the audience of the ID tokens for the provided host, which with Cloud Run is the URL of the
+// service you are invoking.
+func defaultAudience(host string) string {
+	return "https://" + strings.Split(host, ":")[0]
+}
+
+/*
+newTokenSource creates the ID token source used to authenticate the requests made to the provided host for the
+provided audience, wrapped with the error handling and logging configured using the provided options.
+*/
+func newTokenSource(ctx context.Context, host, audience string, options *ConnOptions) (oauth2.TokenSource, error) {
+	logf, logging := options.logger()
+	logf("client: dialing %s using TLS and ID tokens for audience %s", host, audience)
+	tokenSource, err := idtoken.NewTokenSource(ctx, audience, option.WithAudiences(audience))
+	if err != nil {
+		logf("client: unable to create an ID token source for %s: %v", audience, err)
+		return nil, status.Errorf(
+			codes.Unauthenticated,
+			"NewTokenSource: %s", err,
+		)
+	}
+
+	if options.tokenErrorHandler != nil {
+		tokenSource = &errorHandlingTokenSource{
+			TokenSource: tokenSource,
+			audience:    audience,
+			handler:     options.tokenErrorHandler,
+		}
+	}
+	if logging {
+		tokenSource = &loggingTokenSource{
+			TokenSource: tokenSource,
+			audience:    audience,
+			logf:        logf,
+		}
+	}
+
+	return tokenSource, nil
+}
+
+/*
+NewConnWithRetry does the same as NewConn, but retries on temporary TCP connection resets, which is common when
+connecting to Cloud Run services.
+
+Deprecated: Use Dial with WithRetry.
+*/
 func NewConnWithRetry(ctx context.Context, host string, insecure bool, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
-	retryOpts := []grpc_retry.CallOption{
-		grpc_retry.WithBackoff(grpc_retry.BackoffExponential(100 * time.Millisecond)),
-		grpc_retry.WithCodes(codes.Unavailable),
-		grpc_retry.WithMax(5),
-	}
-	retryInterceptorForTcpConnectionResets := grpc.WithUnaryInterceptor(grpc_retry.UnaryClientInterceptor(retryOpts...))
-	opts = append(opts, retryInterceptorForTcpConnectionResets)
-	return NewConn(ctx, host, insecure, opts...)
+	return Dial(ctx, host, append(connOptions(insecure, opts), WithRetry())...)
 }
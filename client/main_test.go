@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// flakyHealthServer fails the first calls with Unavailable, as a Cloud Run service resetting connections would.
+type flakyHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	mu       sync.Mutex
+	failures int
+}
+
+func (s *flakyHealthServer) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return nil, status.Error(codes.Unavailable, "connection reset")
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func TestDial_WithRetry(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	healthServer := &flakyHealthServer{}
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+	dialer := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})
+
+	tests := []struct {
+		name     string
+		opts     []ConnOption
+		wantCode codes.Code
+	}{
+		{name: "without retry", opts: nil, wantCode: codes.Unavailable},
+		{name: "with retry", opts: []ConnOption{WithRetry()}, wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthServer.mu.Lock()
+			healthServer.failures = 2
+			healthServer.mu.Unlock()
+
+			conn, err := Dial(context.Background(), "localhost:8080", append(tt.opts, WithInsecure(), WithDialOptions(dialer))...)
+			if err != nil {
+				t.Fatalf("Dial() error = %v", err)
+			}
+			defer conn.Close()
+
+			_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("Check() error = %v, want code %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestDial_InvalidHost(t *testing.T) {
+	if _, err := Dial(context.Background(), "localhost", WithInsecure()); err == nil {
+		t.Errorf("Dial() error = nil, want an error for a host without port")
+	}
+}
+
+func TestDial_InvalidAudience(t *testing.T) {
+	_, err := Dial(context.Background(), "api.example.com:443", WithAudience("my-service.a.run.app"))
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("Dial() error = %v, want code %v", err, codes.InvalidArgument)
+	}
+}
+
+func Test_validateAudience(t *testing.T) {
+	tests := []struct {
+		audience string
+		wantErr  bool
+	}{
+		{audience: "https://my-service-abcdef-ew.a.run.app", wantErr: false},
+		{audience: "https://my-gateway-abcdef.ew.gateway.dev/v1", wantErr: false},
+		{audience: "http://localhost:8080", wantErr: false},
+		{audience: "my-service-abcdef-ew.a.run.app", wantErr: true},
+		{audience: "grpc://my-service.example.com", wantErr: true},
+		{audience: "https://", wantErr: true},
+		{audience: "://", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.audience, func(t *testing.T) {
+			if err := validateAudience(tt.audience); (err != nil) != tt.wantErr {
+				t.Errorf("validateAudience() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_connOptions(t *testing.T) {
+	for _, insecure := range []bool{false, true} {
+		options := &ConnOptions{}
+		for _, opt := range connOptions(insecure, []grpc.DialOption{grpc.WithAuthority("localhost"), AsDialOption(WithLogger(t.Logf))}) {
+			opt(options)
+		}
+		if options.insecure != insecure {
+			t.Errorf("connOptions(%v) insecure = %v", insecure, options.insecure)
+		}
+		if len(options.dialOptions) != 1 {
+			t.Errorf("connOptions(%v) dial options = %v, want the provided option", insecure, options.dialOptions)
+		}
+		if _, ok := options.logger(); !ok {
+			t.Errorf("connOptions(%v) logger not set, want the option wrapped using AsDialOption to apply", insecure)
+		}
+	}
+}
+
+// newTestCertificate creates a certificate for localhost, signed by the provided parent, or self-signed if nil.
+func newTestCertificate(t *testing.T, parent *tls.Certificate, isCA bool) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	signer, signerKey := template, any(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestDial_WithClientCertificate(t *testing.T) {
+	ca := newTestCertificate(t, nil, true)
+	serverCert := newTestCertificate(t, &ca, false)
+	clientCert := newTestCertificate(t, &ca, false)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+	dialer := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, "tcp", lis.Addr().String())
+	})
+
+	tests := []struct {
+		name     string
+		opts     []ConnOption
+		wantCode codes.Code
+	}{
+		{name: "with client certificate", opts: []ConnOption{WithClientCertificate(clientCert)}, wantCode: codes.OK},
+		{name: "with another client certificate", opts: []ConnOption{WithClientCertificate(newTestCertificate(t, nil, false))}, wantCode: codes.Unavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := Dial(context.Background(), "localhost:443", append(tt.opts, WithRootCAs(pool), WithDialOptions(dialer))...)
+			if err != nil {
+				t.Fatalf("Dial() error = %v", err)
+			}
+			defer conn.Close()
+
+			_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("Check() error = %v, want code %v", err, tt.wantCode)
+			}
+		})
+	}
+}
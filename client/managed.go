@@ -0,0 +1,220 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+// redialInterval is the minimum time between two dials of a ManagedConn, to avoid dialing on every call while the
+// target is down.
+var redialInterval = time.Second
+
+/*
+ManagedConn is a gRPC connection which transparently replaces its underlying connection when it is shut down or in
+TransientFailure, so that long-lived services never hold on to a dead channel.
+
+It implements grpc.ClientConnInterface and can therefore be passed to any generated client constructor in place of a
+*grpc.ClientConn. A ManagedConn is safe for concurrent use by multiple goroutines: the calls in flight keep using the
+connection they started on, while the calls made after a failure use the new connection. The replaced connection is
+only closed once the calls in flight on it are done, i.e. once the unary calls returned and the streams ended.
+*/
+type ManagedConn struct {
+	ctx      context.Context
+	host     string
+	insecure bool
+	opts     []grpc.DialOption
+	logf     func(format string, args ...any)
+
+	mu       sync.RWMutex
+	conn     *trackedConn
+	lastDial time.Time
+	closed   bool
+}
+
+/*
+NewManagedConn creates a ManagedConn to the provided host using Dial. Set insecure to true when testing your gRPC
+server locally, the dial options are passed using WithDialOptions. Use AsDialOption to pass ConnOptions such as
+WithLogger.
+
+The connection is dialed right away and every time it needs to be replaced, using the provided context, which must
+therefore outlive the ManagedConn. Replacements are at least a second apart, so that a target which is down is not
+dialed on every call.
+*/
+func NewManagedConn(ctx context.Context, host string, insecure bool, opts ...grpc.DialOption) (*ManagedConn, error) {
+	connOpts := connOptions(insecure, opts)
+	conn, err := Dial(ctx, host, connOpts...)
+	if err != nil {
+		return nil, err
+	}
+	logf, _ := newConnOptions(connOpts).logger()
+
+	return &ManagedConn{
+		ctx:      ctx,
+		host:     host,
+		insecure: insecure,
+		opts:     opts,
+		logf:     logf,
+		conn:     &trackedConn{ClientConn: conn},
+		lastDial: time.Now(),
+	}, nil
+}
+
+// Invoke performs a unary RPC on the current connection, see grpc.ClientConn.Invoke.
+func (m *ManagedConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	conn, err := m.current()
+	if err != nil {
+		return err
+	}
+	defer conn.release()
+	return conn.Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream begins a streaming RPC on the current connection, see grpc.ClientConn.NewStream.
+func (m *ManagedConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	conn, err := m.current()
+	if err != nil {
+		return nil, err
+	}
+	stream, err := conn.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		conn.release()
+		return nil, err
+	}
+	return newTrackedStream(ctx, stream, desc, conn), nil
+}
+
+// Close closes the current connection. The ManagedConn may not be used after Close.
+func (m *ManagedConn) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	return m.conn.Close()
+}
+
+/*
+current returns the connection to use for a call, replacing it first if it is dead. The call is registered on the
+returned connection, the caller must therefore release it once the call is done.
+*/
+func (m *ManagedConn) current() (*trackedConn, error) {
+	m.mu.RLock()
+	conn, closed := m.conn, m.closed
+	// The call is registered while holding the lock, so that the connection cannot be replaced and closed in between.
+	if !closed && !isDead(conn.GetState()) {
+		conn.acquire()
+		m.mu.RUnlock()
+		return conn, nil
+	}
+	m.mu.RUnlock()
+	if closed {
+		return nil, status.Error(codes.Canceled, "client: the managed connection is closed")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Another call may have replaced the connection, or closed the ManagedConn, in the meantime.
+	if m.closed {
+		return nil, status.Error(codes.Canceled, "client: the managed connection is closed")
+	}
+	if m.conn != conn || time.Since(m.lastDial) < redialInterval {
+		m.conn.acquire()
+		return m.conn, nil
+	}
+
+	m.logf("client: replacing the connection to %s, which is %s", m.host, conn.GetState())
+	m.lastDial = time.Now()
+	newConn, err := Dial(m.ctx, m.host, connOptions(m.insecure, m.opts)...)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "client: unable to replace the connection to %s: %s", m.host, err)
+	}
+	m.conn = &trackedConn{ClientConn: newConn}
+	m.conn.acquire()
+	conn.retire()
+
+	return m.conn, nil
+}
+
+// isDead reports whether a connection in the provided state should be replaced.
+func isDead(state connectivity.State) bool {
+	return state == connectivity.TransientFailure || state == connectivity.Shutdown
+}
+
+// trackedConn is a connection of a ManagedConn, along with the number of calls in flight on it, so that it is only
+// closed once these are done after it was replaced.
+type trackedConn struct {
+	*grpc.ClientConn
+
+	mu      sync.Mutex
+	calls   int
+	retired bool
+}
+
+// acquire registers a call on the connection.
+func (c *trackedConn) acquire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+}
+
+// release marks a call on the connection as done, closing the connection if it was replaced and this was the last call.
+func (c *trackedConn) release() {
+	c.mu.Lock()
+	c.calls--
+	closeConn := c.retired && c.calls == 0
+	c.mu.Unlock()
+	if closeConn {
+		c.Close()
+	}
+}
+
+// retire marks the connection as replaced, closing it right away if no calls are in flight.
+func (c *trackedConn) retire() {
+	c.mu.Lock()
+	c.retired = true
+	closeConn := c.calls == 0
+	c.mu.Unlock()
+	if closeConn {
+		c.Close()
+	}
+}
+
+// trackedStream releases its call on the connection once the stream ended.
+type trackedStream struct {
+	grpc.ClientStream
+	desc    *grpc.StreamDesc
+	release func()
+	stop    func() bool
+}
+
+/*
+newTrackedStream wraps the stream, so that its call on the connection is released once the stream ended, i.e. once
+RecvMsg returned an error, or the response of a stream without server streaming was received, or the context of the
+stream is done.
+*/
+func newTrackedStream(ctx context.Context, stream grpc.ClientStream, desc *grpc.StreamDesc, conn *trackedConn) *trackedStream {
+	s := &trackedStream{
+		ClientStream: stream,
+		desc:         desc,
+		release:      sync.OnceFunc(conn.release),
+	}
+	s.stop = context.AfterFunc(ctx, s.release)
+	return s
+}
+
+// RecvMsg receives a message from the stream, see grpc.ClientStream.RecvMsg.
+func (s *trackedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil || !s.desc.ServerStreams {
+		s.stop()
+		s.release()
+	}
+	return err
+}
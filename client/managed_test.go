@@ -0,0 +1,198 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startHealthServer serves the health service on a new in-memory listener, stopped when the test ends.
+func startHealthServer(t *testing.T) (*bufconn.Listener, *grpc.Server) {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+	return lis, server
+}
+
+func TestManagedConn(t *testing.T) {
+	redialInterval = 0
+	t.Cleanup(func() { redialInterval = time.Second })
+
+	// The dialer connects to the current listener, or fails while the server is down.
+	var mu sync.Mutex
+	lis, server := startHealthServer(t)
+	dialer := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if lis == nil {
+			return nil, errors.New("server is down")
+		}
+		return lis.DialContext(ctx)
+	})
+
+	// restart serves the health service on a new listener, which is stopped when the whole test ends.
+	restart := func() *bufconn.Listener {
+		newLis, _ := startHealthServer(t)
+		return newLis
+	}
+
+	conn, err := NewManagedConn(context.Background(), "localhost:8080", true, dialer)
+	if err != nil {
+		t.Fatalf("NewManagedConn() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	healthClient := healthpb.NewHealthClient(conn)
+
+	check := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+	if err := check(); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	t.Run("transient failure", func(t *testing.T) {
+		// Take the server down and wait for the connection to fail.
+		mu.Lock()
+		lis = nil
+		mu.Unlock()
+		server.Stop()
+		first := conn.conn
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		first.Connect()
+		for state := first.GetState(); state != connectivity.TransientFailure; state = first.GetState() {
+			if state == connectivity.Idle {
+				first.Connect()
+			}
+			if !first.WaitForStateChange(ctx, state) {
+				t.Fatalf("connection state = %s, want %s", state, connectivity.TransientFailure)
+			}
+		}
+
+		// Bring the server back up, the next call should replace the dead connection.
+		newLis := restart()
+		mu.Lock()
+		lis = newLis
+		mu.Unlock()
+		if err := check(); err != nil {
+			t.Fatalf("Check() after recovery error = %v", err)
+		}
+		if conn.conn == first {
+			t.Errorf("connection was not replaced")
+		}
+		if got := first.GetState(); got != connectivity.Shutdown {
+			t.Errorf("replaced connection state = %s, want %s", got, connectivity.Shutdown)
+		}
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		first := conn.conn
+		first.Close()
+		if err := check(); err != nil {
+			t.Fatalf("Check() after shutdown error = %v", err)
+		}
+		if conn.conn == first {
+			t.Errorf("connection was not replaced")
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		conn.Close()
+		if err := check(); err == nil {
+			t.Errorf("Check() after Close() error = nil, want an error")
+		}
+	})
+}
+
+// fakeClientStream is a stream whose RecvMsg returns the provided error.
+type fakeClientStream struct {
+	grpc.ClientStream
+	err error
+}
+
+func (s fakeClientStream) RecvMsg(any) error {
+	return s.err
+}
+
+func TestTrackedConn(t *testing.T) {
+	newConn := func(t *testing.T) *trackedConn {
+		cc, err := grpc.Dial("localhost:8080", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("grpc.Dial() error = %v", err)
+		}
+		t.Cleanup(func() { cc.Close() })
+		return &trackedConn{ClientConn: cc}
+	}
+	// waitForShutdown reports whether the connection is shut down within a second.
+	waitForShutdown := func(conn *trackedConn) bool {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		for state := conn.GetState(); state != connectivity.Shutdown; state = conn.GetState() {
+			if !conn.WaitForStateChange(ctx, state) {
+				return false
+			}
+		}
+		return true
+	}
+
+	t.Run("unary", func(t *testing.T) {
+		conn := newConn(t)
+		conn.acquire()
+		conn.retire()
+		if got := conn.GetState(); got == connectivity.Shutdown {
+			t.Fatalf("state after retire() = %s, want the connection open while a call is in flight", got)
+		}
+		conn.release()
+		if got := conn.GetState(); got != connectivity.Shutdown {
+			t.Errorf("state after release() = %s, want %s", got, connectivity.Shutdown)
+		}
+	})
+
+	t.Run("stream ended", func(t *testing.T) {
+		conn := newConn(t)
+		conn.acquire()
+		stream := newTrackedStream(context.Background(), fakeClientStream{err: io.EOF}, &grpc.StreamDesc{ServerStreams: true}, conn)
+		conn.retire()
+		if got := conn.GetState(); got == connectivity.Shutdown {
+			t.Fatalf("state after retire() = %s, want the connection open while a stream is in flight", got)
+		}
+		_ = stream.RecvMsg(nil)
+		if got := conn.GetState(); got != connectivity.Shutdown {
+			t.Errorf("state after the stream ended = %s, want %s", got, connectivity.Shutdown)
+		}
+	})
+
+	t.Run("stream canceled", func(t *testing.T) {
+		conn := newConn(t)
+		conn.acquire()
+		ctx, cancel := context.WithCancel(context.Background())
+		newTrackedStream(ctx, fakeClientStream{}, &grpc.StreamDesc{ServerStreams: true}, conn)
+		conn.retire()
+		if got := conn.GetState(); got == connectivity.Shutdown {
+			t.Fatalf("state after retire() = %s, want the connection open while a stream is in flight", got)
+		}
+		cancel()
+		if !waitForShutdown(conn) {
+			t.Errorf("state after the stream was canceled = %s, want %s", conn.GetState(), connectivity.Shutdown)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+/*
+Pool caches the connections to multiple hosts, so that the services calling many sibling services share a single
+connection, along with its TLS session and ID token source, per host.
+
+The ID tokens of the pooled connections are refreshed transparently, like those of any connection created using Dial.
+Connections which have not been used for an RPC, nor returned by Conn, for the idle TTL of the pool are closed and
+removed from the pool. Callers should therefore retrieve the connection from the pool whenever they need it, rather
+than holding on to it for longer than the idle TTL.
+
+A Pool is safe for concurrent use by multiple goroutines.
+*/
+type Pool struct {
+	opts    []ConnOption
+	idleTTL time.Duration
+
+	mu     sync.Mutex
+	conns  map[string]*pooledConn
+	closed bool
+	done   chan struct{}
+}
+
+// pooledConn is a connection of a Pool, along with the time it was last used in nanoseconds since the Unix epoch.
+type pooledConn struct {
+	conn     *grpc.ClientConn
+	lastUsed atomic.Int64
+}
+
+// touch records that the connection is being used.
+func (c *pooledConn) touch() {
+	c.lastUsed.Store(now().UnixNano())
+}
+
+// now returns the current time, and is overridden in tests.
+var now = time.Now
+
+// minPoolSweepInterval is the minimum interval between two sweeps of the idle connections of a Pool, so that very
+// short idle TTLs do not turn the sweep into a busy loop.
+const minPoolSweepInterval = time.Second
+
+/*
+NewPool creates a Pool whose connections are created using Dial with the provided options, and are closed after
+being idle for idleTTL. An idleTTL of zero or less keeps the connections open until the Pool is closed.
+The idle connections are looked for every half of idleTTL, but at most once per second.
+
+Example:
+
+	pool := client.NewPool(10*time.Minute, client.WithRetry())
+	defer pool.Close()
+
+	conn, err := pool.Conn(ctx, "iam-users-abcdef-ew.a.run.app:443")
+*/
+func NewPool(idleTTL time.Duration, opts ...ConnOption) *Pool {
+	p := &Pool{
+		opts:    opts,
+		idleTTL: idleTTL,
+		conns:   map[string]*pooledConn{},
+		done:    make(chan struct{}),
+	}
+	if idleTTL > 0 {
+		go p.closeIdleLoop()
+	}
+	return p
+}
+
+/*
+Conn returns the connection to the provided host, creating it using Dial if the pool does not have one yet.
+The connection is owned by the pool and must not be closed by the caller.
+
+The provided context is only used if the connection is created, see Dial.
+*/
+func (p *Pool) Conn(ctx context.Context, host string) (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, status.Error(codes.FailedPrecondition, "client: the pool is closed")
+	}
+	if c, ok := p.conns[host]; ok {
+		c.touch()
+		p.mu.Unlock()
+		return c.conn, nil
+	}
+	p.mu.Unlock()
+
+	// Dial without holding the lock, since dialing may block, e.g. when using WithHealthCheck.
+	c := &pooledConn{}
+	c.touch()
+	track := WithDialOptions(
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			c.touch()
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			c.touch()
+			return streamer(ctx, desc, cc, method, opts...)
+		}),
+	)
+	conn, err := Dial(ctx, host, append(append([]ConnOption{}, p.opts...), track)...)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		conn.Close()
+		return nil, status.Error(codes.FailedPrecondition, "client: the pool is closed")
+	}
+	// Another call may have created a connection to the same host in the meantime.
+	if existing, ok := p.conns[host]; ok {
+		conn.Close()
+		existing.touch()
+		return existing.conn, nil
+	}
+	p.conns[host] = c
+
+	return conn, nil
+}
+
+// Close closes all the connections of the pool. The Pool may not be used after Close.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	close(p.done)
+
+	var errs []error
+	for host, c := range p.conns {
+		if err := c.conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(p.conns, host)
+	}
+	return errors.Join(errs...)
+}
+
+// closeIdleLoop periodically closes the idle connections, until the pool is closed.
+func (p *Pool) closeIdleLoop() {
+	ticker := time.NewTicker(sweepInterval(p.idleTTL))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.closeIdle()
+		}
+	}
+}
+
+// sweepInterval returns the interval at which to look for the connections idle for the provided TTL.
+func sweepInterval(idleTTL time.Duration) time.Duration {
+	return max(idleTTL/2, minPoolSweepInterval)
+}
+
+// closeIdle closes and removes the connections which have not been used for the idle TTL.
+func (p *Pool) closeIdle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cutoff := now().Add(-p.idleTTL).UnixNano()
+	for host, c := range p.conns {
+		if c.lastUsed.Load() <= cutoff {
+			c.conn.Close()
+			delete(p.conns, host)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestPool(t *testing.T, idleTTL time.Duration) *Pool {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	pool := NewPool(idleTTL, WithInsecure(), WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})))
+	t.Cleanup(func() { pool.Close() })
+	return pool
+}
+
+func TestPool_Conn(t *testing.T) {
+	pool := newTestPool(t, 0)
+	ctx := context.Background()
+
+	a, err := pool.Conn(ctx, "service-a:443")
+	if err != nil {
+		t.Fatalf("Conn() error = %v", err)
+	}
+	b, err := pool.Conn(ctx, "service-b:443")
+	if err != nil {
+		t.Fatalf("Conn() error = %v", err)
+	}
+	if a == b {
+		t.Errorf("Conn() returned the same connection for different hosts")
+	}
+	if _, err := healthpb.NewHealthClient(a).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+
+	// Concurrent calls share the connection to the same host.
+	var wg sync.WaitGroup
+	conns := make([]*grpc.ClientConn, 10)
+	for i := range conns {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conns[i], _ = pool.Conn(ctx, "service-c:443")
+		}(i)
+	}
+	wg.Wait()
+	for _, conn := range conns {
+		if conn == nil || conn != conns[0] {
+			t.Fatalf("Conn() returned different connections for the same host")
+		}
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := a.GetState(); got != connectivity.Shutdown {
+		t.Errorf("connection state after Close() = %s, want %s", got, connectivity.Shutdown)
+	}
+	if _, err := pool.Conn(ctx, "service-a:443"); err == nil {
+		t.Errorf("Conn() after Close() error = nil, want an error")
+	}
+}
+
+func TestPool_sweepInterval(t *testing.T) {
+	tests := []struct {
+		idleTTL time.Duration
+		want    time.Duration
+	}{
+		{idleTTL: time.Nanosecond, want: minPoolSweepInterval},
+		{idleTTL: time.Second, want: minPoolSweepInterval},
+		{idleTTL: time.Hour, want: 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := sweepInterval(tt.idleTTL); got != tt.want {
+			t.Errorf("sweepInterval(%v) = %v, want %v", tt.idleTTL, got, tt.want)
+		}
+	}
+
+	// A TTL too short to be halved does not crash the sweep.
+	newTestPool(t, time.Nanosecond)
+}
+
+func TestPool_closeIdle(t *testing.T) {
+	pool := newTestPool(t, time.Hour)
+	ctx := context.Background()
+	current := time.Now()
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = time.Now })
+
+	idle, err := pool.Conn(ctx, "service-idle:443")
+	if err != nil {
+		t.Fatalf("Conn() error = %v", err)
+	}
+	used, err := pool.Conn(ctx, "service-used:443")
+	if err != nil {
+		t.Fatalf("Conn() error = %v", err)
+	}
+
+	// An RPC half way through the TTL keeps the connection open.
+	current = current.Add(40 * time.Minute)
+	if _, err := healthpb.NewHealthClient(used).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	current = current.Add(40 * time.Minute)
+	pool.closeIdle()
+
+	if got := idle.GetState(); got != connectivity.Shutdown {
+		t.Errorf("idle connection state = %s, want %s", got, connectivity.Shutdown)
+	}
+	if got := used.GetState(); got == connectivity.Shutdown {
+		t.Errorf("used connection state = %s, want it open", got)
+	}
+	if conn, err := pool.Conn(ctx, "service-idle:443"); err != nil || conn == idle {
+		t.Errorf("Conn() = %v, %v, want a new connection to replace the idle one", conn, err)
+	}
+}
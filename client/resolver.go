@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TargetEnvPrefix is the prefix of the environment variables read by DefaultResolver.
+const TargetEnvPrefix = "ALIS_TARGET_"
+
+/*
+Resolver maps a logical service name, for example `iam-users`, onto the host to connect to in the current environment,
+along with whether the connection is insecure.
+*/
+type Resolver func(logicalName string) (host string, insecure bool, err error)
+
+var (
+	resolver   Resolver = DefaultResolver
+	resolverMu sync.RWMutex
+
+	// envNameRegex matches the characters of a logical name which are replaced by underscores in env names.
+	envNameRegex = regexp.MustCompile(`[^A-Z0-9]+`)
+)
+
+/*
+SetResolver sets the Resolver used by NewConnFor, typically once during start up.
+Providing nil restores the DefaultResolver.
+
+Example, routing to different hosts per environment:
+
+	client.SetResolver(func(logicalName string) (string, bool, error) {
+		if os.Getenv("ENV") == "dev" {
+			return "localhost:8080", true, nil
+		}
+		return client.DefaultResolver(logicalName)
+	})
+*/
+func SetResolver(r Resolver) {
+	resolverMu.Lock()
+	defer resolverMu.Unlock()
+
+	if r == nil {
+		r = DefaultResolver
+	}
+	resolver = r
+}
+
+/*
+DefaultResolver resolves the logical name using environment variables. The name is upper-cased, with any other
+characters than letters and digits replaced by underscores, for example `iam-users` is resolved using:
+  - ALIS_TARGET_IAM_USERS, holding the host of the form domain:port, for example `localhost:8080`.
+  - ALIS_TARGET_IAM_USERS_INSECURE, set to `true` if the connection is insecure, e.g. when testing locally.
+
+If ALIS_TARGET_IAM_USERS is not set, the logical name is treated as the name of a Cloud Run service and the host is
+composed using RunServiceHost.
+*/
+func DefaultResolver(logicalName string) (string, bool, error) {
+	envName := TargetEnvPrefix + strings.Trim(envNameRegex.ReplaceAllString(strings.ToUpper(logicalName), "_"), "_")
+
+	host := os.Getenv(envName)
+	if host == "" {
+		host, err := RunServiceHost(logicalName)
+		return host, false, err
+	}
+
+	insecure := false
+	if value := os.Getenv(envName + "_INSECURE"); value != "" {
+		var err error
+		insecure, err = strconv.ParseBool(value)
+		if err != nil {
+			return "", false, status.Errorf(codes.FailedPrecondition, "%s_INSECURE env is not a valid boolean: %v", envName, err)
+		}
+	}
+
+	return host, insecure, nil
+}
+
+/*
+NewConnFor creates a new gRPC connection to the service with the provided logical name.
+
+The host, and whether the connection is insecure, are resolved using the Resolver set using SetResolver, which is the
+DefaultResolver unless overridden. The connection is then created using Dial.
+
+Example:
+
+	conn, err := client.NewConnFor(ctx, "iam-users")
+*/
+func NewConnFor(ctx context.Context, logicalName string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	resolverMu.RLock()
+	resolve := resolver
+	resolverMu.RUnlock()
+
+	host, insecure, err := resolve(logicalName)
+	if err != nil {
+		return nil, err
+	}
+
+	return Dial(ctx, host, connOptions(insecure, opts)...)
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDefaultResolver(t *testing.T) {
+	t.Setenv(RunHashEnv, "abcdef-ew.a")
+	t.Setenv("ALIS_TARGET_IAM_USERS", "localhost:8080")
+	t.Setenv("ALIS_TARGET_IAM_USERS_INSECURE", "true")
+
+	tests := []struct {
+		name         string
+		logicalName  string
+		wantHost     string
+		wantInsecure bool
+	}{
+		{
+			name:         "FromEnv",
+			logicalName:  "iam-users",
+			wantHost:     "localhost:8080",
+			wantInsecure: true,
+		},
+		{
+			name:        "CloudRunFallback",
+			logicalName: "iam-groups",
+			wantHost:    "iam-groups-abcdef-ew.a.run.app:443",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, insecure, err := DefaultResolver(tt.logicalName)
+			if err != nil {
+				t.Fatalf("DefaultResolver() error = %v", err)
+			}
+			if host != tt.wantHost || insecure != tt.wantInsecure {
+				t.Errorf("DefaultResolver() = %v, %v, want %v, %v", host, insecure, tt.wantHost, tt.wantInsecure)
+			}
+		})
+	}
+}
+
+func TestNewConnFor(t *testing.T) {
+	var resolved []string
+	SetResolver(func(logicalName string) (string, bool, error) {
+		resolved = append(resolved, logicalName)
+		if logicalName == "unknown" {
+			return "", false, status.Error(codes.NotFound, "unknown service")
+		}
+		return "localhost:8080", true, nil
+	})
+	t.Cleanup(func() { SetResolver(nil) })
+
+	conn, err := NewConnFor(context.Background(), "iam-users")
+	if err != nil {
+		t.Fatalf("NewConnFor() error = %v", err)
+	}
+	defer conn.Close()
+	if got := conn.Target(); got != "localhost:8080" {
+		t.Errorf("NewConnFor() target = %v, want localhost:8080", got)
+	}
+
+	_, err = NewConnFor(context.Background(), "unknown")
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("NewConnFor() error = %v, want code NotFound", err)
+	}
+
+	if len(resolved) != 2 || resolved[0] != "iam-users" || resolved[1] != "unknown" {
+		t.Errorf("resolved = %v, want [iam-users unknown]", resolved)
+	}
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RunHashEnv is the environment variable holding the hash of the Cloud Run service URLs, for example: `abcdef-ew.a`
+const RunHashEnv = "ALIS_RUN_HASH"
+
+// RegionEnv is the environment variable holding the region of the Cloud Run services, for example: `europe-west1`
+const RegionEnv = "ALIS_REGION"
+
+// DefaultRegion is the region of the Cloud Run services used if the ALIS_REGION env is not set.
+const DefaultRegion = "europe-west1"
+
+// runRegionCodes maps the Cloud Run regions onto the codes used in the URLs of their services.
+var runRegionCodes = map[string]string{
+	"asia-east1":              "de",
+	"asia-northeast1":         "an",
+	"asia-southeast1":         "as",
+	"australia-southeast1":    "ts",
+	"europe-north1":           "lz",
+	"europe-west1":            "ew",
+	"europe-west2":            "nw",
+	"europe-west3":            "ey",
+	"europe-west4":            "ez",
+	"northamerica-northeast1": "nn",
+	"southamerica-east1":      "rj",
+	"us-central1":             "uc",
+	"us-east1":                "ue",
+	"us-east4":                "uk",
+	"us-west1":                "uw",
+}
+
+/*
+RunServiceHost composes the host of a Cloud Run service from its name and the ALIS_RUN_HASH env,
+for example: `iam-users-abcdef-ew.a.run.app:443`
+
+The ALIS_RUN_HASH env either holds the full hash, for example `abcdef-ew.a`, or only the hash of the project, for
+example `abcdef`, in which case the code of the region is added using the ALIS_REGION env, which defaults to
+europe-west1.
+
+An error with code FailedPrecondition is returned if the ALIS_RUN_HASH env is not set, or the region is not known.
+*/
+func RunServiceHost(serviceName string) (string, error) {
+	err := validateArgument("serviceName", serviceName, `^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+	if err != nil {
+		return "", err
+	}
+
+	runHash := os.Getenv(RunHashEnv)
+	if runHash == "" {
+		return "", status.Errorf(codes.FailedPrecondition,
+			"%s env is not set, unable to resolve the host of the %s service", RunHashEnv, serviceName)
+	}
+	if !strings.Contains(runHash, ".") {
+		region := os.Getenv(RegionEnv)
+		if region == "" {
+			region = DefaultRegion
+		}
+		regionCode, ok := runRegionCodes[region]
+		if !ok {
+			return "", status.Errorf(codes.FailedPrecondition,
+				"region %s is not known, set the full hash in the %s env instead", region, RunHashEnv)
+		}
+		runHash += "-" + regionCode + ".a"
+	}
+
+	return fmt.Sprintf("%s-%s.run.app:443", serviceName, runHash), nil
+}
+
+/*
+RunServiceConn creates a new gRPC connection to the Cloud Run service with the provided name.
+
+The host is composed using RunServiceHost and the connection is created using Dial with the provided options.
+
+Example:
+
+	conn, err := client.RunServiceConn(ctx, "iam-users", client.WithRetry())
+*/
+func RunServiceConn(ctx context.Context, serviceName string, opts ...ConnOption) (*grpc.ClientConn, error) {
+	host, err := RunServiceHost(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return Dial(ctx, host, opts...)
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRunServiceHost(t *testing.T) {
+	tests := []struct {
+		name        string
+		serviceName string
+		runHash     string
+		region      string
+		want        string
+		wantCode    codes.Code
+	}{
+		{
+			name:        "Valid",
+			serviceName: "iam-users",
+			runHash:     "abcdef-ew.a",
+			want:        "iam-users-abcdef-ew.a.run.app:443",
+			wantCode:    codes.OK,
+		},
+		{
+			name:        "DefaultRegion",
+			serviceName: "iam-users",
+			runHash:     "abcdef",
+			want:        "iam-users-abcdef-ew.a.run.app:443",
+			wantCode:    codes.OK,
+		},
+		{
+			name:        "Region",
+			serviceName: "iam-users",
+			runHash:     "abcdef",
+			region:      "us-central1",
+			want:        "iam-users-abcdef-uc.a.run.app:443",
+			wantCode:    codes.OK,
+		},
+		{
+			name:        "UnknownRegion",
+			serviceName: "iam-users",
+			runHash:     "abcdef",
+			region:      "mars-north1",
+			wantCode:    codes.FailedPrecondition,
+		},
+		{
+			name:        "MissingHash",
+			serviceName: "iam-users",
+			runHash:     "",
+			wantCode:    codes.FailedPrecondition,
+		},
+		{
+			name:        "InvalidServiceName",
+			serviceName: "iam_users",
+			runHash:     "abcdef-ew.a",
+			wantCode:    codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(RunHashEnv, tt.runHash)
+			t.Setenv(RegionEnv, tt.region)
+
+			got, err := RunServiceHost(tt.serviceName)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("RunServiceHost() error = %v, want code %v", err, tt.wantCode)
+			}
+			if got != tt.want {
+				t.Errorf("RunServiceHost() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+)
+
+// RPCSizeStats holds the message size details of a single RPC made on a connection.
+//
+// Sizes are reported as the uncompressed payload lengths, which are the values
+// compared against limits such as grpc.MaxCallRecvMsgSize and grpc.MaxCallSendMsgSize.
+type RPCSizeStats struct {
+	// The full RPC method name, for example: "/myorg.co.jobs.v1.JobsService/GetJob"
+	FullMethod string
+	// The total number of payload bytes sent to the server.
+	SentBytes int
+	// The total number of payload bytes received from the server.
+	ReceivedBytes int
+	// The number of messages sent to the server. This is always 1 for unary RPCs.
+	SentMessages int
+	// The number of messages received from the server. This is always 1 for successful unary RPCs.
+	ReceivedMessages int
+	// The largest single message received from the server, in bytes.
+	MaxReceivedMessageBytes int
+	// The error the RPC ended with, if any.
+	Err error
+}
+
+/*
+WithMessageSizeStats installs a grpc.StatsHandler on the connection which records the number of bytes sent and
+received for each RPC and reports them via the provided callback once the RPC ends.
+
+This is useful to get visibility into the actual message sizes flowing through a connection, which in turn allows
+one to set realistic values for options such as grpc.MaxCallRecvMsgSize instead of relying on giant defaults.
+
+The callback is invoked synchronously by the gRPC runtime and should therefore return quickly.
+
+Example:
+
+	conn, err := client.Dial(ctx, host, client.WithMessageSizeStats(func(ctx context.Context, s RPCSizeStats) {
+		alog.Infof(ctx, "%s sent %d bytes and received %d bytes", s.FullMethod, s.SentBytes, s.ReceivedBytes)
+	}))
+*/
+func WithMessageSizeStats(callback func(ctx context.Context, stats RPCSizeStats)) ConnOption {
+	return func(o *ConnOptions) {
+		o.dialOptions = append(o.dialOptions, grpc.WithStatsHandler(&sizeStatsHandler{callback: callback}))
+	}
+}
+
+// sizeStatsHandler implements the stats.Handler interface to track message sizes per RPC.
+type sizeStatsHandler struct {
+	callback func(ctx context.Context, stats RPCSizeStats)
+}
+
+// rpcSizeStatsKey is the context key used to store the per RPC stats.
+type rpcSizeStatsKey struct{}
+
+// rpcSizeStats wraps RPCSizeStats with a mutex since payload events of streaming RPCs
+// may be reported from different goroutines.
+type rpcSizeStats struct {
+	mu    sync.Mutex
+	stats RPCSizeStats
+}
+
+// TagRPC attaches a fresh set of stats to the RPC context.
+func (h *sizeStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, rpcSizeStatsKey{}, &rpcSizeStats{
+		stats: RPCSizeStats{FullMethod: info.FullMethodName},
+	})
+}
+
+// HandleRPC records the payload sizes and reports the stats once the RPC ends.
+func (h *sizeStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	rs, ok := ctx.Value(rpcSizeStatsKey{}).(*rpcSizeStats)
+	if !ok {
+		return
+	}
+
+	rs.mu.Lock()
+	switch s := s.(type) {
+	case *stats.OutPayload:
+		rs.stats.SentBytes += s.Length
+		rs.stats.SentMessages++
+	case *stats.InPayload:
+		rs.stats.ReceivedBytes += s.Length
+		rs.stats.ReceivedMessages++
+		if s.Length > rs.stats.MaxReceivedMessageBytes {
+			rs.stats.MaxReceivedMessageBytes = s.Length
+		}
+	case *stats.End:
+		rs.stats.Err = s.Error
+		res := rs.stats
+		rs.mu.Unlock()
+		if h.callback != nil {
+			h.callback(ctx, res)
+		}
+		return
+	}
+	rs.mu.Unlock()
+}
+
+// TagConn is a no-op, connection level stats are not tracked.
+func (h *sizeStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn is a no-op, connection level stats are not tracked.
+func (h *sizeStatsHandler) HandleConn(_ context.Context, _ stats.ConnStats) {}
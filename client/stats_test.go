@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestServer starts an in-memory gRPC server exposing the standard health service.
+func newTestServer(t *testing.T) *bufconn.Listener {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	return lis
+}
+
+func TestWithMessageSizeStats(t *testing.T) {
+	lis := newTestServer(t)
+	ctx := context.Background()
+
+	statsCh := make(chan RPCSizeStats, 1)
+	conn, err := Dial(ctx, "localhost:8080", WithInsecure(),
+		WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		})),
+		WithMessageSizeStats(func(ctx context.Context, s RPCSizeStats) {
+			statsCh <- s
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	req := &healthpb.HealthCheckRequest{Service: ""}
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, req)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	select {
+	case got := <-statsCh:
+		if got.FullMethod != healthpb.Health_Check_FullMethodName {
+			t.Errorf("FullMethod = %v, want %v", got.FullMethod, healthpb.Health_Check_FullMethodName)
+		}
+		if got.SentMessages != 1 || got.ReceivedMessages != 1 {
+			t.Errorf("SentMessages = %v, ReceivedMessages = %v, want 1 and 1", got.SentMessages, got.ReceivedMessages)
+		}
+		if want := proto.Size(req); got.SentBytes != want {
+			t.Errorf("SentBytes = %v, want %v", got.SentBytes, want)
+		}
+		if want := proto.Size(res); got.ReceivedBytes != want {
+			t.Errorf("ReceivedBytes = %v, want %v", got.ReceivedBytes, want)
+		}
+		if got.Err != nil {
+			t.Errorf("Err = %v, want nil", got.Err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stats handler did not observe the RPC")
+	}
+}
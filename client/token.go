@@ -0,0 +1,62 @@
+package client
+
+import (
+	"fmt"
+
+	"golang.org/x/oauth2"
+)
+
+/*
+WithTokenErrorHandler registers a function which is called whenever the ID token source used by Dial fails to
+retrieve a token, for example due to a metadata server outage. This allows alerting specifically on authentication
+token problems, which would otherwise only surface as Unauthenticated errors on the RPCs.
+
+The error passed to the handler, and returned to the RPC, identifies the audience of the token. The handler is called
+synchronously on the RPC path and should therefore return quickly. The option has no effect on insecure connections.
+
+Example:
+
+	conn, err := client.Dial(ctx, host, client.WithTokenErrorHandler(func(err error) {
+		alog.Alertf(ctx, "ID token refresh failed: %v", err)
+	}))
+*/
+func WithTokenErrorHandler(handler func(error)) ConnOption {
+	return func(o *ConnOptions) {
+		o.tokenErrorHandler = handler
+	}
+}
+
+// ErrTokenRefresh is returned when the ID token source of a connection fails to retrieve a token.
+type ErrTokenRefresh struct {
+	// The audience of the ID token.
+	Audience string
+	err      error
+}
+
+func (e ErrTokenRefresh) Error() string {
+	return fmt.Sprintf("unable to retrieve an ID token for %s: %v", e.Audience, e.err)
+}
+
+func (e ErrTokenRefresh) Unwrap() error {
+	return e.err
+}
+
+// errorHandlingTokenSource identifies the failures of the underlying token source, and reports them to a handler.
+type errorHandlingTokenSource struct {
+	oauth2.TokenSource
+	audience string
+	handler  func(error)
+}
+
+// Token returns the token of the underlying token source, or an ErrTokenRefresh error if it fails.
+func (s *errorHandlingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.TokenSource.Token()
+	if err != nil {
+		err = ErrTokenRefresh{Audience: s.audience, err: err}
+		if s.handler != nil {
+			s.handler(err)
+		}
+		return nil, err
+	}
+	return token, nil
+}
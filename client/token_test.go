@@ -0,0 +1,63 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+type failingTokenSource struct {
+	err error
+}
+
+func (s failingTokenSource) Token() (*oauth2.Token, error) {
+	return nil, s.err
+}
+
+func TestErrorHandlingTokenSource(t *testing.T) {
+	errMetadata := errors.New("metadata server unavailable")
+
+	var handled []error
+	ts := &errorHandlingTokenSource{
+		TokenSource: failingTokenSource{err: errMetadata},
+		audience:    "https://example.a.run.app",
+		handler: func(err error) {
+			handled = append(handled, err)
+		},
+	}
+
+	_, err := ts.Token()
+	var refreshErr ErrTokenRefresh
+	if !errors.As(err, &refreshErr) || refreshErr.Audience != "https://example.a.run.app" {
+		t.Fatalf("Token() error = %v, want ErrTokenRefresh for the audience", err)
+	}
+	if !errors.Is(err, errMetadata) {
+		t.Errorf("Token() error = %v, want it to wrap %v", err, errMetadata)
+	}
+	if len(handled) != 1 || !errors.Is(handled[0], errMetadata) {
+		t.Errorf("handler called with %v, want a single call with the refresh error", handled)
+	}
+}
+
+func TestErrorHandlingTokenSource_Success(t *testing.T) {
+	called := false
+	ts := &errorHandlingTokenSource{
+		TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}),
+		handler:     func(error) { called = true },
+	}
+
+	token, err := ts.Token()
+	if err != nil || token.AccessToken != "token" {
+		t.Fatalf("Token() = %v, %v, want the token", token, err)
+	}
+	if called {
+		t.Errorf("handler called, want no call on success")
+	}
+}
+
+func TestWithTokenErrorHandler_NotConfigured(t *testing.T) {
+	if options := newConnOptions([]ConnOption{WithLogger(func(string, ...any) {})}); options.tokenErrorHandler != nil {
+		t.Errorf("tokenErrorHandler = non-nil, want nil without WithTokenErrorHandler")
+	}
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"net/url"
 	"regexp"
 
 	"google.golang.org/grpc/codes"
@@ -19,3 +20,15 @@ func validateArgument(name string, value string, regex string) error {
 	}
 	return nil
 }
+
+// validateAudience validates the audience of the ID tokens, which must be an absolute http(s) URL, such as
+// https://my-gateway-abcdef.ew.gateway.dev.
+func validateAudience(audience string) error {
+	u, err := url.Parse(audience)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"audience (%s) is not a valid URL, expected for example https://my-service.example.com", audience)
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// webContentType is the content type of gRPC-Web requests and responses using the binary protobuf encoding.
+	webContentType = "application/grpc-web+proto"
+	// webTrailerFlag marks a frame holding the trailers rather than a message.
+	webTrailerFlag = 0x80
+	// webCompressedFlag marks a frame holding a compressed message.
+	webCompressedFlag = 0x01
+	// webDefaultMaxRecvMsgSize is the default maximum size of the frames received, the same as for gRPC connections.
+	webDefaultMaxRecvMsgSize = 4 << 20
+)
+
+/*
+WebConn is a connection to a gRPC-Web endpoint, for deployments which only expose gRPC-Web behind an HTTP/1.1 proxy,
+which a *grpc.ClientConn cannot talk to.
+
+It implements grpc.ClientConnInterface and can therefore be passed to the generated client constructors instead of a
+*grpc.ClientConn. Only unary RPCs are supported, streaming RPCs fail with an Unimplemented error.
+*/
+type WebConn struct {
+	// The base URL of the endpoint, for example: https://your-app-on-cloudrun-abcdef-ew.a.run.app:443
+	baseURL    string
+	httpClient *http.Client
+	// tokenSource, if set, is used to add an ID token to each request.
+	tokenSource oauth2.TokenSource
+}
+
+// Ensure WebConn can be used by generated clients.
+var _ grpc.ClientConnInterface = (*WebConn)(nil)
+
+/*
+NewWebConn creates a new connection to a gRPC-Web endpoint, which is used instead of Dial to reach gRPC-Web only
+deployments.
+  - host should be of the form domain:port, for example: `your-app-on-cloudrun-abcdef-ew.a.run.app:443`
+  - use WithInsecure when testing your gRPC-Web server locally, i.e. to use plain HTTP without ID tokens.
+
+The RPCs are sent as HTTP/1.1 POST requests using the gRPC-Web binary protocol, authenticated like those of Dial,
+i.e. using WithAudience, WithClientCertificate and WithRootCAs. Of the other options, only WithLogger and
+WithTokenErrorHandler apply, while WithDialOptions, WithRetry, WithBlockingDial and WithHealthCheck are ignored.
+As with gRPC connections, the size of the received messages is limited to 4 MiB, which may be changed per call using
+grpc.MaxCallRecvMsgSize.
+
+Example:
+
+	conn, err := client.NewWebConn(ctx, "edge-proxy.example.com:443", client.WithAudience("https://jobs.example.com"))
+	if err != nil {
+		return err
+	}
+	jobs := pb.NewJobsServiceClient(conn)
+*/
+func NewWebConn(ctx context.Context, host string, opts ...ConnOption) (*WebConn, error) {
+	options := newConnOptions(opts)
+
+	err := validateArgument("host", host, `^[a-zA-Z0-9.-]+:\d+$`)
+	if err != nil {
+		return nil, err
+	}
+
+	logf, _ := options.logger()
+	if options.insecure {
+		logf("client: using gRPC-Web over plain HTTP for %s", host)
+		return &WebConn{baseURL: "http://" + host, httpClient: &http.Client{}}, nil
+	}
+
+	tlsConfig, err := options.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	conn := &WebConn{
+		baseURL:    "https://" + host,
+		httpClient: &http.Client{Transport: transport},
+	}
+	if !options.usesIDTokens() {
+		logf("client: using gRPC-Web over mutual TLS for %s", host)
+		return conn, nil
+	}
+
+	audience, err := options.tokenAudience(host)
+	if err != nil {
+		return nil, err
+	}
+	conn.tokenSource, err = newTokenSource(ctx, host, audience, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// Invoke performs a unary RPC using the gRPC-Web protocol and returns after the response is received into reply.
+func (c *WebConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	req, ok := args.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "client: request of %s is not a proto message", method)
+	}
+	res, ok := reply.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "client: response of %s is not a proto message", method)
+	}
+
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		return status.Errorf(codes.Internal, "client: marshal request: %v", err)
+	}
+	body := make([]byte, 5+len(payload))
+	binary.BigEndian.PutUint32(body[1:5], uint32(len(payload)))
+	copy(body[5:], payload)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+method, bytes.NewReader(body))
+	if err != nil {
+		return status.Errorf(codes.Internal, "client: create request: %v", err)
+	}
+	if err := c.setHeaders(ctx, httpReq); err != nil {
+		return err
+	}
+
+	httpRes, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return status.FromContextError(ctxErr).Err()
+		}
+		return status.Errorf(codes.Unavailable, "client: %v", err)
+	}
+	defer httpRes.Body.Close()
+
+	maxRecvMsgSize := webDefaultMaxRecvMsgSize
+	for _, opt := range opts {
+		if o, ok := opt.(grpc.MaxRecvMsgSizeCallOption); ok {
+			maxRecvMsgSize = o.MaxRecvMsgSize
+		}
+	}
+
+	return readWebResponse(httpRes, res, maxRecvMsgSize)
+}
+
+// NewStream is not supported, since gRPC-Web proxies generally do not support streaming RPCs over HTTP/1.1.
+func (c *WebConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "client: streaming RPCs are not supported over gRPC-Web (%s)", method)
+}
+
+// Close closes the idle connections of the underlying HTTP client.
+func (c *WebConn) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// setHeaders sets the gRPC-Web headers, the outgoing metadata and the ID token, if any, on the request. The values of
+// binary metadata, whose keys end with "-bin", are base64 encoded as per the gRPC spec.
+func (c *WebConn) setHeaders(ctx context.Context, req *http.Request) error {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		for key, values := range md {
+			isBinary := strings.HasSuffix(key, "-bin")
+			for _, value := range values {
+				if isBinary {
+					value = base64.RawStdEncoding.EncodeToString([]byte(value))
+				}
+				req.Header.Add(key, value)
+			}
+		}
+	}
+	req.Header.Set("Content-Type", webContentType)
+	req.Header.Set("Accept", webContentType)
+	req.Header.Set("X-Grpc-Web", "1")
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set("Grpc-Timeout", fmt.Sprintf("%dm", max(time.Until(deadline).Milliseconds(), 1)))
+	}
+
+	if c.tokenSource != nil {
+		token, err := c.tokenSource.Token()
+		if err != nil {
+			return status.Errorf(codes.Unauthenticated, "client: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	}
+
+	return nil
+}
+
+/*
+readWebResponse reads the message and trailers of a gRPC-Web response, and unmarshals the message into reply.
+It returns the status of the RPC as an error, if not OK. Frames larger than maxSize are rejected with a
+ResourceExhausted error before being read.
+*/
+func readWebResponse(res *http.Response, reply proto.Message, maxSize int) error {
+	if res.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return status.Errorf(webHTTPStatusCode(res.StatusCode), "client: gRPC-Web endpoint responded with %s: %s",
+			res.Status, strings.TrimSpace(string(data)))
+	}
+
+	// Errors without a message may be returned in the headers only.
+	if code := res.Header.Get("Grpc-Status"); code != "" {
+		if err := webStatus(code, res.Header.Get("Grpc-Message")); err != nil {
+			return err
+		}
+	}
+
+	received := false
+	for {
+		header := make([]byte, 5)
+		if _, err := io.ReadFull(res.Body, header); err != nil {
+			if err == io.EOF {
+				return status.Error(codes.Internal, "client: gRPC-Web response ended without trailers")
+			}
+			return status.Errorf(codes.Internal, "client: read gRPC-Web frame: %v", err)
+		}
+		size := binary.BigEndian.Uint32(header[1:5])
+		if uint64(size) > uint64(maxSize) {
+			return status.Errorf(codes.ResourceExhausted, "client: received gRPC-Web frame larger than max (%d vs. %d)", size, maxSize)
+		}
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(res.Body, frame); err != nil {
+			return status.Errorf(codes.Internal, "client: read gRPC-Web frame: %v", err)
+		}
+
+		flags := header[0]
+		if flags&webTrailerFlag != 0 {
+			trailers, err := textproto.NewReader(bufio.NewReader(bytes.NewReader(append(frame, '\r', '\n')))).ReadMIMEHeader()
+			if err != nil && err != io.EOF {
+				return status.Errorf(codes.Internal, "client: read gRPC-Web trailers: %v", err)
+			}
+			if err := webStatus(trailers.Get("Grpc-Status"), trailers.Get("Grpc-Message")); err != nil {
+				return err
+			}
+			if !received {
+				return status.Error(codes.Internal, "client: gRPC-Web response ended without a message")
+			}
+			return nil
+		}
+		if flags&webCompressedFlag != 0 {
+			return status.Error(codes.Internal, "client: compressed gRPC-Web messages are not supported")
+		}
+		if received {
+			return status.Error(codes.Internal, "client: gRPC-Web response has more than one message for a unary RPC")
+		}
+		if err := proto.Unmarshal(frame, reply); err != nil {
+			return status.Errorf(codes.Internal, "client: unmarshal response: %v", err)
+		}
+		received = true
+	}
+}
+
+// webStatus returns the status error with the provided code and percent-encoded message, or nil if the code is OK.
+func webStatus(code string, message string) error {
+	c, err := strconv.Atoi(code)
+	if err != nil {
+		return status.Errorf(codes.Internal, "client: invalid gRPC-Web status %q", code)
+	}
+	if codes.Code(c) == codes.OK {
+		return nil
+	}
+	if decoded, err := url.PathUnescape(message); err == nil {
+		message = decoded
+	}
+	return status.Error(codes.Code(c), message)
+}
+
+// webHTTPStatusCode maps the HTTP status of a failed gRPC-Web response onto a gRPC code, as per the gRPC spec.
+func webHTTPStatusCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.Internal
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,240 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// newWebTestServer starts a minimal gRPC-Web server over plain HTTP, see newWebTestHandler.
+func newWebTestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(newWebTestHandler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newWebTestHandler returns a minimal gRPC-Web handler for the health check method, which responds depending on the
+// service checked.
+func newWebTestHandler() http.Handler {
+	frame := func(flags byte, data []byte) []byte {
+		res := make([]byte, 5+len(data))
+		res[0] = flags
+		binary.BigEndian.PutUint32(res[1:5], uint32(len(data)))
+		copy(res[5:], data)
+		return res
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/grpc.health.v1.Health/Check", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != webContentType || r.Header.Get("X-Grpc-Web") != "1" {
+			http.Error(w, "not a gRPC-Web request", http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil || len(body) < 5 || body[0] != 0 {
+			http.Error(w, "invalid frame", http.StatusBadRequest)
+			return
+		}
+		req := &healthpb.HealthCheckRequest{}
+		if err := proto.Unmarshal(body[5:], req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", webContentType)
+		switch req.GetService() {
+		case "serving":
+			data, _ := proto.Marshal(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+			_, _ = w.Write(frame(0, data))
+			_, _ = w.Write(frame(webTrailerFlag, []byte("grpc-status:0\r\n")))
+		case "metadata":
+			// Only report serving if the outgoing metadata was forwarded, with the binary values base64 encoded.
+			servingStatus := healthpb.HealthCheckResponse_NOT_SERVING
+			trace, err := base64.RawStdEncoding.DecodeString(r.Header.Get("X-Trace-Bin"))
+			if r.Header.Get("X-Request-Id") == "abc" && err == nil && string(trace) == "\x00\xff" {
+				servingStatus = healthpb.HealthCheckResponse_SERVING
+			}
+			data, _ := proto.Marshal(&healthpb.HealthCheckResponse{Status: servingStatus})
+			_, _ = w.Write(frame(0, data))
+			_, _ = w.Write(frame(webTrailerFlag, []byte("grpc-status:0\r\n")))
+		case "compressed":
+			_, _ = w.Write(frame(webCompressedFlag, []byte{0x1f, 0x8b}))
+			_, _ = w.Write(frame(webTrailerFlag, []byte("grpc-status:0\r\n")))
+		case "oversized":
+			// Only the header of the frame is sent, the client must not wait for, nor allocate, its data.
+			header := frame(0, nil)
+			binary.BigEndian.PutUint32(header[1:5], 1<<30)
+			_, _ = w.Write(header)
+		case "trailers-only":
+			w.Header().Set("Grpc-Status", "7")
+			w.Header().Set("Grpc-Message", "access%20denied")
+		default:
+			_, _ = w.Write(frame(webTrailerFlag, []byte("grpc-status:5\r\ngrpc-message:unknown%20service\r\n")))
+		}
+	})
+	return mux
+}
+
+func TestNewWebConn(t *testing.T) {
+	server := newWebTestServer(t)
+	conn, err := NewWebConn(context.Background(), strings.TrimPrefix(server.URL, "http://"), WithInsecure())
+	if err != nil {
+		t.Fatalf("NewWebConn() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	health := healthpb.NewHealthClient(conn)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		service     string
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{name: "message and trailers", ctx: context.Background(), service: "serving", wantCode: codes.OK},
+		{
+			name:     "outgoing metadata",
+			ctx:      metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "abc", "x-trace-bin", "\x00\xff"),
+			service:  "metadata",
+			wantCode: codes.OK,
+		},
+		{
+			name:        "error in trailers",
+			ctx:         context.Background(),
+			service:     "unknown",
+			wantCode:    codes.NotFound,
+			wantMessage: "unknown service",
+		},
+		{
+			name:        "error in headers",
+			ctx:         context.Background(),
+			service:     "trailers-only",
+			wantCode:    codes.PermissionDenied,
+			wantMessage: "access denied",
+		},
+		{
+			name:        "compressed message",
+			ctx:         context.Background(),
+			service:     "compressed",
+			wantCode:    codes.Internal,
+			wantMessage: "client: compressed gRPC-Web messages are not supported",
+		},
+		{
+			name:        "oversized frame",
+			ctx:         context.Background(),
+			service:     "oversized",
+			wantCode:    codes.ResourceExhausted,
+			wantMessage: "client: received gRPC-Web frame larger than max (1073741824 vs. 4194304)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := health.Check(tt.ctx, &healthpb.HealthCheckRequest{Service: tt.service})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("Check() code = %v, want %v (error = %v)", got, tt.wantCode, err)
+			}
+			if tt.wantCode != codes.OK {
+				if got := status.Convert(err).Message(); got != tt.wantMessage {
+					t.Errorf("Check() message = %q, want %q", got, tt.wantMessage)
+				}
+				return
+			}
+			if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+				t.Errorf("Check() status = %v, want SERVING", res.GetStatus())
+			}
+		})
+	}
+}
+
+func TestNewWebConn_MutualTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(newWebTestHandler())
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	// Without the client certificate, the handshake is rejected by the server.
+	conn, err := NewWebConn(context.Background(), host, WithRootCAs(rootCAs), WithClientCertificate(tls.Certificate{}))
+	if err != nil {
+		t.Fatalf("NewWebConn() error = %v", err)
+	}
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: "serving"})
+	if got := status.Code(err); got != codes.Unavailable {
+		t.Errorf("Check() without a client certificate code = %v, want %v", got, codes.Unavailable)
+	}
+
+	conn, err = NewWebConn(context.Background(), host, WithRootCAs(rootCAs), WithClientCertificate(server.TLS.Certificates[0]))
+	if err != nil {
+		t.Fatalf("NewWebConn() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	res, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: "serving"})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Check() status = %v, want SERVING", res.GetStatus())
+	}
+}
+
+func TestNewWebConn_InvalidAudience(t *testing.T) {
+	_, err := NewWebConn(context.Background(), "api.example.com:443", WithAudience("my-service.a.run.app"))
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("NewWebConn() code = %v, want %v", got, codes.InvalidArgument)
+	}
+}
+
+func TestWebConn_MaxReceiveMessageSize(t *testing.T) {
+	server := newWebTestServer(t)
+	conn, err := NewWebConn(context.Background(), strings.TrimPrefix(server.URL, "http://"), WithInsecure())
+	if err != nil {
+		t.Fatalf("NewWebConn() error = %v", err)
+	}
+	health := healthpb.NewHealthClient(conn)
+
+	// The default limit may be lowered per call.
+	req := &healthpb.HealthCheckRequest{Service: "serving"}
+	if _, err := health.Check(context.Background(), req, grpc.MaxCallRecvMsgSize(1)); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Check() error = %v, want code %v", err, codes.ResourceExhausted)
+	}
+	if _, err := health.Check(context.Background(), req); err != nil {
+		t.Errorf("Check() error = %v, want no error with the default limit", err)
+	}
+}
+
+func TestWebConn_Unsupported(t *testing.T) {
+	server := newWebTestServer(t)
+	conn, err := NewWebConn(context.Background(), strings.TrimPrefix(server.URL, "http://"), WithInsecure())
+	if err != nil {
+		t.Fatalf("NewWebConn() error = %v", err)
+	}
+	health := healthpb.NewHealthClient(conn)
+
+	// The Watch method is a streaming RPC.
+	_, err = health.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	if got := status.Code(err); got != codes.Unimplemented {
+		t.Errorf("Watch() code = %v, want %v", got, codes.Unimplemented)
+	}
+
+	// Methods which are not exposed by the proxy respond with a 404.
+	err = conn.Invoke(context.Background(), "/grpc.health.v1.Health/Unknown", &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+	if got := status.Code(err); got != codes.Unimplemented {
+		t.Errorf("Invoke() code = %v, want %v", got, codes.Unimplemented)
+	}
+}
@@ -20,6 +20,16 @@ const (
 	claimedKey ctxKey = "x-alis-authz-claimed"
 )
 
+const (
+	// AllUsers is the policy member which grants the role to anyone. Since requests without a principal are attributed
+	// to the deployment service account, see ExtractIdentityFromCtx, it grants the role to the same identities as
+	// AllAuthenticatedUsers.
+	AllUsers = "allUsers"
+	// AllAuthenticatedUsers is the policy member which grants the role to any authenticated principal, i.e. any user
+	// or service account whose identity could be extracted from the request.
+	AllAuthenticatedUsers = "allAuthenticatedUsers"
+)
+
 // Authorizer is responsible for the Authorization (i.e. Access) part of the IAM service and
 // lives for the duration of a grpc method call. It is used to authorize the requester while
 // providing access to the policy cache and the member cache to prevent redundant calls.
@@ -179,9 +189,7 @@ func (a *Authorizer) HasAccess(permission string, policies ...*iampb.Policy) boo
 			if a.iam.RoleHasPermission(binding.Role, permission) {
 				// Check whether the identity is present in the policy members.
 				for _, member := range binding.Members {
-					if member == a.Identity.PolicyMember() {
-						return true
-					} else if a.IsGroupMember(member) {
+					if a.isMember(member) {
 						return true
 					}
 				}
@@ -205,9 +213,7 @@ func (a *Authorizer) HasRole(policies []*iampb.Policy, role string) bool {
 			if bindingRole == role {
 				// Check whether the identity is present in the policy members.
 				for _, policyMember := range binding.Members {
-					if a.Identity.PolicyMember() == policyMember {
-						return true
-					} else if a.IsGroupMember(policyMember) {
+					if a.isMember(policyMember) {
 						return true
 					}
 				}
@@ -217,6 +223,25 @@ func (a *Authorizer) HasRole(policies []*iampb.Policy, role string) bool {
 	return false
 }
 
+/*
+isMember returns whether the identity is the specified policy member, either explicitly, through the AllUsers or
+AllAuthenticatedUsers members, or as a member of a group.
+
+Bindings only ever grant access, so an explicit binding and a binding to AllUsers or AllAuthenticatedUsers are
+additive: the identity is granted the role if any of the members match, and an explicit binding cannot narrow down a
+public one.
+*/
+func (a *Authorizer) isMember(member string) bool {
+	switch member {
+	case AllUsers, AllAuthenticatedUsers:
+		return a.Identity.IsAuthenticated()
+	}
+	if a.Identity != nil && member == a.Identity.PolicyMember() {
+		return true
+	}
+	return a.IsGroupMember(member)
+}
+
 // Returns whether identity is a member of the specified iam group.
 func (a *Authorizer) IsGroupMember(group string) bool {
 	parts := strings.Split(group, ":")
@@ -230,7 +255,7 @@ func (a *Authorizer) IsGroupMember(group string) bool {
 
 		// builtin domain groups
 		if groupType == "domain" {
-			if strings.HasSuffix(a.Identity.email, "@"+parts[1]) {
+			if a.Identity != nil && strings.HasSuffix(a.Identity.email, "@"+parts[1]) {
 				return true
 			}
 		}
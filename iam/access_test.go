@@ -0,0 +1,65 @@
+package iam
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/iam/apiv1/iampb"
+)
+
+func TestAuthorizer_HasAccess_Members(t *testing.T) {
+	const permission = "/alis.in.reports.v1.ReportsService/GetReport"
+	i := &IAM{
+		rolePermissionMap: map[string]map[string]bool{"roles/viewer": {permission: true}},
+		memberResolver:    map[string]func(ctx context.Context, groupType string, groupId string, az *Authorizer) bool{},
+		openPermissions:   map[string]bool{},
+	}
+	newAuthorizer := func(identity *Identity) *Authorizer {
+		return &Authorizer{
+			iam:         i,
+			Identity:    identity,
+			policies:    &sync.Map{},
+			memberCache: &sync.Map{},
+			wg:          &sync.WaitGroup{},
+			Cache:       &sync.Map{},
+		}
+	}
+	policy := func(members ...string) *iampb.Policy {
+		return &iampb.Policy{Bindings: []*iampb.Binding{{Role: "roles/viewer", Members: members}}}
+	}
+
+	user := &Identity{id: "123456789", email: "john@example.com"}
+	tests := []struct {
+		name     string
+		identity *Identity
+		policy   *iampb.Policy
+		want     bool
+	}{
+		{name: "explicit member", identity: user, policy: policy("user:123456789"), want: true},
+		{name: "other member", identity: user, policy: policy("user:987654321"), want: false},
+		{name: "all users with principal", identity: user, policy: policy(AllUsers), want: true},
+		{name: "all authenticated users with principal", identity: user, policy: policy(AllAuthenticatedUsers), want: true},
+		{name: "explicit and public members", identity: user, policy: policy("user:987654321", AllAuthenticatedUsers), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newAuthorizer(tt.identity).HasAccess(permission, tt.policy); got != tt.want {
+				t.Errorf("HasAccess() = %v, want %v", got, tt.want)
+			}
+			if got := newAuthorizer(tt.identity).HasRole([]*iampb.Policy{tt.policy}, "roles/viewer"); got != tt.want {
+				t.Errorf("HasRole() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_IsGroupMember_NoIdentity(t *testing.T) {
+	a := &Authorizer{
+		iam:         &IAM{memberResolver: map[string]func(ctx context.Context, groupType string, groupId string, az *Authorizer) bool{}},
+		memberCache: &sync.Map{},
+	}
+	if a.IsGroupMember("domain:example.com") {
+		t.Errorf("IsGroupMember() = true, want false without an identity")
+	}
+}
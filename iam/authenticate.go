@@ -8,13 +8,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 	"go.alis.build/alog"
+	"go.alis.build/client"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -27,13 +27,12 @@ const ForwardedHostHeader = "x-forwarded-host"
 
 // Creates a new Authenticator instance that can be used to authenticate users via the alis-build managed iam service.
 func NewAuthenticator() *Authenticator {
-	runHash := os.Getenv("ALIS_RUN_HASH")
-	if runHash == "" {
-		alog.Fatalf(context.Background(), "ALIS_RUN_HASH not set")
+	authHost, err := client.RunServiceHost("iam-auth")
+	if err != nil {
+		alog.Fatalf(context.Background(), "resolve the iam-auth host: %v", err)
 	}
-	authHost := fmt.Sprintf("https://iam-auth-%s.run.app:443", runHash)
 	an := &Authenticator{
-		authHost:   authHost,
+		authHost:   "https://" + authHost,
 		publicKeys: &sync.Map{},
 	}
 	return an
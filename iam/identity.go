@@ -71,6 +71,12 @@ func (r *Identity) IsServiceAccount() bool {
 	return strings.HasSuffix(r.email, ".gserviceaccount.com")
 }
 
+// Returns whether the requester is an authenticated principal, i.e. whether its identity could be extracted from the
+// request.
+func (r *Identity) IsAuthenticated() bool {
+	return r != nil && (r.id != "" || r.email != "")
+}
+
 // Returns the policy member string of the requester.
 // E.g. user:123456789 or serviceAccount:alis-build@...
 func (r *Identity) PolicyMember() string {
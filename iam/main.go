@@ -151,7 +151,7 @@ func New(opts ...IamOption) (*IAM, error) {
 	if !options.WithoutDefaultUsersClient {
 		ctx := context.Background()
 		maxSizeOptions := grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(2000000000), grpc.MaxCallRecvMsgSize(2000000000))
-		conn, err := client.NewConnWithRetry(ctx, "iam-users-"+os.Getenv("ALIS_RUN_HASH")+".run.app:443", false, maxSizeOptions)
+		conn, err := client.RunServiceConn(ctx, "iam-users", client.WithRetry(), client.WithDialOptions(maxSizeOptions))
 		if err != nil {
 			return nil, fmt.Errorf("error creating users client: %v", err)
 		}
@@ -178,12 +178,12 @@ func (s *IAM) RoleHasPermission(role string, permission string) bool {
 // WithMemberResolver registers a function to resolve whether a requester is a member of a group.
 // There can be multiple different types of groups, e.g. "team:engineering" (groupType = "team",groupId="engineering")
 // A group always has a type, but does not always have an id, e.g. "team:engineering" (groupType = "team",groupId="engineering") vs "all" (groupType = "all",groupId="").
-// "user" and "serviceAccounts" are not allowed as group types.
+// "user" and "serviceAccounts" are not allowed as group types, nor are the builtin "allUsers" and "allAuthenticatedUsers" members.
 // "domain" is a builtin group type that is resolved by checking if the requester's email ends with the group id.
 // Results are cached per Authorizer.
 func (s *IAM) WithMemberResolver(groupTypes []string, resolver func(ctx context.Context, groupType string, groupId string, principal *Authorizer) bool) *IAM {
 	for _, groupType := range groupTypes {
-		if groupType == "user" || groupType == "serviceAccount" || groupType == "domain" {
+		if groupType == "user" || groupType == "serviceAccount" || groupType == "domain" || groupType == AllUsers || groupType == AllAuthenticatedUsers {
 			alog.Fatalf(context.Background(), "cannot register builtin group type %s", groupType)
 		}
 		s.memberResolver[groupType] = resolver
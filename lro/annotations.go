@@ -0,0 +1,188 @@
+package lro
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"cloud.google.com/go/spanner"
+	"go.alis.build/sproto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// AnnotationsColumnName is the column name used in spanner to store the annotations of an operation (if used)
+const AnnotationsColumnName = "Annotations"
+
+var (
+	// annotationKeyRegex matches the keys which may be used in annotations, which are embedded in JSON paths.
+	annotationKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+	// annotationFilterRegex matches a single annotation term of a ListOperations filter, e.g. annotations.tenant = "abc".
+	annotationFilterRegex = regexp.MustCompile(`^annotations\.([A-Za-z0-9_]+)\s*=\s*"([^"]*)"$`)
+	// andRegex separates the terms of a ListOperations filter.
+	andRegex = regexp.MustCompile(`\s+AND\s+`)
+)
+
+/*
+setAnnotations merges the provided annotations into the ones already set on the LRO, within a read-write transaction so
+that concurrent updates are not lost. Annotations with an empty value are removed.
+
+The Annotations column is a JSON column and is optional, an error is returned if it does not exist.
+*/
+func (c *Client) setAnnotations(ctx context.Context, operation string, annotations map[string]string) (map[string]string, error) {
+	for key := range annotations {
+		if !annotationKeyRegex.MatchString(key) {
+			return nil, status.Errorf(codes.InvalidArgument, "annotation key (%s) is not of the right format: %s", key, annotationKeyRegex)
+		}
+	}
+
+	var res map[string]string
+	_, err := c.spanner.Client().ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		row, err := txn.ReadRow(ctx, c.spannerTable, spanner.Key{operation}, []string{AnnotationsColumnName})
+		if err != nil {
+			if spanner.ErrCode(err) == codes.NotFound {
+				return ErrNotFound{
+					Operation: operation,
+				}
+			}
+			return fmt.Errorf("read annotations from database: %w", err)
+		}
+
+		var current spanner.NullJSON
+		if err := row.Columns(&current); err != nil {
+			return fmt.Errorf("read annotations from database: %w", err)
+		}
+		res = annotationsFromJSON(current)
+		for key, value := range annotations {
+			if value == "" {
+				delete(res, key)
+				continue
+			}
+			res[key] = value
+		}
+
+		return txn.BufferWrite([]*spanner.Mutation{
+			spanner.Update(c.spannerTable, []string{"key", AnnotationsColumnName}, []interface{}{operation, spanner.NullJSON{Value: res, Valid: true}}),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAnnotations returns the annotations set on the operation using SetAnnotations.
+func (c *Client) GetAnnotations(ctx context.Context, operation string) (map[string]string, error) {
+	row, err := c.spanner.Client().Single().ReadRow(ctx, c.spannerTable, spanner.Key{operation}, []string{AnnotationsColumnName})
+	if err != nil {
+		if spanner.ErrCode(err) == codes.NotFound {
+			return nil, ErrNotFound{
+				Operation: operation,
+			}
+		}
+		return nil, fmt.Errorf("read annotations from database: %w", err)
+	}
+
+	var annotations spanner.NullJSON
+	if err := row.Columns(&annotations); err != nil {
+		return nil, fmt.Errorf("read annotations from database: %w", err)
+	}
+	return annotationsFromJSON(annotations), nil
+}
+
+// annotationsFromJSON converts the value of the Annotations column into a map, ignoring any non-string values.
+func annotationsFromJSON(value spanner.NullJSON) map[string]string {
+	res := map[string]string{}
+	if values, ok := value.Value.(map[string]interface{}); value.Valid && ok {
+		for k, v := range values {
+			if s, ok := v.(string); ok {
+				res[k] = s
+			}
+		}
+	}
+	return res
+}
+
+/*
+ListOperations lists the operations in the database, optionally filtered by their annotations.
+
+The filter is a conjunction of annotation terms of the form annotations.{key} = "{value}", for example:
+
+	annotations.tenant = "acme" AND annotations.job_type = "export"
+
+The page size and page token of the request are supported, and the name of the request is ignored.
+*/
+func (c *Client) ListOperations(ctx context.Context, req *longrunningpb.ListOperationsRequest, opts ...grpc.CallOption) (*longrunningpb.ListOperationsResponse, error) {
+	filter, err := annotationsFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	if c.table != nil {
+		rows, nextPageToken, err := c.table.Query(ctx, []proto.Message{&longrunningpb.Operation{}}, filter, &sproto.QueryOptions{
+			SortColumns: map[string]sproto.SortOrder{"key": sproto.SortOrderAsc},
+			Limit:       req.GetPageSize(),
+			PageToken:   req.GetPageToken(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		res := &longrunningpb.ListOperationsResponse{NextPageToken: nextPageToken}
+		for _, row := range rows {
+			if op, ok := row.Messages[0].(*longrunningpb.Operation); ok {
+				res.Operations = append(res.Operations, op)
+			}
+		}
+		return res, nil
+	}
+
+	rows, nextPageToken, err := c.spanner.QueryProtos(ctx, c.spannerTable, []string{OperationColumnName},
+		[]proto.Message{&longrunningpb.Operation{}}, filter, &sproto.ReadOptions{
+			SortColumns: map[string]sproto.SortOrder{"key": sproto.SortOrderAsc},
+			Limit:       req.GetPageSize(),
+			PageToken:   req.GetPageToken(),
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &longrunningpb.ListOperationsResponse{NextPageToken: nextPageToken}
+	for _, row := range rows {
+		if op, ok := row[OperationColumnName].(*longrunningpb.Operation); ok {
+			res.Operations = append(res.Operations, op)
+		}
+	}
+	return res, nil
+}
+
+// annotationsFilter converts a ListOperations filter into a Spanner statement on the Annotations column.
+func annotationsFilter(filter string) (*spanner.Statement, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return nil, nil
+	}
+
+	var conditions []string
+	params := map[string]interface{}{}
+	for i, term := range andRegex.Split(filter, -1) {
+		matches := annotationFilterRegex.FindStringSubmatch(strings.TrimSpace(term))
+		if matches == nil {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"filter term (%s) is not of the right format: annotations.{key} = \"{value}\"", term)
+		}
+		param := fmt.Sprintf("annotation%d", i)
+		conditions = append(conditions, fmt.Sprintf("JSON_VALUE(%s, '$.%s') = @%s", AnnotationsColumnName, matches[1], param))
+		params[param] = matches[2]
+	}
+
+	return &spanner.Statement{
+		SQL:    strings.Join(conditions, " AND "),
+		Params: params,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package lro
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+)
+
+func Test_annotationsFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     string
+		wantSQL    string
+		wantParams map[string]interface{}
+		wantErr    bool
+	}{
+		{name: "empty", filter: ""},
+		{
+			name:       "single",
+			filter:     `annotations.tenant = "acme"`,
+			wantSQL:    "JSON_VALUE(Annotations, '$.tenant') = @annotation0",
+			wantParams: map[string]interface{}{"annotation0": "acme"},
+		},
+		{
+			name:       "conjunction",
+			filter:     `annotations.tenant="acme" AND annotations.job_type = "export"`,
+			wantSQL:    "JSON_VALUE(Annotations, '$.tenant') = @annotation0 AND JSON_VALUE(Annotations, '$.job_type') = @annotation1",
+			wantParams: map[string]interface{}{"annotation0": "acme", "annotation1": "export"},
+		},
+		{name: "not an annotation", filter: `done = true`, wantErr: true},
+		{name: "invalid key", filter: `annotations.'a-b' = "c"`, wantErr: true},
+		{name: "disjunction", filter: `annotations.a = "b" OR annotations.c = "d"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := annotationsFilter(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("annotationsFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantSQL == "" {
+				if got != nil {
+					t.Errorf("annotationsFilter() = %v, want nil", got)
+				}
+				return
+			}
+			if got.SQL != tt.wantSQL {
+				t.Errorf("annotationsFilter() SQL = %v, want %v", got.SQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(got.Params, tt.wantParams) {
+				t.Errorf("annotationsFilter() Params = %v, want %v", got.Params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestOperation_SetAnnotations(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	op, err := NewOperation[any](ctx, client)
+	if err != nil {
+		t.Fatalf("NewOperation() error = %v", err)
+	}
+	tenant := op.Name()[len("operations/"):]
+	if _, err := op.SetAnnotations(map[string]string{"tenant": tenant, "job_type": "export"}); err != nil {
+		t.Fatalf("SetAnnotations() error = %v", err)
+	}
+	got, err := op.SetAnnotations(map[string]string{"job_type": ""})
+	if err != nil {
+		t.Fatalf("SetAnnotations() error = %v", err)
+	}
+	if want := map[string]string{"tenant": tenant}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SetAnnotations() = %v, want %v", got, want)
+	}
+
+	res, err := client.ListOperations(ctx, &longrunningpb.ListOperationsRequest{
+		Filter: `annotations.tenant = "` + tenant + `"`,
+	})
+	if err != nil {
+		t.Fatalf("ListOperations() error = %v", err)
+	}
+	if len(res.GetOperations()) != 1 || res.GetOperations()[0].GetName() != op.Name() {
+		t.Errorf("ListOperations() = %v, want only %s", res.GetOperations(), op.Name())
+	}
+}
@@ -0,0 +1,61 @@
+package lro
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"cloud.google.com/go/spanner"
+	"github.com/google/uuid"
+)
+
+/*
+BatchCreateOperations creates n new operations in a single commit and returns their names, in the format
+`operations/*`.
+
+This avoids a round trip per operation when a handler fans out to many sub-operations, which can each then be picked up
+using NewOperation with WithExistingOperation. The operations are created without a deadline.
+
+Spanner limits the number of mutations of a single commit, so very large fan-outs should be split into several calls.
+*/
+func (c *Client) BatchCreateOperations(ctx context.Context, n int) ([]string, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("number of operations cannot be negative, got %d", n)
+	}
+	if n == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, n)
+	mutations := make([]*spanner.Mutation, n)
+	for i := range names {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return nil, err
+		}
+		op := &longrunningpb.Operation{
+			Name: "operations/" + id.String(),
+		}
+		names[i] = op.GetName()
+
+		// write operation to its spanner column, the key is generated from the operation name
+		mutations[i] = spanner.Insert(c.spannerTable, []string{OperationColumnName}, []interface{}{op})
+	}
+	if _, err := c.spanner.Client().Apply(ctx, mutations); err != nil {
+		return nil, fmt.Errorf("create operations: %w", err)
+	}
+
+	// The UpdateTime column is optional, and recording the time fails softly.
+	if !c.hasUpdateTime {
+		return names, nil
+	}
+	updateTime := now().UTC().Truncate(time.Microsecond)
+	updates := make([]*spanner.Mutation, n)
+	for i, name := range names {
+		updates[i] = spanner.Update(c.spannerTable, []string{"key", UpdateTimeColumnName}, []interface{}{name, updateTime})
+	}
+	_, _ = c.spanner.Client().Apply(ctx, updates)
+
+	return names, nil
+}
@@ -0,0 +1,58 @@
+package lro
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+)
+
+func TestClient_BatchCreateOperations_Invalid(t *testing.T) {
+	client := &Client{}
+	if _, err := client.BatchCreateOperations(context.Background(), -1); err == nil {
+		t.Errorf("BatchCreateOperations(-1) error = nil, want an error")
+	}
+	names, err := client.BatchCreateOperations(context.Background(), 0)
+	if err != nil || len(names) != 0 {
+		t.Errorf("BatchCreateOperations(0) = %v, %v, want no operations", names, err)
+	}
+}
+
+func TestClient_BatchCreateOperations(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	const n = 200
+	names, err := client.BatchCreateOperations(ctx, n)
+	if err != nil {
+		t.Fatalf("BatchCreateOperations() error = %v", err)
+	}
+	if len(names) != n {
+		t.Fatalf("BatchCreateOperations() returned %d names, want %d", len(names), n)
+	}
+
+	seen := make(map[string]bool, n)
+	for _, name := range names {
+		if seen[name] {
+			t.Errorf("BatchCreateOperations() returned %s twice", name)
+		}
+		seen[name] = true
+
+		op, err := client.GetOperation(ctx, &longrunningpb.GetOperationRequest{Name: name})
+		if err != nil {
+			t.Fatalf("GetOperation(%s) error = %v", name, err)
+		}
+		if op.GetName() != name || op.GetDone() {
+			t.Errorf("GetOperation(%s) = %v, want a new operation", name, op)
+		}
+	}
+
+	// The operations can be picked up like any other existing operation.
+	op, err := NewOperation[any](ctx, client, WithExistingOperation(names[0]))
+	if err != nil {
+		t.Fatalf("NewOperation() error = %v", err)
+	}
+	if err := op.Done(nil); err != nil {
+		t.Errorf("Done() error = %v", err)
+	}
+}
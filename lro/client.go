@@ -5,12 +5,17 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"cloud.google.com/go/longrunning/autogen/longrunningpb"
 	"cloud.google.com/go/spanner"
 	executions "cloud.google.com/go/workflows/executions/apiv1"
+	"cloud.google.com/go/workflows/executions/apiv1/executionspb"
+	"github.com/googleapis/gax-go/v2"
 	"go.alis.build/sproto"
+	"google.golang.org/api/iterator"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
@@ -24,6 +29,12 @@ const (
 	StateColumnName = "State"
 	// ResumePointColumnName is the column name used in spanner to the point to resume to.
 	ResumePointColumnName = "ResumePoint"
+	// DeadlineColumnName is the column name used in spanner to store the overall deadline of an operation (if used)
+	DeadlineColumnName = "Deadline"
+	// UpdateTimeColumnName is the column name used in spanner to store the last time an operation was updated (if used)
+	UpdateTimeColumnName = "UpdateTime"
+	// ChildOperationsColumnName is the column name used in spanner to store the names of registered child operations (if used)
+	ChildOperationsColumnName = "ChildOperations"
 )
 
 type ClientOptions struct {
@@ -32,6 +43,8 @@ type ClientOptions struct {
 	// Set a default host for resumable operations, which can be overwritten via options on NewOperation.
 	// Example: "https://internal-gateway-....run.app"
 	resumeHost string
+	// The Google Cloud Workflows executions client, if provided.
+	workflows WorkflowsClient
 }
 
 // ClientOption is a functional option for the NewClient method.
@@ -66,14 +79,37 @@ func WithLocation(location string) ClientOption {
 	}
 }
 
+/*
+WithWorkflowsClient overrides the Google Cloud Workflows executions client used to hand over async waits.
+
+This is typically used in tests together with the fakes provided by the lrotest package.
+*/
+func WithWorkflowsClient(workflows WorkflowsClient) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.workflows = workflows
+	}
+}
+
+// WorkflowsClient is the subset of the Google Cloud Workflows executions client used to wait asynchronously, and to
+// re-drive waits using ReconcileOperations. It is satisfied by *executions.Client.
+type WorkflowsClient interface {
+	CreateExecution(ctx context.Context, req *executionspb.CreateExecutionRequest, opts ...gax.CallOption) (*executionspb.Execution, error)
+	GetExecution(ctx context.Context, req *executionspb.GetExecutionRequest, opts ...gax.CallOption) (*executionspb.Execution, error)
+	CancelExecution(ctx context.Context, req *executionspb.CancelExecutionRequest, opts ...gax.CallOption) (*executionspb.Execution, error)
+}
+
 type Client struct {
 	// Google Cloud Spanner configurations.
 	spanner *sproto.Client
 	// The table in Spanner which will store all Operations data.
 	spannerTable string
+	// The caller-provided table client used to read, create, update, list and delete operations, if any.
+	table *sproto.TableClient
+	// Whether the table has the optional UpdateTime column, as checked once on construction.
+	hasUpdateTime bool
 
 	// Google Cloud Workflows executions client
-	workflows *executions.Client
+	workflows WorkflowsClient
 	// Name of the workflow for which an execution should be created.
 	// Format: projects/{project}/locations/{location}/workflows/{workflow}
 	// Example: projects/myabc-123/locations/europe-west1/workflows/operations
@@ -122,6 +158,86 @@ func NewClient(ctx context.Context, spannerConfig *SpannerConfig, opts ...Client
 		return nil, fmt.Errorf("spanner configuration cannot be empty")
 	}
 
+	client, options, err := newClient(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Instantiate a Spanner client and set the table.
+	role := strings.ReplaceAll(options.project, "-", "_") // As configured by the Alis Build Platform
+	if spanner, err := sproto.NewClient(ctx, spannerConfig.Project, spannerConfig.Instance, spannerConfig.Database, role); err != nil {
+		return nil, err
+	} else {
+		client.spanner = spanner
+		client.spannerTable = strings.ReplaceAll(options.project, "-", "_") + "_AlisManagedOperations"
+	}
+	if client.hasUpdateTime, err = client.hasColumn(ctx, UpdateTimeColumnName); err != nil {
+		client.spanner.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+/*
+NewClientFromTable creates a new Client for managing long-running operations (LROs), which stores the operations in
+the table of the provided sproto TableClient. The tableName must be the name the TableClient was created with.
+
+The operations are read, created, updated, listed and deleted using the TableClient, thereby reusing its schema
+discovery, request options and error handling. The Deadline and UpdateTime columns are written in the same
+transactions, using TxTableClient.BufferWrite. The other auxiliary columns, i.e. State, ResumePoint, ChildOperations
+and Annotations, are not PROTO columns and are therefore read and written using the underlying spanner.Client of the
+TableClient directly. The table is expected to have the same schema as the tables managed by the Alis Build Platform.
+
+The TableClient remains owned by the caller, i.e. closing the returned Client does not close the underlying
+spanner.Client.
+
+The same environment variables and client options as for [NewClient] apply.
+*/
+func NewClientFromTable(ctx context.Context, table *sproto.TableClient, tableName string, opts ...ClientOption) (*Client, error) {
+	// The table client is required
+	if table == nil {
+		return nil, fmt.Errorf("table client cannot be nil")
+	}
+	if tableName == "" {
+		return nil, fmt.Errorf("table name cannot be empty")
+	}
+
+	client, _, err := newClient(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	client.table = table
+	client.spanner = sproto.New(table.Client())
+	client.spannerTable = tableName
+	if client.hasUpdateTime, err = client.hasColumn(ctx, UpdateTimeColumnName); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// hasColumn returns whether the operations table has the provided optional column, so that writes need not find out
+// by failing.
+func (c *Client) hasColumn(ctx context.Context, column string) (bool, error) {
+	it := c.spanner.Client().Single().Query(ctx, spanner.Statement{
+		SQL:    "SELECT 1 FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_NAME = @table AND COLUMN_NAME = @column",
+		Params: map[string]interface{}{"table": c.spannerTable, "column": column},
+	})
+	defer it.Stop()
+
+	_, err := it.Next()
+	if err == iterator.Done {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read schema of table %s: %w", c.spannerTable, err)
+	}
+	return true, nil
+}
+
+// newClient creates a Client from the defaults and the provided options, without configuring the Spanner storage.
+func newClient(ctx context.Context, opts []ClientOption) (*Client, *ClientOptions, error) {
 	// Configure the default options
 	options := &ClientOptions{
 		project:  os.Getenv("ALIS_OS_PROJECT"),
@@ -144,29 +260,26 @@ func NewClient(ctx context.Context, spannerConfig *SpannerConfig, opts ...Client
 		resumeHost:   options.resumeHost,
 	}
 
-	// Instantiate a Spanner client and set the table.
-	role := strings.ReplaceAll(options.project, "-", "_") // As configured by the Alis Build Platform
-	if spanner, err := sproto.NewClient(ctx, spannerConfig.Project, spannerConfig.Instance, spannerConfig.Database, role); err != nil {
-		return nil, err
-	} else {
-		client.spanner = spanner
-		client.spannerTable = strings.ReplaceAll(options.project, "-", "_") + "_AlisManagedOperations"
-	}
-
-	// Set the client
-	if executionsClient, err := executions.NewClient(ctx); err != nil {
-		return nil, err
+	// Set the client, unless one was provided.
+	if options.workflows != nil {
+		client.workflows = options.workflows
+	} else if executionsClient, err := executions.NewClient(ctx); err != nil {
+		return nil, nil, err
 	} else {
 		client.workflows = executionsClient
 	}
 
-	return client, nil
+	return client, options, nil
 }
 
 /*
 Close closes the underlying spanner.Client instance.
+Clients created using NewClientFromTable leave the caller-provided spanner.Client open.
 */
 func (c *Client) Close() {
+	if c.table != nil {
+		return
+	}
 	c.spanner.Close()
 }
 
@@ -180,7 +293,11 @@ func (c *Client) GetOperation(ctx context.Context, req *longrunningpb.GetOperati
 
 	// read operation resource from spanner
 	op := &longrunningpb.Operation{}
-	err = c.spanner.ReadProto(ctx, c.spannerTable, spanner.Key{req.GetName()}, OperationColumnName, op, nil)
+	if c.table != nil {
+		err = c.table.Read(ctx, spanner.Key{req.GetName()}, op)
+	} else {
+		err = c.spanner.ReadProto(ctx, c.spannerTable, spanner.Key{req.GetName()}, OperationColumnName, op, nil)
+	}
 	if err != nil {
 		if _, ok := err.(sproto.ErrNotFound); ok {
 			// Handle the ErrNotFound case.
@@ -196,6 +313,211 @@ func (c *Client) GetOperation(ctx context.Context, req *longrunningpb.GetOperati
 	return op, nil
 }
 
+// createOperation inserts a new LRO into the database, along with its deadline if not zero.
+func (c *Client) createOperation(ctx context.Context, op *longrunningpb.Operation, deadline time.Time) error {
+	if c.table == nil {
+		row := map[string]interface{}{OperationColumnName: op}
+		if !deadline.IsZero() {
+			row[DeadlineColumnName] = deadline
+		}
+		return c.spanner.InsertRow(ctx, c.spannerTable, row)
+	}
+
+	// The Deadline column is not a PROTO column, and is therefore written in the same transaction as the operation.
+	return c.table.RunInTransaction(ctx, func(ctx context.Context, tx *sproto.TxTableClient) error {
+		if err := tx.Create(ctx, spanner.Key{op.GetName()}, op); err != nil {
+			return err
+		}
+		if deadline.IsZero() {
+			return nil
+		}
+		return tx.BufferWrite(spanner.Update(c.spannerTable, []string{"key", DeadlineColumnName}, []interface{}{op.GetName(), deadline}))
+	})
+}
+
+/*
+updateOperation reads the LRO, applies the provided changes and writes it back within a single read-write transaction,
+thereby preventing lost updates when the same LRO is updated concurrently.
+
+The updateTime, if not zero, is written to the UpdateTime column in the same mutation as the operation, so that both
+are committed atomically. The UpdateTime column is optional, and is only written if the table has it.
+
+The apply function may be called multiple times if the transaction is retried, and should therefore only modify the
+provided operation.
+*/
+func (c *Client) updateOperation(ctx context.Context, operation string, updateTime time.Time, apply func(op *longrunningpb.Operation) error) (*longrunningpb.Operation, error) {
+	if !c.hasUpdateTime {
+		updateTime = time.Time{}
+	}
+	if c.table != nil {
+		return c.updateTableOperation(ctx, operation, updateTime, apply)
+	}
+
+	var op *longrunningpb.Operation
+	_, err := c.spanner.Client().ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		row, err := txn.ReadRow(ctx, c.spannerTable, spanner.Key{operation}, []string{OperationColumnName})
+		if err != nil {
+			if spanner.ErrCode(err) == codes.NotFound {
+				return ErrNotFound{
+					Operation: operation,
+				}
+			}
+			return fmt.Errorf("read operation from database: %w", err)
+		}
+
+		var data []byte
+		if err := row.Columns(&data); err != nil {
+			return fmt.Errorf("read operation from database: %w", err)
+		}
+		op = &longrunningpb.Operation{}
+		if err := proto.Unmarshal(data, op); err != nil {
+			return fmt.Errorf("unmarshal operation: %w", err)
+		}
+
+		if err := apply(op); err != nil {
+			return err
+		}
+
+		// write operation to its spanner column, the key is generated from the operation name
+		columns := []string{OperationColumnName}
+		values := []interface{}{op}
+		if !updateTime.IsZero() {
+			columns = append(columns, UpdateTimeColumnName)
+			values = append(values, updateTime)
+		}
+		return txn.BufferWrite([]*spanner.Mutation{
+			spanner.InsertOrUpdate(c.spannerTable, columns, values),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// updateTableOperation performs updateOperation using the TableClient.
+func (c *Client) updateTableOperation(ctx context.Context, operation string, updateTime time.Time, apply func(op *longrunningpb.Operation) error) (*longrunningpb.Operation, error) {
+	var op *longrunningpb.Operation
+	err := c.table.RunInTransaction(ctx, func(ctx context.Context, tx *sproto.TxTableClient) error {
+		op = &longrunningpb.Operation{}
+		if err := tx.Read(ctx, spanner.Key{operation}, op); err != nil {
+			if _, ok := err.(sproto.ErrNotFound); ok {
+				return ErrNotFound{
+					Operation: operation,
+				}
+			}
+			return fmt.Errorf("read operation from database: %w", err)
+		}
+
+		if err := apply(op); err != nil {
+			return err
+		}
+
+		if err := tx.Update(ctx, spanner.Key{operation}, op); err != nil {
+			return err
+		}
+		if updateTime.IsZero() {
+			return nil
+		}
+		return tx.BufferWrite(spanner.Update(c.spannerTable, []string{"key", UpdateTimeColumnName}, []interface{}{operation, updateTime}))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// deleteOperation deletes the LRO, including its auxiliary columns.
+func (c *Client) deleteOperation(ctx context.Context, operation string) error {
+	var err error
+	if c.table != nil {
+		err = c.table.Delete(ctx, spanner.Key{operation})
+	} else {
+		err = c.spanner.DeleteRow(ctx, c.spannerTable, spanner.Key{operation})
+	}
+	if err != nil {
+		return fmt.Errorf("delete operation (%s): %w", operation, err)
+	}
+	return nil
+}
+
+/*
+GetUpdateTime returns the last time the operation was created or updated, i.e. the last time its metadata was set or it
+was marked as done.
+
+This allows watchdogs to flag operations which have not advanced in a while as stuck.
+The UpdateTime column is optional, an error is returned if it does not exist or the time has not been recorded.
+*/
+func (c *Client) GetUpdateTime(ctx context.Context, operation string) (time.Time, error) {
+	row, err := c.spanner.ReadRow(ctx, c.spannerTable, spanner.Key{operation}, []string{UpdateTimeColumnName}, nil)
+	if err != nil {
+		if _, ok := err.(sproto.ErrNotFound); ok {
+			return time.Time{}, ErrNotFound{
+				Operation: operation,
+			}
+		}
+		return time.Time{}, fmt.Errorf("read update time from database: %w", err)
+	}
+
+	// Timestamps are returned in their RFC 3339 string representation.
+	updateTimeString, ok := row[UpdateTimeColumnName].(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("update time of operation (%s) is not recorded", operation)
+	}
+	return time.Parse(time.RFC3339Nano, updateTimeString)
+}
+
+// recordUpdateTime records the time at which the operation was last updated, if the table has the optional UpdateTime
+// column. It fails softly.
+func (c *Client) recordUpdateTime(ctx context.Context, operation string, updateTime time.Time) {
+	if !c.hasUpdateTime {
+		return
+	}
+	_ = c.spanner.UpdateRow(ctx, c.spannerTable, map[string]interface{}{
+		"key":                operation,
+		UpdateTimeColumnName: updateTime,
+	})
+}
+
+/*
+addChildOperations appends the provided child operation names to the ones already registered on the LRO, within a
+read-write transaction so that concurrent registrations are not lost. Names which are already registered are skipped.
+
+The ChildOperations column is an ARRAY<STRING(MAX)> and is optional, an error is returned if it does not exist.
+It returns all the child operations registered on the LRO.
+*/
+func (c *Client) addChildOperations(ctx context.Context, operation string, children ...string) ([]string, error) {
+	var res []string
+	_, err := c.spanner.Client().ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		row, err := txn.ReadRow(ctx, c.spannerTable, spanner.Key{operation}, []string{ChildOperationsColumnName})
+		if err != nil {
+			if spanner.ErrCode(err) == codes.NotFound {
+				return ErrNotFound{
+					Operation: operation,
+				}
+			}
+			return fmt.Errorf("read child operations from database: %w", err)
+		}
+
+		var registered []string
+		if err := row.Columns(&registered); err != nil {
+			return fmt.Errorf("read child operations from database: %w", err)
+		}
+		res = appendUnique(registered, children...)
+
+		return txn.BufferWrite([]*spanner.Mutation{
+			spanner.Update(c.spannerTable, []string{"key", ChildOperationsColumnName}, []interface{}{operation, res}),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // SetResponse retrieves the underlying LRO and unmarshals the Response into the provided response object.
 // It takes three arguments
 //   - ctx: Context
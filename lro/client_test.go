@@ -0,0 +1,23 @@
+package lro
+
+import (
+	"context"
+	"testing"
+
+	"go.alis.build/lro/lrotest"
+	"go.alis.build/sproto"
+)
+
+func TestNewClientFromTable_NilTable(t *testing.T) {
+	_, err := NewClientFromTable(context.Background(), nil, "operations", WithWorkflowsClient(lrotest.NewFakeWorkflowsClient()))
+	if err == nil {
+		t.Errorf("NewClientFromTable() error = nil, want an error")
+	}
+}
+
+func TestNewClientFromTable_EmptyTableName(t *testing.T) {
+	_, err := NewClientFromTable(context.Background(), &sproto.TableClient{}, "", WithWorkflowsClient(lrotest.NewFakeWorkflowsClient()))
+	if err == nil {
+		t.Errorf("NewClientFromTable() error = nil, want an error")
+	}
+}
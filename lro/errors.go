@@ -3,6 +3,10 @@ package lro
 import (
 	"errors"
 	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // ErrNotFound is returned when the requested operation does not exist.
@@ -23,6 +27,26 @@ func (e ErrWaitDeadlineExceeded) Error() string {
 	return e.message
 }
 
+// GRPCStatus returns the DeadlineExceeded status of the error, so that it is passed on as such by gRPC servers.
+func (e ErrWaitDeadlineExceeded) GRPCStatus() *status.Status {
+	return status.New(codes.DeadlineExceeded, e.message)
+}
+
+// ErrOperationDeadlineExceeded is returned when the overall deadline of an operation has passed.
+type ErrOperationDeadlineExceeded struct {
+	operation string
+	deadline  time.Time
+}
+
+func (e ErrOperationDeadlineExceeded) Error() string {
+	return fmt.Sprintf("operation (%s) exceeded its deadline of %s", e.operation, e.deadline.Format(time.RFC3339))
+}
+
+func (e ErrOperationDeadlineExceeded) Is(target error) bool {
+	_, ok := target.(ErrOperationDeadlineExceeded)
+	return ok
+}
+
 type InvalidOperationName struct {
 	Name string // unavailable locations
 }
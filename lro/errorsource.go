@@ -0,0 +1,64 @@
+package lro
+
+import (
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// ErrorSource classifies the failure of an operation, to tell failures of the business logic apart from failures of
+// the infrastructure used to wait asynchronously.
+type ErrorSource string
+
+const (
+	// ErrorSourceBusiness marks an operation failed by the business logic, i.e. using op.Error.
+	ErrorSourceBusiness ErrorSource = "BUSINESS"
+	// ErrorSourceInfra marks an operation failed by the lro package itself, e.g. if Google Cloud Workflows could not
+	// be launched, or the overall deadline of the operation passed.
+	ErrorSourceInfra ErrorSource = "INFRA"
+)
+
+const (
+	// errorInfoDomain is the domain of the ErrorInfo detail holding the ErrorSource of a failed operation.
+	errorInfoDomain = "lro.alis.build"
+	// errorInfoReason is the reason of the ErrorInfo detail holding the ErrorSource of a failed operation.
+	errorInfoReason = "OPERATION_FAILED"
+	// errorSourceKey is the metadata key of the ErrorSource in the ErrorInfo detail.
+	errorSourceKey = "source"
+)
+
+/*
+ErrorSourceOf returns the ErrorSource of a failed operation, which is stored as an errdetails.ErrorInfo detail of the
+operation error, with the "lro.alis.build" domain and the source in its "source" metadata.
+
+An empty ErrorSource is returned if the operation has not failed, or failed without a classification, e.g. before
+the classification was introduced.
+
+Example:
+
+	op, err := client.GetOperation(ctx, &longrunningpb.GetOperationRequest{Name: name})
+	...
+	if lro.ErrorSourceOf(op) == lro.ErrorSourceInfra {
+		// page the platform team rather than the product team
+	}
+*/
+func ErrorSourceOf(op *longrunningpb.Operation) ErrorSource {
+	for _, detail := range op.GetError().GetDetails() {
+		info := &errdetails.ErrorInfo{}
+		if err := detail.UnmarshalTo(info); err != nil {
+			continue
+		}
+		if info.GetDomain() == errorInfoDomain {
+			return ErrorSource(info.GetMetadata()[errorSourceKey])
+		}
+	}
+	return ""
+}
+
+// errorInfo returns the ErrorInfo detail which records the provided source.
+func errorInfo(source ErrorSource) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{
+		Reason:   errorInfoReason,
+		Domain:   errorInfoDomain,
+		Metadata: map[string]string{errorSourceKey: string(source)},
+	}
+}
@@ -0,0 +1,94 @@
+package lro
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"go.alis.build/lro/lrotest"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestErrorSourceOf(t *testing.T) {
+	detail := func(source ErrorSource) *anypb.Any {
+		a, err := anypb.New(errorInfo(source))
+		if err != nil {
+			t.Fatalf("anypb.New() error = %v", err)
+		}
+		return a
+	}
+	other, err := anypb.New(wrapperspb.String("other detail"))
+	if err != nil {
+		t.Fatalf("anypb.New() error = %v", err)
+	}
+	failed := func(details ...*anypb.Any) *longrunningpb.Operation {
+		return &longrunningpb.Operation{Done: true, Result: &longrunningpb.Operation_Error{Error: &statuspb.Status{Code: 2, Details: details}}}
+	}
+
+	tests := []struct {
+		name string
+		op   *longrunningpb.Operation
+		want ErrorSource
+	}{
+		{name: "business", op: failed(other, detail(ErrorSourceBusiness)), want: ErrorSourceBusiness},
+		{name: "infra", op: failed(detail(ErrorSourceInfra)), want: ErrorSourceInfra},
+		{name: "unclassified", op: failed(other), want: ""},
+		{name: "not failed", op: &longrunningpb.Operation{Done: true}, want: ""},
+		{name: "nil", op: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorSourceOf(tt.op); got != tt.want {
+				t.Errorf("ErrorSourceOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperation_ErrorSource(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	// Errors reported by the business logic.
+	op, err := NewOperation[any](ctx, client)
+	if err != nil {
+		t.Fatalf("NewOperation() error = %v", err)
+	}
+	if err := op.Error(errors.New("invalid order")); err != nil {
+		t.Fatalf("Error() error = %v", err)
+	}
+	got, err := op.GetOperation()
+	if err != nil {
+		t.Fatalf("GetOperation() error = %v", err)
+	}
+	if source := ErrorSourceOf(got); source != ErrorSourceBusiness {
+		t.Errorf("ErrorSourceOf() = %v, want %v", source, ErrorSourceBusiness)
+	}
+
+	// Failures to hand over an asynchronous wait to Google Cloud Workflows.
+	workflows := lrotest.NewFakeWorkflowsClient()
+	workflows.Err = errors.New("workflows unavailable")
+	client.workflows = workflows
+	op, err = NewOperation[any](ctx, client)
+	if err != nil {
+		t.Fatalf("NewOperation() error = %v", err)
+	}
+	op.devMode = false
+	if err := op.Wait(WithSleep(time.Minute), WithAsync("resume")); err == nil {
+		t.Fatalf("Wait() error = nil, want the workflows error")
+	}
+	got, err = op.GetOperation()
+	if err != nil {
+		t.Fatalf("GetOperation() error = %v", err)
+	}
+	if !got.GetDone() {
+		t.Errorf("GetOperation() = %v, want done", got)
+	}
+	if source := ErrorSourceOf(got); source != ErrorSourceInfra {
+		t.Errorf("ErrorSourceOf() = %v, want %v", source, ErrorSourceInfra)
+	}
+}
@@ -0,0 +1,117 @@
+package lro
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.alis.build/lro/lrotest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOperation_Wait_ChildOperations(t *testing.T) {
+	service := lrotest.NewFakeOperationsService()
+	service.SetDone("operations/1", true)
+	service.DoneAfter("operations/2", 3)
+
+	op := &Operation[any]{ctx: context.Background(), client: &Client{}, name: "operations/parent"}
+	err := op.Wait(WithChildOperations("operations/1", "operations/2"), WithService(service), WithPollFrequency(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if got := service.Calls("operations/1"); got != 1 {
+		t.Errorf("Calls(operations/1) = %v, want 1", got)
+	}
+	if got := service.Calls("operations/2"); got != 3 {
+		t.Errorf("Calls(operations/2) = %v, want 3", got)
+	}
+}
+
+func TestOperation_Wait_ChildOperationsTimeout(t *testing.T) {
+	service := lrotest.NewFakeOperationsService()
+	service.SetDone("operations/1", false)
+
+	op := &Operation[any]{ctx: context.Background(), client: &Client{}, name: "operations/parent"}
+	err := op.Wait(WithChildOperations("operations/1"), WithService(service),
+		WithPollFrequency(time.Millisecond), WithTimeout(10*time.Millisecond))
+	if _, ok := err.(ErrWaitDeadlineExceeded); !ok {
+		t.Errorf("Wait() error = %v, want ErrWaitDeadlineExceeded", err)
+	}
+	if got := status.Code(err); got != codes.DeadlineExceeded {
+		t.Errorf("Wait() code = %v, want %v", got, codes.DeadlineExceeded)
+	}
+}
+
+func TestOperation_waitWithGoogleWorkflows(t *testing.T) {
+	workflows := lrotest.NewFakeWorkflowsClient()
+	client := &Client{
+		workflows:    workflows,
+		workflowName: "projects/p/locations/l/workflows/alis-managed-operations",
+		resumeHost:   "https://internal-gateway-abc.run.app",
+	}
+	op := &Operation[any]{
+		ctx:          context.Background(),
+		client:       client,
+		name:         "operations/123",
+		resumeMethod: "/myorg.co.jobs.v1.JobsService/GenerateClientReports",
+	}
+
+	err := op.waitWithGoogleWorkflows(&WaitConfig{
+		sleep:                          time.Minute,
+		timeout:                        time.Hour,
+		pollFrequency:                  30 * time.Second,
+		childOperations:                []string{"operations/456"},
+		asyncChildGetOperationEndpoint: "https://internal-gateway-abc.run.app/google.longrunning.Operations/GetOperation",
+	})
+	if err != nil {
+		t.Fatalf("waitWithGoogleWorkflows() error = %v", err)
+	}
+
+	requests := workflows.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("CreateExecution() called %d times, want 1", len(requests))
+	}
+	if got := requests[0].GetParent(); got != client.workflowName {
+		t.Errorf("Parent = %v, want %v", got, client.workflowName)
+	}
+
+	var args map[string]interface{}
+	if err := json.Unmarshal([]byte(requests[0].GetExecution().GetArgument()), &args); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"operationId":            "123",
+		"initialWaitDuration":    float64(60),
+		"timeout":                float64(3600),
+		"pollFrequency":          float64(30),
+		"resumeEndpoint":         "https://internal-gateway-abc.run.app/myorg.co.jobs.v1.JobsService/GenerateClientReports",
+		"resumeEndpointAudience": "https://internal-gateway-abc.run.app",
+		"pollEndpointAudience":   "https://internal-gateway-abc.run.app",
+	}
+	for k, v := range want {
+		if args[k] != v {
+			t.Errorf("argument %s = %v, want %v", k, args[k], v)
+		}
+	}
+}
+
+func TestOperation_WaitForChildren(t *testing.T) {
+	service := lrotest.NewFakeOperationsService()
+	service.SetDone("operations/1", true)
+	service.DoneAfter("operations/2", 2)
+
+	op := &Operation[any]{ctx: context.Background(), client: &Client{}, name: "operations/parent",
+		children: appendUnique(nil, "operations/1", "operations/2", "operations/1")}
+	err := op.WaitForChildren(WithService(service), WithPollFrequency(time.Millisecond))
+	if err != nil {
+		t.Fatalf("WaitForChildren() error = %v", err)
+	}
+	if got := service.Calls("operations/1"); got != 1 {
+		t.Errorf("Calls(operations/1) = %v, want 1", got)
+	}
+	if got := service.Calls("operations/2"); got != 2 {
+		t.Errorf("Calls(operations/2) = %v, want 2", got)
+	}
+}
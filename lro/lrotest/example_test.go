@@ -0,0 +1,26 @@
+package lrotest_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.alis.build/lro"
+	"go.alis.build/lro/lrotest"
+)
+
+func ExampleFakeOperationsService() {
+	ctx := context.Background()
+
+	// The operation reports as done on the third poll.
+	service := lrotest.NewFakeOperationsService()
+	service.DoneAfter("operations/123", 3)
+
+	op, err := lro.WaitOperation(ctx, "operations/123", service, 10*time.Second)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(op.GetDone(), service.Calls("operations/123"))
+	// Output: true 3
+}
@@ -0,0 +1,223 @@
+// Package lrotest provides in-memory fakes of the services used by the lro package, allowing the wait and resume
+// logic of long-running operations to be unit tested without Google Cloud Spanner or Google Cloud Workflows.
+package lrotest //import "go.alis.build/lro/lrotest"
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"cloud.google.com/go/workflows/executions/apiv1/executionspb"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+/*
+FakeOperationsService is an in-memory implementation of the lro.OperationsService interface.
+
+Operations are registered using SetOperation or SetDone. An operation can also be configured to only report as done
+after a number of polls using DoneAfter, which is useful to exercise the polling logic of Wait.
+
+Example:
+
+	service := lrotest.NewFakeOperationsService()
+	service.DoneAfter("operations/123", 2)
+	op.Wait(lro.WithChildOperations("operations/123"), lro.WithService(service))
+*/
+type FakeOperationsService struct {
+	mu         sync.Mutex
+	operations map[string]*longrunningpb.Operation
+	// Number of remaining polls before an operation is marked as done.
+	pollsUntilDone map[string]int
+	// Number of GetOperation calls per operation.
+	calls map[string]int
+}
+
+// NewFakeOperationsService creates a new FakeOperationsService without any operations.
+func NewFakeOperationsService() *FakeOperationsService {
+	return &FakeOperationsService{
+		operations:     map[string]*longrunningpb.Operation{},
+		pollsUntilDone: map[string]int{},
+		calls:          map[string]int{},
+	}
+}
+
+// SetOperation adds or replaces the provided operation.
+func (s *FakeOperationsService) SetOperation(operation *longrunningpb.Operation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.operations[operation.GetName()] = proto.Clone(operation).(*longrunningpb.Operation)
+	delete(s.pollsUntilDone, operation.GetName())
+}
+
+// SetDone adds the operation with the provided name if it does not exist yet and sets its done state.
+func (s *FakeOperationsService) SetDone(name string, done bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.operation(name).Done = done
+	delete(s.pollsUntilDone, name)
+}
+
+// DoneAfter adds the operation with the provided name if it does not exist yet, and marks it as done once it has
+// been polled the provided number of times.
+func (s *FakeOperationsService) DoneAfter(name string, polls int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.operation(name).Done = polls <= 0
+	s.pollsUntilDone[name] = polls
+}
+
+// Calls returns the number of times GetOperation was called for the operation with the provided name.
+func (s *FakeOperationsService) Calls(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[name]
+}
+
+// GetOperation returns a copy of the operation with the provided name, or a NotFound error if it does not exist.
+func (s *FakeOperationsService) GetOperation(ctx context.Context, req *longrunningpb.GetOperationRequest, opts ...grpc.CallOption) (*longrunningpb.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls[req.GetName()]++
+	operation, ok := s.operations[req.GetName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%s not found", req.GetName())
+	}
+
+	if polls, ok := s.pollsUntilDone[req.GetName()]; ok {
+		polls--
+		s.pollsUntilDone[req.GetName()] = polls
+		if polls <= 0 {
+			operation.Done = true
+			delete(s.pollsUntilDone, req.GetName())
+		}
+	}
+
+	return proto.Clone(operation).(*longrunningpb.Operation), nil
+}
+
+// operation returns the operation with the provided name, adding it if it does not exist yet.
+// The caller must hold the lock.
+func (s *FakeOperationsService) operation(name string) *longrunningpb.Operation {
+	operation, ok := s.operations[name]
+	if !ok {
+		operation = &longrunningpb.Operation{Name: name}
+		s.operations[name] = operation
+	}
+	return operation
+}
+
+/*
+FakeWorkflowsClient is an in-memory implementation of the lro.WorkflowsClient interface which records all the
+CreateExecution calls instead of launching Google Cloud Workflows executions.
+
+The executions created are reported as active, use SetExecution to report them, or any other execution, in another
+state, e.g. to exercise lro.Client.ReconcileOperations.
+
+Use it with the lro.WithWorkflowsClient client option.
+*/
+type FakeWorkflowsClient struct {
+	mu         sync.Mutex
+	requests   []*executionspb.CreateExecutionRequest
+	executions map[string]*executionspb.Execution
+	// Names of the executions cancelled so far.
+	cancelled []string
+	// Err, if set, is returned by CreateExecution instead of recording the request.
+	Err error
+}
+
+// NewFakeWorkflowsClient creates a new FakeWorkflowsClient.
+func NewFakeWorkflowsClient() *FakeWorkflowsClient {
+	return &FakeWorkflowsClient{
+		executions: map[string]*executionspb.Execution{},
+	}
+}
+
+// CreateExecution records the request and returns an active execution with the provided argument.
+// Executions are named after their parent, e.g. "projects/p/locations/l/workflows/w/executions/1".
+func (c *FakeWorkflowsClient) CreateExecution(ctx context.Context, req *executionspb.CreateExecutionRequest, opts ...gax.CallOption) (*executionspb.Execution, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	c.requests = append(c.requests, proto.Clone(req).(*executionspb.CreateExecutionRequest))
+
+	execution := &executionspb.Execution{
+		Name:      fmt.Sprintf("%s/executions/%d", req.GetParent(), len(c.requests)),
+		Argument:  req.GetExecution().GetArgument(),
+		State:     executionspb.Execution_ACTIVE,
+		StartTime: timestamppb.Now(),
+	}
+	c.executions[execution.GetName()] = execution
+
+	return proto.Clone(execution).(*executionspb.Execution), nil
+}
+
+// GetExecution returns a copy of the execution with the provided name, or a NotFound error if it does not exist.
+func (c *FakeWorkflowsClient) GetExecution(ctx context.Context, req *executionspb.GetExecutionRequest, opts ...gax.CallOption) (*executionspb.Execution, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	execution, ok := c.executions[req.GetName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%s not found", req.GetName())
+	}
+
+	return proto.Clone(execution).(*executionspb.Execution), nil
+}
+
+// CancelExecution marks the execution with the provided name as cancelled, or returns a NotFound error if it does not
+// exist.
+func (c *FakeWorkflowsClient) CancelExecution(ctx context.Context, req *executionspb.CancelExecutionRequest, opts ...gax.CallOption) (*executionspb.Execution, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	execution, ok := c.executions[req.GetName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%s not found", req.GetName())
+	}
+	execution.State = executionspb.Execution_CANCELLED
+	c.cancelled = append(c.cancelled, req.GetName())
+
+	return proto.Clone(execution).(*executionspb.Execution), nil
+}
+
+// SetExecution adds or replaces the provided execution.
+func (c *FakeWorkflowsClient) SetExecution(execution *executionspb.Execution) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.executions[execution.GetName()] = proto.Clone(execution).(*executionspb.Execution)
+}
+
+// Requests returns the CreateExecution requests recorded so far, in the order in which they were made.
+func (c *FakeWorkflowsClient) Requests() []*executionspb.CreateExecutionRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	requests := make([]*executionspb.CreateExecutionRequest, len(c.requests))
+	copy(requests, c.requests)
+	return requests
+}
+
+// Cancelled returns the names of the executions cancelled so far, in the order in which they were cancelled.
+func (c *FakeWorkflowsClient) Cancelled() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cancelled := make([]string, len(c.cancelled))
+	copy(cancelled, c.cancelled)
+	return cancelled
+}
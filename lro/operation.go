@@ -6,10 +6,12 @@ import (
 	"encoding/base64"
 	"encoding/gob"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"cloud.google.com/go/longrunning/autogen/longrunningpb"
@@ -46,8 +48,24 @@ type Operation[T any] struct {
 	asyncCallbackFn func(ctx context.Context)
 	// Devmode enabled
 	devMode bool
+	// The overall deadline of the operation across all of its (async) waits, if any.
+	deadline time.Time
+	// The last time the operation was updated by this instance, guarded by updateTimeMu.
+	updateTime   time.Time
+	updateTimeMu sync.Mutex
+	// The names of the child operations registered using AddChild, guarded by childrenMu.
+	children   []string
+	childrenMu sync.Mutex
+	// The input stashed by the wait this invocation is resuming from, if any.
+	resumeInput *anypb.Any
+	// The attempt of this invocation at its resume point, and the maximum number of attempts.
+	attempt           int
+	maxResumeAttempts int
 }
 
+// now returns the current time, and is overridden in tests.
+var now = time.Now
+
 type OperationOptions struct {
 	// The fully qualified method name to be resumed
 	// For example: "/myorg.co.jobs.v1.JobsService/GenerateClientReports"
@@ -56,6 +74,10 @@ type OperationOptions struct {
 	existingOperation string
 	// LocalResumeCallback is used for local testing
 	asyncCallbackFn func(ctx context.Context)
+	// The overall deadline of the operation
+	deadline time.Time
+	// The maximum number of attempts of a resumed invocation
+	maxResumeAttempts int
 }
 
 // ClientOption is a functional option for the NewOperation method.
@@ -94,6 +116,24 @@ func WithCallbackFn(fn func(ctx context.Context)) OperationOption {
 	}
 }
 
+/*
+WithOperationDeadline sets an overall deadline for the operation, which is stored with the operation on creation.
+
+Each call to Wait checks the remaining budget against this deadline. Once the deadline has passed, Wait marks the
+operation as failed with a DeadlineExceeded status and returns an [ErrOperationDeadlineExceeded] error. Waits which
+would otherwise outlive the deadline have their sleep and timeout capped to the remaining budget, a synchronous wait
+which reaches the deadline thereby fails the operation as it returns, and an asynchronous one on the next Wait.
+
+This prevents a resumable operation chaining several async waits from running indefinitely via Google Cloud Workflows.
+The operations table requires a nullable 'Deadline TIMESTAMP' column to persist the deadline between async waits.
+The option is ignored for existing operations, which retain the deadline they were created with.
+*/
+func WithOperationDeadline(t time.Time) OperationOption {
+	return func(opts *OperationOptions) {
+		opts.deadline = t
+	}
+}
+
 /*
 NewOperation creates a new Operation object used to simplify the management of the underlying LRO.
 The default behaviour of this function is to create a new underlying LRO.
@@ -129,16 +169,21 @@ func NewOperation[T any](ctx context.Context, client *Client, opts ...OperationO
 
 	// Construct an Operation object
 	operation := &Operation[T]{
-		ctx:             context.WithoutCancel(ctx),
-		client:          client,
-		name:            "",
-		state:           new(T),
-		resumePoint:     "",
-		resumeMethod:    "",
-		asyncCallbackFn: options.asyncCallbackFn,
-		devMode:         false,
+		ctx:               context.WithoutCancel(ctx),
+		client:            client,
+		name:              "",
+		state:             new(T),
+		resumePoint:       "",
+		resumeMethod:      "",
+		asyncCallbackFn:   options.asyncCallbackFn,
+		devMode:           false,
+		attempt:           1,
+		maxResumeAttempts: options.maxResumeAttempts,
 	}
 
+	// Make the operation available to any business logic using the operation's context.
+	operation.ctx = WithOperation(operation.ctx, operation)
+
 	// Enable the devMode if not running on Cloud Run.
 	if os.Getenv("K_SERVICE") == "" {
 		operation.devMode = true
@@ -196,12 +241,13 @@ func NewOperation[T any](ctx context.Context, client *Client, opts ...OperationO
 		}
 		operation.name = op.GetName()
 
-		// write operation and parent to respective spanner columns
-		row := map[string]interface{}{"Operation": op}
-		err = operation.client.spanner.InsertRow(operation.ctx, operation.client.spannerTable, row)
+		// write operation and deadline to respective spanner columns
+		operation.deadline = options.deadline
+		err = operation.client.createOperation(operation.ctx, op, options.deadline)
 		if err != nil {
 			return nil, err
 		}
+		operation.client.recordUpdateTime(operation.ctx, operation.name, operation.stamp())
 	} else {
 		// The operation exists, get the details from the Spanner database.
 		// No need to actually retrieve the Operation data from the database, only need the State and ResumePoint details, if available
@@ -244,11 +290,91 @@ func NewOperation[T any](ctx context.Context, client *Client, opts ...OperationO
 				return nil, fmt.Errorf("resumePoint data is not string")
 			}
 		}
+
+		// Populate the Deadline if available.
+		// The Deadline column is optional, so we'll fail softly if unable to read it.
+		row, err = operation.client.spanner.ReadRow(operation.ctx, operation.client.spannerTable,
+			spanner.Key{operation.name}, []string{DeadlineColumnName}, nil)
+		if err == nil {
+			// Timestamps are returned in their RFC 3339 string representation.
+			if deadlineString, ok := row[DeadlineColumnName].(string); ok {
+				if deadline, err := time.Parse(time.RFC3339Nano, deadlineString); err == nil {
+					operation.deadline = deadline
+				}
+			}
+		}
+
+		// Populate the registered child operations if available.
+		// The ChildOperations column is optional, so we'll fail softly if unable to read it.
+		row, err = operation.client.spanner.ReadRow(operation.ctx, operation.client.spannerTable,
+			spanner.Key{operation.name}, []string{ChildOperationsColumnName}, nil)
+		if err == nil {
+			if children, ok := row[ChildOperationsColumnName].([]string); ok {
+				operation.children = children
+			}
+		}
+
+		// Populate the input passed to this invocation if available.
+		operation.loadResumeInput()
+
+		// Populate the attempt of this invocation if available.
+		operation.loadAttempt()
 	}
 
 	return operation, err
 }
 
+// operationContextKey is the context key used to store the current Operation.
+type operationContextKey struct{}
+
+/*
+WithOperation returns a copy of ctx which carries the provided Operation.
+The Operation can be retrieved again using [OperationFromContext].
+
+NewOperation already stores the Operation in its own context, available via op.Context(),
+so this is typically only required when deriving a new context from a different parent.
+*/
+func WithOperation[T any](ctx context.Context, op *Operation[T]) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, op)
+}
+
+/*
+OperationFromContext retrieves the current Operation from the provided context.
+This allows deeply nested business logic to, for example, update the metadata of the
+operation without the need to pass the Operation through every function.
+
+The type parameter must match the one used when creating the Operation, otherwise false is returned.
+
+Lifetime caveats:
+  - The Operation is only available on contexts derived from op.Context() or from a context passed to [WithOperation].
+    The ctx provided to NewOperation itself is not modified.
+  - op.Context() is detached from the cancellation of the original request context since the business logic
+    typically continues to run in the background after the RPC returned.
+  - When waiting asynchronously, a new Operation object is instantiated when the method is resumed. Do not hold on to
+    contexts (or Operation objects) across a resume point.
+
+Example:
+
+	func updateProgress(ctx context.Context, progress int32) error {
+		op, ok := lro.OperationFromContext[MyState](ctx)
+		if !ok {
+			return fmt.Errorf("no operation in context")
+		}
+		_, err := op.SetMetadata(&pb.MyMetadata{Progress: progress})
+		return err
+	}
+*/
+func OperationFromContext[T any](ctx context.Context) (*Operation[T], bool) {
+	op, ok := ctx.Value(operationContextKey{}).(*Operation[T])
+	return op, ok
+}
+
+// Context returns the context of the Operation, which carries the Operation itself.
+// See [OperationFromContext] for the lifetime caveats of this context.
+func (o *Operation[T]) Context() context.Context {
+	return o.ctx
+}
+
 // Name returns the name of the underlying Operation resource.
 func (o *Operation[T]) Name() string {
 	return o.name
@@ -273,80 +399,100 @@ func (o *Operation[T]) ReturnRPC() (*longrunningpb.Operation, error) {
 
 // Done marks the operation as done with a success response.
 func (o *Operation[T]) Done(response proto.Message) error {
-	// get operation
-	op, err := o.GetOperation()
-	if err != nil {
-		return err
-	}
+	_, err := o.Complete(response)
+	return err
+}
 
-	// update done and result
-	op.Done = true
+/*
+Complete marks the operation as done with a success response, like Done, and returns the resulting
+longrunningpb.Operation, like ReturnRPC.
+
+The operation is returned as written, rather than read again afterwards, which saves a round trip.
+
+Example:
+
+	return op.Complete(&pb.MyResponse{})
+*/
+func (o *Operation[T]) Complete(response proto.Message) (*longrunningpb.Operation, error) {
+	var resultAny *anypb.Any
 	if response != nil {
-		resultAny, err := anypb.New(response)
+		var err error
+		resultAny, err = anypb.New(response)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		op.Result = &longrunningpb.Operation_Response{Response: resultAny}
-	}
-
-	//  write operation and parent to respective spanner columns
-	row := map[string]interface{}{"Operation": op}
-	err = o.client.spanner.UpsertRow(o.ctx, o.client.spannerTable, row)
-	if err != nil {
-		return err
 	}
 
-	return nil
+	// update done and result
+	return o.update(func(op *longrunningpb.Operation) error {
+		op.Done = true
+		if resultAny != nil {
+			op.Result = &longrunningpb.Operation_Response{Response: resultAny}
+		}
+		return nil
+	})
 }
 
 // Error marks the operation as done with an error.
+// If the operation was created using WithMaxResumeAttempts, a resumed invocation is re-scheduled instead, as long as
+// attempts remain.
 func (o *Operation[T]) Error(error error) error {
-	// get operation
-	op, err := o.GetOperation()
-	if err != nil {
-		return err
+	if o.retryable() {
+		return o.retry()
 	}
+	return o.fail(codes.Unknown, ErrorSourceBusiness, error)
+}
 
-	// update operation fields
-	op.Done = true
+// fail marks the operation as done with an error with the provided status code, classified by the provided source.
+func (o *Operation[T]) fail(code codes.Code, source ErrorSource, error error) error {
 	if error == nil {
 		error = fmt.Errorf("unknown error")
 	}
-
-	op.Result = &longrunningpb.Operation_Error{Error: &statuspb.Status{
-		Code:    int32(codes.Unknown),
-		Message: error.Error(),
-		Details: nil,
-	}}
-
-	// write operation and parent to respective spanner columns
-	row := map[string]interface{}{"Operation": op}
-	err = o.client.spanner.UpsertRow(o.ctx, o.client.spannerTable, row)
+	detail, err := anypb.New(errorInfo(source))
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// update operation fields
+	_, err = o.update(func(op *longrunningpb.Operation) error {
+		op.Done = true
+		op.Result = &longrunningpb.Operation_Error{Error: &statuspb.Status{
+			Code:    int32(code),
+			Message: error.Error(),
+			Details: []*anypb.Any{detail},
+		}}
+		return nil
+	})
+	return err
 }
 
 // SetMetadata sets the metadata for the operation.
 func (o *Operation[T]) SetMetadata(metadata proto.Message) (*longrunningpb.Operation, error) {
-	// get operation
-	op, err := o.GetOperation()
+	metaAny, err := anypb.New(metadata)
 	if err != nil {
 		return nil, err
 	}
 
-	// update metadata if required
-	metaAny, err := anypb.New(metadata)
-	if err != nil {
-		return nil, err
+	// update metadata only, leaving any concurrent update of the other fields intact
+	return o.update(func(op *longrunningpb.Operation) error {
+		op.Metadata = metaAny
+		return nil
+	})
+}
+
+/*
+update applies the provided changes to the current operation within a read-write transaction and records the time of
+the update in the same transaction.
+
+Since the operation is read and written within the same transaction, concurrent updates of the same operation (e.g.
+SetMetadata racing with Done) are not lost.
+*/
+func (o *Operation[T]) update(apply func(op *longrunningpb.Operation) error) (*longrunningpb.Operation, error) {
+	if o.name == "" {
+		return nil, fmt.Errorf("operation name is nil")
 	}
-	op.Metadata = metaAny
 
-	// write operation and parent to respective spanner columns
-	row := map[string]interface{}{"Operation": op}
-	err = o.client.spanner.UpsertRow(o.ctx, o.client.spannerTable, row)
+	op, err := o.client.updateOperation(o.ctx, o.name, o.stamp(), apply)
 	if err != nil {
 		return nil, err
 	}
@@ -354,6 +500,30 @@ func (o *Operation[T]) SetMetadata(metadata proto.Message) (*longrunningpb.Opera
 	return op, nil
 }
 
+// stamp returns the time of the current update, which is guaranteed to be after the previous update by this instance
+// even if the clock did not advance, given the microsecond precision of Spanner timestamps. It is safe for concurrent
+// use, e.g. by SetMetadata racing with Done.
+func (o *Operation[T]) stamp() time.Time {
+	o.updateTimeMu.Lock()
+	defer o.updateTimeMu.Unlock()
+
+	updateTime := now().UTC().Truncate(time.Microsecond)
+	if !updateTime.After(o.updateTime) {
+		updateTime = o.updateTime.Add(time.Microsecond)
+	}
+	o.updateTime = updateTime
+
+	return updateTime
+}
+
+/*
+UpdateTime returns the last time the operation was created or updated, i.e. the last time its metadata was set or it
+was marked as done. See [Client.GetUpdateTime] for details.
+*/
+func (o *Operation[T]) UpdateTime() (time.Time, error) {
+	return o.client.GetUpdateTime(o.ctx, o.name)
+}
+
 // Delete deletes the LRO, including auxiliary columns
 func (o *Operation[T]) Delete() error {
 	// validate existence of operation
@@ -363,11 +533,7 @@ func (o *Operation[T]) Delete() error {
 	}
 
 	// delete operation
-	err = o.client.spanner.DeleteRow(o.ctx, o.client.spannerTable, spanner.Key{o.name})
-	if err != nil {
-		return fmt.Errorf("delete operation (%s): %w", o.name, err)
-	}
-	return nil
+	return o.client.deleteOperation(o.ctx, o.name)
 }
 
 // SaveState saves a current state with the LRO resource
@@ -385,12 +551,83 @@ func (o *Operation[T]) State() *T {
 	return o.state
 }
 
+// Deadline returns the overall deadline of the operation, if set.
+func (o *Operation[T]) Deadline() (time.Time, bool) {
+	return o.deadline, !o.deadline.IsZero()
+}
+
 // InDevMode returns a true if the Operation is running locally
 func (o *Operation[T]) InDevMode() bool {
 	// We'll deactivate development mode if the service is running on Cloud Run
 	return o.devMode
 }
 
+/*
+AddChild registers child operations, for example those returned by other services, on the operation so that
+WaitForChildren can wait for them without the caller having to keep track of their names.
+
+The names are persisted in the ChildOperations column (ARRAY<STRING(MAX)>), which therefore survives asynchronous waits.
+Names which are already registered are ignored.
+*/
+func (o *Operation[T]) AddChild(names ...string) error {
+	if o.name == "" {
+		return fmt.Errorf("operation name is nil")
+	}
+
+	children, err := o.client.addChildOperations(o.ctx, o.name, names...)
+	if err != nil {
+		return err
+	}
+
+	o.childrenMu.Lock()
+	defer o.childrenMu.Unlock()
+	o.children = children
+
+	return nil
+}
+
+/*
+SetAnnotations sets key/value annotations on the operation, e.g. trace or tenant identifiers, which can be used to
+filter the operations returned by Client.ListOperations. The annotations are merged into the existing ones, and an
+annotation with an empty value is removed.
+
+Keys may only contain letters, digits and underscores. The annotations are persisted in the Annotations column (JSON).
+*/
+func (o *Operation[T]) SetAnnotations(annotations map[string]string) (map[string]string, error) {
+	if o.name == "" {
+		return nil, fmt.Errorf("operation name is nil")
+	}
+
+	return o.client.setAnnotations(o.ctx, o.name, annotations)
+}
+
+// Children returns the names of the child operations registered using AddChild.
+func (o *Operation[T]) Children() []string {
+	o.childrenMu.Lock()
+	defer o.childrenMu.Unlock()
+	return append([]string(nil), o.children...)
+}
+
+/*
+WaitForChildren waits for all the child operations registered using AddChild, in the same way as Wait with the
+WithChildOperations option. Any other WaitOption, such as WithTimeout or WithAsync, may be provided.
+
+Example:
+
+	res, err := otherServiceClient.LongMethod(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := op.AddChild(res.GetName()); err != nil {
+		return err
+	}
+	// ...
+	err = op.WaitForChildren(WithTimeout(10 * time.Minute))
+*/
+func (o *Operation[T]) WaitForChildren(opts ...WaitOption) error {
+	return o.Wait(append([]WaitOption{WithChildOperations(o.Children()...)}, opts...)...)
+}
+
 // WaitConfig is used to store the waiting configurations specified as functional WaitOption(s) in the Wait() and WaitAsync() methods.
 type WaitConfig struct {
 	// Standard Wait Configurations
@@ -406,8 +643,10 @@ type WaitConfig struct {
 
 	// Async configurations
 	asyncEnabled                   bool
-	resumePoint                    string // Once the wait is complete, resume at this point.
-	asyncChildGetOperationEndpoint string // The API endpoint which exposes a GetOperation method
+	resumePoint                    string     // Once the wait is complete, resume at this point.
+	resumeInput                    *anypb.Any // The input passed to the resumed invocation, if any.
+	attempt                        int        // The attempt of the resumed invocation, if it is a retry.
+	asyncChildGetOperationEndpoint string     // The API endpoint which exposes a GetOperation method
 }
 
 // WaitOption is a functional option for WaitConfig.
@@ -551,6 +790,12 @@ func (o *Operation[T]) Wait(opts ...WaitOption) error {
 		}
 	}
 
+	// Enforce the overall deadline of the operation, if any.
+	if err := o.failPastDeadline(); err != nil {
+		return err
+	}
+	o.applyDeadline(w)
+
 	// All options have been configures, start the wait.
 	startTime := time.Now()
 
@@ -601,6 +846,10 @@ func (o *Operation[T]) Wait(opts ...WaitOption) error {
 	// Async is not enabled, wait Synchronously
 	if !w.asyncEnabled {
 		err := waitSynchronouslyFn()
+		// The wait was capped to the overall deadline, which may therefore have passed in the meantime.
+		if deadlineErr := o.failPastDeadline(); deadlineErr != nil {
+			return deadlineErr
+		}
 		if err != nil {
 			return err
 		}
@@ -617,20 +866,36 @@ func (o *Operation[T]) Wait(opts ...WaitOption) error {
 			return err
 		}
 
-		err := o.client.spanner.UpdateRow(o.ctx, o.client.spannerTable, map[string]interface{}{
+		row := map[string]interface{}{
 			"key":                 o.name,
 			StateColumnName:       buffer.Bytes(),
 			ResumePointColumnName: w.resumePoint,
-		})
+		}
+		resumeInput, ok, err := o.resumeInputColumn(w)
 		if err != nil {
 			return err
 		}
+		if ok {
+			row[ResumeInputColumnName] = resumeInput
+		}
+		// The Attempt column is only written if retries are enabled, since it is optional.
+		if o.maxResumeAttempts > 1 {
+			row[AttemptColumnName] = int64(max(w.attempt, 1))
+		}
+		if err := o.client.spanner.UpdateRow(o.ctx, o.client.spannerTable, row); err != nil {
+			return err
+		}
 
 		// Always wait locally when in dev mode, we'll use some seriously cool recursion magic 😎.
 		if o.devMode {
 			// First we'll wait asynchronously
 			err := waitSynchronouslyFn()
 			if err != nil {
+				// Only failures to poll the child operations would stop the wait in production, a timeout of the
+				// children or any other error is returned as is.
+				if status.Code(err) == codes.Unavailable {
+					return o.failInfra(err)
+				}
 				return err
 			}
 			// And then run the callback function to 'simulate' a resumable operation
@@ -643,7 +908,7 @@ func (o *Operation[T]) Wait(opts ...WaitOption) error {
 			// Hand over the wait task to Google Cloud Workflows.
 			err := o.waitWithGoogleWorkflows(w)
 			if err != nil {
-				return err
+				return o.failInfra(err)
 			}
 			return nil
 		}
@@ -652,6 +917,17 @@ func (o *Operation[T]) Wait(opts ...WaitOption) error {
 	return nil
 }
 
+/*
+failInfra marks the operation as failed by the infrastructure, since nothing would resume it after the asynchronous
+wait could not be handed over or polled, and returns the original error.
+*/
+func (o *Operation[T]) failInfra(err error) error {
+	if failErr := o.fail(codes.Unavailable, ErrorSourceInfra, err); failErr != nil {
+		return errors.Join(err, failErr)
+	}
+	return err
+}
+
 // waitWithGoogleWorkflows triggers asynchronous waiting in a workflow.
 func (o *Operation[T]) waitWithGoogleWorkflows(cfg *WaitConfig) error {
 	// Prepare the Google Cloud Workflow arguments
@@ -706,7 +982,7 @@ func (o *Operation[T]) waitWithGoogleWorkflows(cfg *WaitConfig) error {
 	}
 
 	// Hand over the task of waiting to a dedicated Google Cloud Workflow
-	_, err = o.client.workflows.CreateExecution(o.ctx, &executionspb.CreateExecutionRequest{
+	execution, err := o.client.workflows.CreateExecution(o.ctx, &executionspb.CreateExecutionRequest{
 		Parent: o.client.workflowName,
 		Execution: &executionspb.Execution{
 			Argument:     string(argsBytes),
@@ -717,6 +993,37 @@ func (o *Operation[T]) waitWithGoogleWorkflows(cfg *WaitConfig) error {
 		// TODO: handle error types from Google explicitly.
 		return fmt.Errorf("launch google cloud workflows: %w", err)
 	}
+	// Record the execution, so that ReconcileOperations can re-drive the wait should the execution die.
+	o.client.recordExecution(o.ctx, o.name, execution.GetName())
 
 	return nil
 }
+
+// applyDeadline caps the sleep and timeout of the wait configuration to the remaining budget of the overall deadline of
+// the operation, if any.
+func (o *Operation[T]) applyDeadline(w *WaitConfig) {
+	if o.deadline.IsZero() {
+		return
+	}
+
+	remaining := max(time.Until(o.deadline), 0)
+	w.sleep = min(w.sleep, remaining)
+	w.timeout = min(w.timeout, remaining)
+}
+
+// failPastDeadline marks the operation as failed with a DeadlineExceeded status if its overall deadline has passed, and
+// returns an ErrOperationDeadlineExceeded error in that case.
+func (o *Operation[T]) failPastDeadline() error {
+	if o.deadline.IsZero() || time.Now().Before(o.deadline) {
+		return nil
+	}
+
+	err := ErrOperationDeadlineExceeded{
+		operation: o.name,
+		deadline:  o.deadline,
+	}
+	if failErr := o.fail(codes.DeadlineExceeded, ErrorSourceInfra, err); failErr != nil {
+		return failErr
+	}
+	return err
+}
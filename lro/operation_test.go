@@ -0,0 +1,318 @@
+package lro
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestOperation_applyDeadline(t *testing.T) {
+	tests := []struct {
+		name        string
+		deadline    time.Time
+		sleep       time.Duration
+		timeout     time.Duration
+		wantSleep   time.Duration
+		wantTimeout time.Duration
+	}{
+		{
+			name:        "NoDeadline",
+			sleep:       time.Hour,
+			timeout:     7 * time.Minute,
+			wantSleep:   time.Hour,
+			wantTimeout: 7 * time.Minute,
+		},
+		{
+			name:        "WithinBudget",
+			deadline:    time.Now().Add(time.Hour),
+			sleep:       time.Minute,
+			timeout:     7 * time.Minute,
+			wantSleep:   time.Minute,
+			wantTimeout: 7 * time.Minute,
+		},
+		{
+			name:        "BudgetExhausted",
+			deadline:    time.Now().Add(-time.Second),
+			sleep:       time.Minute,
+			timeout:     7 * time.Minute,
+			wantSleep:   0,
+			wantTimeout: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Operation[any]{name: "operations/123", deadline: tt.deadline}
+			w := &WaitConfig{sleep: tt.sleep, timeout: tt.timeout}
+
+			o.applyDeadline(w)
+			if w.sleep != tt.wantSleep {
+				t.Errorf("sleep = %v, want %v", w.sleep, tt.wantSleep)
+			}
+			if w.timeout != tt.wantTimeout {
+				t.Errorf("timeout = %v, want %v", w.timeout, tt.wantTimeout)
+			}
+		})
+	}
+
+	// Waits which would outlive the deadline are capped to the remaining budget, rather than failing up front.
+	t.Run("CappedToBudget", func(t *testing.T) {
+		o := &Operation[any]{name: "operations/123", deadline: time.Now().Add(time.Minute)}
+		w := &WaitConfig{sleep: time.Hour, timeout: time.Hour}
+		o.applyDeadline(w)
+		if w.sleep <= 0 || w.sleep > time.Minute {
+			t.Errorf("sleep = %v, want at most %v", w.sleep, time.Minute)
+		}
+		if w.timeout <= 0 || w.timeout > time.Minute {
+			t.Errorf("timeout = %v, want at most %v", w.timeout, time.Minute)
+		}
+	})
+}
+
+func TestOperation_failPastDeadline(t *testing.T) {
+	for _, deadline := range []time.Time{{}, time.Now().Add(time.Hour)} {
+		o := &Operation[any]{name: "operations/123", deadline: deadline}
+		if err := o.failPastDeadline(); err != nil {
+			t.Errorf("failPastDeadline() with deadline %v error = %v, want nil", deadline, err)
+		}
+	}
+}
+
+func TestOperation_stamp(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	t.Cleanup(func() { now = time.Now })
+
+	o := &Operation[any]{}
+	first := o.stamp()
+	if !first.Equal(clock) {
+		t.Errorf("stamp() = %v, want %v", first, clock)
+	}
+
+	// The timestamp advances across updates, even if the clock did not.
+	second := o.stamp()
+	if !second.After(first) {
+		t.Errorf("stamp() = %v, want after %v", second, first)
+	}
+
+	// And follows the clock once it moves ahead again.
+	clock = clock.Add(time.Minute)
+	third := o.stamp()
+	if !third.Equal(clock) {
+		t.Errorf("stamp() = %v, want %v", third, clock)
+	}
+	if !third.After(second) {
+		t.Errorf("stamp() = %v, want after %v", third, second)
+	}
+}
+
+func TestOperation_stamp_Concurrent(t *testing.T) {
+	o := &Operation[any]{}
+
+	stamps := make(chan time.Time, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(stamps); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stamps <- o.stamp()
+		}()
+	}
+	wg.Wait()
+	close(stamps)
+
+	// Each concurrent update gets a distinct timestamp.
+	seen := map[time.Time]bool{}
+	for stamp := range stamps {
+		if seen[stamp] {
+			t.Errorf("stamp() = %v, returned more than once", stamp)
+		}
+		seen[stamp] = true
+	}
+}
+
+// newTestClient returns a Client connected to the Spanner database configured using the LRO_TEST_SPANNER_* env, or
+// skips the test if it is not configured.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	config := &SpannerConfig{
+		Project:  os.Getenv("LRO_TEST_SPANNER_PROJECT"),
+		Instance: os.Getenv("LRO_TEST_SPANNER_INSTANCE"),
+		Database: os.Getenv("LRO_TEST_SPANNER_DATABASE"),
+	}
+	if config.Project == "" || config.Instance == "" || config.Database == "" {
+		t.Skip("LRO_TEST_SPANNER_PROJECT, LRO_TEST_SPANNER_INSTANCE and LRO_TEST_SPANNER_DATABASE are required")
+	}
+
+	client, err := NewClient(context.Background(), config)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func TestOperation_ConcurrentUpdates(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	for i := 0; i < 5; i++ {
+		op, err := NewOperation[any](ctx, client)
+		if err != nil {
+			t.Fatalf("NewOperation() error = %v", err)
+		}
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 2)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := op.SetMetadata(wrapperspb.String("progress"))
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			errs <- op.Done(wrapperspb.String("result"))
+		}()
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("update error = %v", err)
+			}
+		}
+
+		got, err := op.GetOperation()
+		if err != nil {
+			t.Fatalf("GetOperation() error = %v", err)
+		}
+		if !got.GetDone() || got.GetResponse() == nil {
+			t.Errorf("GetOperation() = %v, want done with response", got)
+		}
+		if got.GetMetadata() == nil {
+			t.Errorf("GetOperation() = %v, want metadata", got)
+		}
+	}
+}
+
+func TestOperation_Complete(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	op, err := NewOperation[any](ctx, client)
+	if err != nil {
+		t.Fatalf("NewOperation() error = %v", err)
+	}
+	got, err := op.Complete(wrapperspb.String("result"))
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if got.GetName() != op.Name() || !got.GetDone() {
+		t.Errorf("Complete() = %v, want done operation %s", got, op.Name())
+	}
+	res := &wrapperspb.StringValue{}
+	if err := got.GetResponse().UnmarshalTo(res); err != nil {
+		t.Fatalf("Complete() response error = %v", err)
+	}
+	if res.GetValue() != "result" {
+		t.Errorf("Complete() response = %v, want %v", res.GetValue(), "result")
+	}
+
+	// The returned operation is the one which was written.
+	stored, err := op.GetOperation()
+	if err != nil {
+		t.Fatalf("GetOperation() error = %v", err)
+	}
+	if !proto.Equal(stored, got) {
+		t.Errorf("GetOperation() = %v, want %v", stored, got)
+	}
+}
+
+func TestOperation_AddChild(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	children := make([]*Operation[any], 2)
+	for i := range children {
+		child, err := NewOperation[any](ctx, client)
+		if err != nil {
+			t.Fatalf("NewOperation() error = %v", err)
+		}
+		if err := child.Done(nil); err != nil {
+			t.Fatalf("Done() error = %v", err)
+		}
+		children[i] = child
+	}
+
+	parent, err := NewOperation[any](ctx, client)
+	if err != nil {
+		t.Fatalf("NewOperation() error = %v", err)
+	}
+	for _, child := range children {
+		if err := parent.AddChild(child.Name()); err != nil {
+			t.Fatalf("AddChild() error = %v", err)
+		}
+	}
+
+	// The children are persisted, and available when the operation is resumed.
+	resumed, err := NewOperation[any](ctx, client, WithExistingOperation(parent.Name()))
+	if err != nil {
+		t.Fatalf("NewOperation() error = %v", err)
+	}
+	want := []string{children[0].Name(), children[1].Name()}
+	if got := resumed.Children(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Children() = %v, want %v", got, want)
+	}
+	if err := resumed.WaitForChildren(WithTimeout(time.Minute), WithPollFrequency(100*time.Millisecond)); err != nil {
+		t.Errorf("WaitForChildren() error = %v", err)
+	}
+}
+
+func TestOperationFromContext(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	op, err := NewOperation[wrapperspb.StringValue](ctx, client)
+	if err != nil {
+		t.Fatalf("NewOperation() error = %v", err)
+	}
+
+	// The operation's context carries the operation itself.
+	got, ok := OperationFromContext[wrapperspb.StringValue](op.Context())
+	if !ok || got != op {
+		t.Errorf("OperationFromContext() = %v, %v, want %v, true", got, ok, op)
+	}
+
+	// A different type parameter does not match the operation.
+	if got, ok := OperationFromContext[any](op.Context()); ok {
+		t.Errorf("OperationFromContext[any]() = %v, %v, want nil, false", got, ok)
+	}
+
+	// The ctx provided to NewOperation is not modified.
+	if got, ok := OperationFromContext[wrapperspb.StringValue](ctx); ok {
+		t.Errorf("OperationFromContext() = %v, %v, want nil, false for a context without an operation", got, ok)
+	}
+}
+
+func TestWithOperation(t *testing.T) {
+	op := &Operation[any]{name: "operations/123"}
+	ctx := WithOperation(context.Background(), op)
+
+	got, ok := OperationFromContext[any](ctx)
+	if !ok || got != op {
+		t.Errorf("OperationFromContext() = %v, %v, want %v, true", got, ok, op)
+	}
+	if _, ok := OperationFromContext[wrapperspb.StringValue](ctx); ok {
+		t.Errorf("OperationFromContext[wrapperspb.StringValue]() ok = true, want false")
+	}
+	if _, ok := OperationFromContext[any](context.Background()); ok {
+		t.Errorf("OperationFromContext() ok = true, want false for a context without an operation")
+	}
+}
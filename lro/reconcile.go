@@ -0,0 +1,154 @@
+package lro
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"cloud.google.com/go/spanner"
+	"cloud.google.com/go/workflows/executions/apiv1/executionspb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/proto"
+)
+
+// ExecutionColumnName is the column name used in spanner to store the name of the Google Cloud Workflows execution
+// waiting on an operation (if used)
+const ExecutionColumnName = "Execution"
+
+// ReconcileConfig is used to configure how ReconcileOperations detects stuck operations.
+type ReconcileConfig struct {
+	maxAge time.Duration
+}
+
+// ReconcileOption is a functional option for the ReconcileOperations method.
+type ReconcileOption func(*ReconcileConfig)
+
+/*
+WithMaxExecutionAge considers the executions which are still running after the provided duration as stuck as well.
+These are cancelled before the wait is re-launched, so that the operation is not resumed twice.
+
+By default, only the executions which failed or were cancelled are considered stuck. Make sure the duration exceeds
+the longest wait, i.e. the sleep and timeout passed to Wait, otherwise healthy waits are re-launched.
+*/
+func WithMaxExecutionAge(maxAge time.Duration) ReconcileOption {
+	return func(c *ReconcileConfig) {
+		c.maxAge = maxAge
+	}
+}
+
+/*
+ReconcileOperations re-drives the operations which are stuck waiting on a Google Cloud Workflows execution which died,
+e.g. due to quota exhaustion or a crash, and would otherwise never be resumed.
+
+The operations which are not done and have a resume point are considered, and the wait of those whose execution failed
+or was cancelled is re-launched with the same arguments. Use WithMaxExecutionAge to re-drive hung executions as well.
+It is intended to be run periodically, e.g. from a Cloud Scheduler job, and returns the names of the re-driven
+operations.
+
+This requires the optional Execution column (STRING(MAX)), which records the execution waiting on each operation.
+Failures to re-drive a single operation do not stop the others from being re-driven, the first one is returned.
+*/
+func (c *Client) ReconcileOperations(ctx context.Context, opts ...ReconcileOption) ([]string, error) {
+	cfg := &ReconcileConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	stmt := spanner.Statement{
+		SQL: fmt.Sprintf("SELECT key, %s, %s FROM %s WHERE %s IS NOT NULL AND %s IS NOT NULL",
+			OperationColumnName, ExecutionColumnName, c.spannerTable, ResumePointColumnName, ExecutionColumnName),
+	}
+	it := c.spanner.Client().Single().Query(ctx, stmt)
+	defer it.Stop()
+
+	var reconciled []string
+	var firstErr error
+	for {
+		row, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return reconciled, fmt.Errorf("query operations from database: %w", err)
+		}
+
+		var name, execution string
+		var data []byte
+		if err := row.Columns(&name, &data, &execution); err != nil {
+			return reconciled, fmt.Errorf("read operation from database: %w", err)
+		}
+		op := &longrunningpb.Operation{}
+		if err := proto.Unmarshal(data, op); err != nil {
+			return reconciled, fmt.Errorf("unmarshal operation: %w", err)
+		}
+		if op.GetDone() {
+			continue
+		}
+
+		redriven, err := c.redrive(ctx, name, execution, cfg)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if redriven {
+			reconciled = append(reconciled, name)
+		}
+	}
+
+	return reconciled, firstErr
+}
+
+/*
+redrive re-launches the wait of the provided operation if its execution is stuck, and records the new execution.
+It returns whether the wait was re-launched.
+*/
+func (c *Client) redrive(ctx context.Context, operation string, executionName string, cfg *ReconcileConfig) (bool, error) {
+	execution, err := c.workflows.GetExecution(ctx, &executionspb.GetExecutionRequest{Name: executionName})
+	if err != nil {
+		return false, fmt.Errorf("get execution (%s) of operation (%s): %w", executionName, operation, err)
+	}
+
+	switch execution.GetState() {
+	case executionspb.Execution_FAILED, executionspb.Execution_CANCELLED:
+	case executionspb.Execution_ACTIVE, executionspb.Execution_QUEUED:
+		if cfg.maxAge <= 0 || execution.GetStartTime() == nil || time.Since(execution.GetStartTime().AsTime()) < cfg.maxAge {
+			return false, nil
+		}
+		_, err := c.workflows.CancelExecution(ctx, &executionspb.CancelExecutionRequest{Name: executionName})
+		if err != nil {
+			return false, fmt.Errorf("cancel execution (%s) of operation (%s): %w", executionName, operation, err)
+		}
+	default:
+		return false, nil
+	}
+
+	// The argument of the execution holds everything needed to wait and resume the operation again.
+	redriven, err := c.workflows.CreateExecution(ctx, &executionspb.CreateExecutionRequest{
+		Parent: c.workflowName,
+		Execution: &executionspb.Execution{
+			Argument:     execution.GetArgument(),
+			CallLogLevel: executionspb.Execution_LOG_ALL_CALLS,
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("relaunch google cloud workflows for operation (%s): %w", operation, err)
+	}
+	c.recordExecution(ctx, operation, redriven.GetName())
+
+	return true, nil
+}
+
+// recordExecution records the Google Cloud Workflows execution waiting on the operation.
+// The Execution column is optional, so this fails softly.
+func (c *Client) recordExecution(ctx context.Context, operation string, execution string) {
+	if c.spanner == nil {
+		return
+	}
+	_ = c.spanner.UpdateRow(ctx, c.spannerTable, map[string]interface{}{
+		"key":               operation,
+		ExecutionColumnName: execution,
+	})
+}
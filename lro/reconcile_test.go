@@ -0,0 +1,94 @@
+package lro
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/workflows/executions/apiv1/executionspb"
+	"go.alis.build/lro/lrotest"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestClient_redrive(t *testing.T) {
+	const workflowName = "projects/p/locations/l/workflows/alis-managed-operations"
+	tests := []struct {
+		name         string
+		state        executionspb.Execution_State
+		startedAgo   time.Duration
+		opts         []ReconcileOption
+		wantRedriven bool
+		wantCancel   bool
+	}{
+		{name: "failed", state: executionspb.Execution_FAILED, wantRedriven: true},
+		{name: "cancelled", state: executionspb.Execution_CANCELLED, wantRedriven: true},
+		{name: "succeeded", state: executionspb.Execution_SUCCEEDED},
+		{name: "active", state: executionspb.Execution_ACTIVE, startedAgo: 2 * time.Hour},
+		{
+			name:       "active within max age",
+			state:      executionspb.Execution_ACTIVE,
+			startedAgo: time.Hour,
+			opts:       []ReconcileOption{WithMaxExecutionAge(2 * time.Hour)},
+		},
+		{
+			name:         "active beyond max age",
+			state:        executionspb.Execution_ACTIVE,
+			startedAgo:   3 * time.Hour,
+			opts:         []ReconcileOption{WithMaxExecutionAge(2 * time.Hour)},
+			wantRedriven: true,
+			wantCancel:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workflows := lrotest.NewFakeWorkflowsClient()
+			workflows.SetExecution(&executionspb.Execution{
+				Name:      workflowName + "/executions/dead",
+				Argument:  `{"operationId":"123"}`,
+				State:     tt.state,
+				StartTime: timestamppb.New(time.Now().Add(-tt.startedAgo)),
+			})
+			client := &Client{workflows: workflows, workflowName: workflowName}
+			cfg := &ReconcileConfig{}
+			for _, opt := range tt.opts {
+				opt(cfg)
+			}
+
+			redriven, err := client.redrive(context.Background(), "operations/123", workflowName+"/executions/dead", cfg)
+			if err != nil {
+				t.Fatalf("redrive() error = %v", err)
+			}
+			if redriven != tt.wantRedriven {
+				t.Errorf("redrive() = %v, want %v", redriven, tt.wantRedriven)
+			}
+
+			requests := workflows.Requests()
+			if !tt.wantRedriven {
+				if len(requests) != 0 {
+					t.Errorf("CreateExecution() called %d times, want 0", len(requests))
+				}
+				return
+			}
+			if len(requests) != 1 {
+				t.Fatalf("CreateExecution() called %d times, want 1", len(requests))
+			}
+			if got := requests[0].GetParent(); got != workflowName {
+				t.Errorf("Parent = %v, want %v", got, workflowName)
+			}
+			if got := requests[0].GetExecution().GetArgument(); got != `{"operationId":"123"}` {
+				t.Errorf("Argument = %v, want the argument of the stuck execution", got)
+			}
+			if got := len(workflows.Cancelled()) == 1; got != tt.wantCancel {
+				t.Errorf("Cancelled() = %v, want cancelled %v", workflows.Cancelled(), tt.wantCancel)
+			}
+		})
+	}
+}
+
+func TestClient_redrive_ExecutionNotFound(t *testing.T) {
+	client := &Client{workflows: lrotest.NewFakeWorkflowsClient()}
+	_, err := client.redrive(context.Background(), "operations/123", "executions/unknown", &ReconcileConfig{})
+	if err == nil {
+		t.Errorf("redrive() error = nil, want an error")
+	}
+}
@@ -0,0 +1,83 @@
+package lro
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ResumeInputColumnName is the column name used in spanner to store the input passed to a resumed operation (if used)
+const ResumeInputColumnName = "ResumeInput"
+
+/*
+WithResumeInput stashes an input message which the resumed invocation of the method can retrieve using ResumeInput.
+
+Unlike the State, which is owned by the operation and carried across all of its waits, the input is only passed to
+the invocation resuming from this wait. It is only applicable together with WithAsync, and requires the optional
+ResumeInput column (BYTES(MAX)).
+
+Example:
+
+	op.Wait(WithSleep(time.Hour), WithAsync("resumePoint1"), WithResumeInput(&pb.Batch{Ids: ids}))
+*/
+func WithResumeInput(input proto.Message) WaitOption {
+	return func(w *WaitConfig) error {
+		resumeInput, err := anypb.New(input)
+		if err != nil {
+			return fmt.Errorf("marshal resume input: %w", err)
+		}
+		w.resumeInput = resumeInput
+		return nil
+	}
+}
+
+/*
+ResumeInput returns the input stashed using WithResumeInput by the wait this invocation is resuming from, or nil if
+none was provided.
+
+Example:
+
+	if input := op.ResumeInput(); input != nil {
+		batch := &pb.Batch{}
+		if err := input.UnmarshalTo(batch); err != nil {
+			return err
+		}
+	}
+*/
+func (o *Operation[T]) ResumeInput() *anypb.Any {
+	return o.resumeInput
+}
+
+// loadResumeInput reads the input stashed for this invocation, if any.
+// The ResumeInput column is optional, so we'll fail softly if unable to read it.
+func (o *Operation[T]) loadResumeInput() {
+	row, err := o.client.spanner.ReadRow(o.ctx, o.client.spannerTable, spanner.Key{o.name}, []string{ResumeInputColumnName}, nil)
+	if err != nil {
+		return
+	}
+	data, ok := row[ResumeInputColumnName].([]byte)
+	if !ok || len(data) == 0 {
+		return
+	}
+	resumeInput := &anypb.Any{}
+	if err := proto.Unmarshal(data, resumeInput); err == nil {
+		o.resumeInput = resumeInput
+	}
+}
+
+// resumeInputColumn returns the value to write to the ResumeInput column when waiting asynchronously, and whether it
+// needs to be written at all. The input of the current invocation is cleared if no new input is provided, so that it
+// is not passed to the next invocation again.
+func (o *Operation[T]) resumeInputColumn(w *WaitConfig) ([]byte, bool, error) {
+	if w.resumeInput != nil {
+		data, err := proto.Marshal(w.resumeInput)
+		if err != nil {
+			return nil, false, fmt.Errorf("marshal resume input: %w", err)
+		}
+		return data, true, nil
+	}
+	// The column is known to exist if an input was read from it.
+	return nil, o.resumeInput != nil, nil
+}
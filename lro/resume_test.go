@@ -0,0 +1,101 @@
+package lro
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestOperation_resumeInputColumn(t *testing.T) {
+	input, err := anypb.New(wrapperspb.String("payload"))
+	if err != nil {
+		t.Fatalf("anypb.New() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		current     *anypb.Any
+		opts        []WaitOption
+		wantWrite   bool
+		wantPayload bool
+	}{
+		{name: "no input", wantWrite: false},
+		{name: "new input", opts: []WaitOption{WithResumeInput(wrapperspb.String("payload"))}, wantWrite: true, wantPayload: true},
+		{name: "clear current input", current: input, wantWrite: true, wantPayload: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &WaitConfig{}
+			for _, opt := range tt.opts {
+				if err := opt(w); err != nil {
+					t.Fatalf("WaitOption error = %v", err)
+				}
+			}
+			op := &Operation[any]{resumeInput: tt.current}
+			data, write, err := op.resumeInputColumn(w)
+			if err != nil {
+				t.Fatalf("resumeInputColumn() error = %v", err)
+			}
+			if write != tt.wantWrite {
+				t.Errorf("resumeInputColumn() write = %v, want %v", write, tt.wantWrite)
+			}
+			if (data != nil) != tt.wantPayload {
+				t.Errorf("resumeInputColumn() data = %v, want payload %v", data, tt.wantPayload)
+			}
+			if tt.wantPayload {
+				got := &anypb.Any{}
+				if err := proto.Unmarshal(data, got); err != nil {
+					t.Fatalf("proto.Unmarshal() error = %v", err)
+				}
+				if !proto.Equal(got, input) {
+					t.Errorf("resumeInputColumn() = %v, want %v", got, input)
+				}
+			}
+		})
+	}
+}
+
+func TestOperation_ResumeInput(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	var resumed *Operation[any]
+	op, err := NewOperation[any](ctx, client, WithCallbackFn(func(ctx context.Context) {
+		var err error
+		resumed, err = NewOperation[any](ctx, client)
+		if err != nil {
+			t.Errorf("NewOperation() error = %v", err)
+		}
+	}))
+	if err != nil {
+		t.Fatalf("NewOperation() error = %v", err)
+	}
+	if !op.InDevMode() {
+		t.Skip("the resumed invocation is only simulated in dev mode")
+	}
+	if got := op.ResumeInput(); got != nil {
+		t.Errorf("ResumeInput() = %v, want nil", got)
+	}
+
+	if err := op.Wait(WithAsync("step2"), WithResumeInput(wrapperspb.String("payload"))); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if resumed == nil {
+		t.Fatal("the operation was not resumed")
+	}
+	if resumed.ResumePoint() != "step2" {
+		t.Errorf("ResumePoint() = %v, want step2", resumed.ResumePoint())
+	}
+	got := &wrapperspb.StringValue{}
+	if input := resumed.ResumeInput(); input == nil {
+		t.Fatal("ResumeInput() = nil, want payload")
+	} else if err := input.UnmarshalTo(got); err != nil {
+		t.Fatalf("UnmarshalTo() error = %v", err)
+	}
+	if got.GetValue() != "payload" {
+		t.Errorf("ResumeInput() = %v, want payload", got.GetValue())
+	}
+}
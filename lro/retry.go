@@ -0,0 +1,85 @@
+package lro
+
+import (
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+// AttemptColumnName is the column name used in spanner to store the attempt of the current resume point (if used)
+const AttemptColumnName = "Attempt"
+
+/*
+WithMaxResumeAttempts retries a resumed invocation which fails, by re-scheduling it up to n attempts in total before
+marking the operation as failed. This is useful for async steps which are flaky.
+
+A failure is reported using op.Error, which re-schedules the invocation to resume at the same resume point, with the
+same input, instead of failing the operation, as long as attempts remain. Each retry waits a little longer than the
+previous one. The state is persisted as is, so a retried invocation observes any changes made by the failed one.
+Use op.Attempt to retrieve the attempt of the current invocation.
+
+The option should be provided to NewOperation by every invocation of the method, and requires the optional Attempt
+column (INT64). The attempts are counted per resume point, i.e. reset by each call to Wait using WithAsync.
+
+Example:
+
+	op, err := lro.NewOperation[MyState](ctx, client, lro.WithMaxResumeAttempts(3))
+	...
+	case "export":
+		if err := export(ctx); err != nil {
+			return nil, op.Error(err) // re-scheduled unless this was the third attempt
+		}
+*/
+func WithMaxResumeAttempts(n int) OperationOption {
+	return func(opts *OperationOptions) {
+		opts.maxResumeAttempts = n
+	}
+}
+
+/*
+Attempt returns the attempt of the current invocation at its resume point, starting at 1.
+It is only incremented for invocations re-scheduled due to WithMaxResumeAttempts.
+*/
+func (o *Operation[T]) Attempt() int {
+	return o.attempt
+}
+
+// resumeRetryDelay returns the duration to wait before re-running the provided failed attempt, and is overridden in
+// tests.
+var resumeRetryDelay = func(attempt int) time.Duration {
+	delay := 10 * time.Second << (attempt - 1)
+	if delay <= 0 || delay > 10*time.Minute {
+		return 10 * time.Minute
+	}
+	return delay
+}
+
+// retryable returns whether a failure of the current invocation should be retried rather than fail the operation.
+func (o *Operation[T]) retryable() bool {
+	return o.resumePoint != "" && o.attempt < o.maxResumeAttempts
+}
+
+// retry re-schedules the current invocation to resume at the same resume point, with the same input.
+func (o *Operation[T]) retry() error {
+	return o.Wait(WithSleep(resumeRetryDelay(o.attempt)), WithAsync(o.resumePoint), func(w *WaitConfig) error {
+		w.attempt = o.attempt + 1
+		w.resumeInput = o.resumeInput
+		return nil
+	})
+}
+
+// loadAttempt reads the attempt of the current invocation, if retries are enabled.
+// The Attempt column is optional, so we'll fail softly if unable to read it.
+func (o *Operation[T]) loadAttempt() {
+	if o.maxResumeAttempts <= 1 {
+		return
+	}
+	row, err := o.client.spanner.Client().Single().ReadRow(o.ctx, o.client.spannerTable, spanner.Key{o.name}, []string{AttemptColumnName})
+	if err != nil {
+		return
+	}
+	var attempt spanner.NullInt64
+	if err := row.Columns(&attempt); err == nil && attempt.Valid && attempt.Int64 > 0 {
+		o.attempt = int(attempt.Int64)
+	}
+}
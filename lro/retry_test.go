@@ -0,0 +1,112 @@
+package lro
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOperation_retryable(t *testing.T) {
+	tests := []struct {
+		name        string
+		resumePoint string
+		attempt     int
+		maxAttempts int
+		want        bool
+	}{
+		{name: "retries disabled", resumePoint: "step", attempt: 1, maxAttempts: 0, want: false},
+		{name: "not resumed", resumePoint: "", attempt: 1, maxAttempts: 3, want: false},
+		{name: "attempts remaining", resumePoint: "step", attempt: 2, maxAttempts: 3, want: true},
+		{name: "attempts exhausted", resumePoint: "step", attempt: 3, maxAttempts: 3, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := &Operation[any]{resumePoint: tt.resumePoint, attempt: tt.attempt, maxResumeAttempts: tt.maxAttempts}
+			if got := op.retryable(); got != tt.want {
+				t.Errorf("retryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResumeRetryDelay(t *testing.T) {
+	if got := resumeRetryDelay(1); got != 10*time.Second {
+		t.Errorf("resumeRetryDelay(1) = %v, want %v", got, 10*time.Second)
+	}
+	if got := resumeRetryDelay(2); got != 20*time.Second {
+		t.Errorf("resumeRetryDelay(2) = %v, want %v", got, 20*time.Second)
+	}
+	if got := resumeRetryDelay(100); got != 10*time.Minute {
+		t.Errorf("resumeRetryDelay(100) = %v, want %v", got, 10*time.Minute)
+	}
+}
+
+// The test expects the operations table to have the optional 'Attempt INT64' column.
+func TestOperation_WithMaxResumeAttempts(t *testing.T) {
+	client := newTestClient(t)
+	resumeRetryDelay = func(int) time.Duration { return 0 }
+	t.Cleanup(func() {
+		resumeRetryDelay = func(attempt int) time.Duration { return 10 * time.Second << (attempt - 1) }
+	})
+
+	errFlaky := errors.New("flaky")
+	tests := []struct {
+		name         string
+		succeedOn    int
+		wantAttempts []int
+		wantFailed   bool
+	}{
+		{name: "success on retry", succeedOn: 2, wantAttempts: []int{1, 2}},
+		{name: "attempts exhausted", succeedOn: 0, wantAttempts: []int{1, 2, 3}, wantFailed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var name string
+			var attempts []int
+			var handler func(ctx context.Context)
+			handler = func(ctx context.Context) {
+				op, err := NewOperation[any](ctx, client, WithCallbackFn(handler), WithMaxResumeAttempts(3))
+				if err != nil {
+					t.Errorf("NewOperation() error = %v", err)
+					return
+				}
+				name = op.Name()
+				switch op.ResumePoint() {
+				case "":
+					err = op.Wait(WithAsync("flaky"))
+				case "flaky":
+					attempts = append(attempts, op.Attempt())
+					if op.Attempt() == tt.succeedOn {
+						err = op.Done(nil)
+					} else {
+						err = op.Error(errFlaky)
+					}
+				}
+				if err != nil {
+					t.Errorf("invocation error = %v", err)
+				}
+			}
+			handler(context.Background())
+
+			if !reflect.DeepEqual(attempts, tt.wantAttempts) {
+				t.Errorf("attempts = %v, want %v", attempts, tt.wantAttempts)
+			}
+			op, err := NewOperation[any](context.Background(), client, WithExistingOperation(name))
+			if err != nil {
+				t.Fatalf("NewOperation() error = %v", err)
+			}
+			got, err := op.GetOperation()
+			if err != nil {
+				t.Fatalf("GetOperation() error = %v", err)
+			}
+			if !got.GetDone() {
+				t.Errorf("GetOperation() = %v, want done", got)
+			}
+			if failed := got.GetError() != nil; failed != tt.wantFailed {
+				t.Errorf("GetOperation() error = %v, want failed %v", got.GetError(), tt.wantFailed)
+			}
+		})
+	}
+}
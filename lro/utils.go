@@ -7,6 +7,7 @@ import (
 
 	"cloud.google.com/go/longrunning/autogen/longrunningpb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -95,3 +96,56 @@ func UnmarshalOperation(operation *longrunningpb.Operation, response, metadata p
 
 	return nil
 }
+
+/*
+Inspect unpacks the metadata of the operation into M and its response into R, and reports whether it is done.
+
+If the operation failed, the error result is returned as a gRPC status error, which can be inspected using
+status.FromError, along with the metadata. Messages which are not set on the operation are returned as nil, for
+example the response of an operation which is still running.
+
+Example:
+
+	op, err := client.GetOperation(ctx, &longrunningpb.GetOperationRequest{Name: name})
+	metadata, response, done, err := lro.Inspect[*pb.ReportMetadata, *pb.Report](op)
+*/
+func Inspect[M, R proto.Message](operation *longrunningpb.Operation) (M, R, bool, error) {
+	var metadata M
+	var response R
+
+	if operation.GetMetadata() != nil {
+		metadata = metadata.ProtoReflect().Type().New().Interface().(M)
+		if err := operation.GetMetadata().UnmarshalTo(metadata); err != nil {
+			return metadata, response, operation.GetDone(), fmt.Errorf("unmarshal metadata of operation (%s): %w", operation.GetName(), err)
+		}
+	}
+
+	if operation.GetError() != nil {
+		return metadata, response, operation.GetDone(), status.ErrorProto(operation.GetError())
+	}
+
+	if operation.GetResponse() != nil {
+		response = response.ProtoReflect().Type().New().Interface().(R)
+		if err := operation.GetResponse().UnmarshalTo(response); err != nil {
+			return metadata, response, operation.GetDone(), fmt.Errorf("unmarshal response of operation (%s): %w", operation.GetName(), err)
+		}
+	}
+
+	return metadata, response, operation.GetDone(), nil
+}
+
+// appendUnique appends the values to the slice, skipping empty values and those already present.
+func appendUnique(slice []string, values ...string) []string {
+	seen := make(map[string]bool, len(slice))
+	for _, s := range slice {
+		seen[s] = true
+	}
+	for _, v := range values {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		slice = append(slice, v)
+	}
+	return slice
+}
@@ -0,0 +1,85 @@
+package lro
+
+import (
+	"testing"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestInspect(t *testing.T) {
+	metadata, err := anypb.New(wrapperspb.Int32(75))
+	if err != nil {
+		t.Fatalf("anypb.New() error = %v", err)
+	}
+	response, err := anypb.New(wrapperspb.String("report"))
+	if err != nil {
+		t.Fatalf("anypb.New() error = %v", err)
+	}
+
+	t.Run("Done", func(t *testing.T) {
+		op := &longrunningpb.Operation{
+			Name:     "operations/123",
+			Metadata: metadata,
+			Done:     true,
+			Result:   &longrunningpb.Operation_Response{Response: response},
+		}
+		gotMetadata, gotResponse, done, err := Inspect[*wrapperspb.Int32Value, *wrapperspb.StringValue](op)
+		if err != nil {
+			t.Fatalf("Inspect() error = %v", err)
+		}
+		if !done {
+			t.Errorf("Inspect() done = false, want true")
+		}
+		if gotMetadata.GetValue() != 75 {
+			t.Errorf("Inspect() metadata = %v, want 75", gotMetadata)
+		}
+		if gotResponse.GetValue() != "report" {
+			t.Errorf("Inspect() response = %v, want report", gotResponse)
+		}
+	})
+
+	t.Run("Running", func(t *testing.T) {
+		op := &longrunningpb.Operation{Name: "operations/123", Metadata: metadata}
+		gotMetadata, gotResponse, done, err := Inspect[*wrapperspb.Int32Value, *wrapperspb.StringValue](op)
+		if err != nil {
+			t.Fatalf("Inspect() error = %v", err)
+		}
+		if done {
+			t.Errorf("Inspect() done = true, want false")
+		}
+		if gotMetadata.GetValue() != 75 {
+			t.Errorf("Inspect() metadata = %v, want 75", gotMetadata)
+		}
+		if gotResponse != nil {
+			t.Errorf("Inspect() response = %v, want nil", gotResponse)
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		op := &longrunningpb.Operation{
+			Name:     "operations/123",
+			Metadata: metadata,
+			Done:     true,
+			Result:   &longrunningpb.Operation_Error{Error: &statuspb.Status{Code: int32(codes.NotFound), Message: "report not found"}},
+		}
+		gotMetadata, _, done, err := Inspect[*wrapperspb.Int32Value, *wrapperspb.StringValue](op)
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Inspect() error = %v, want code NotFound", err)
+		}
+		if !done || gotMetadata.GetValue() != 75 {
+			t.Errorf("Inspect() = %v, %v, want done with metadata", gotMetadata, done)
+		}
+	})
+
+	t.Run("MismatchedType", func(t *testing.T) {
+		op := &longrunningpb.Operation{Name: "operations/123", Done: true, Result: &longrunningpb.Operation_Response{Response: response}}
+		if _, _, _, err := Inspect[*wrapperspb.Int32Value, *wrapperspb.Int64Value](op); err == nil {
+			t.Errorf("Inspect() error = nil, want an unmarshal error")
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+/*
+Any returns whether any row of the specified table matches the provided filter, e.g. a statement returned by the
+filtering package. A nil or empty filter checks whether the table has any rows at all.
+
+It runs a SELECT EXISTS query, which stops at the first matching row, and is therefore cheaper than counting the rows
+when only their existence matters.
+*/
+func (s *Client) Any(ctx context.Context, tableName string, filter *spanner.Statement) (bool, error) {
+	it := s.single().QueryWithOptions(ctx, existsStatement(tableName, filter), s.queryOptions())
+	defer it.Stop()
+
+	row, err := it.Next()
+	if errors.Is(err, iterator.Done) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var exists bool
+	if err := row.Column(0, &exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// existsStatement returns the SELECT EXISTS statement checking whether any row of the table matches the filter.
+func existsStatement(tableName string, filter *spanner.Statement) spanner.Statement {
+	query := fmt.Sprintf("SELECT 1 FROM %s", tableName)
+	params := map[string]interface{}{}
+	if filter != nil && filter.SQL != "" {
+		query += " WHERE " + filter.SQL
+		if len(filter.Params) > 0 {
+			params = filter.Params
+		}
+	}
+
+	return spanner.Statement{
+		SQL:    fmt.Sprintf("SELECT EXISTS(%s LIMIT 1)", query),
+		Params: params,
+	}
+}
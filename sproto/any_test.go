@@ -0,0 +1,76 @@
+package sproto
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func Test_existsStatement(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *spanner.Statement
+		want   spanner.Statement
+	}{
+		{
+			name:   "no filter",
+			filter: nil,
+			want:   spanner.Statement{SQL: "SELECT EXISTS(SELECT 1 FROM test_table LIMIT 1)", Params: map[string]interface{}{}},
+		},
+		{
+			name:   "filter",
+			filter: &spanner.Statement{SQL: "Id = @p0", Params: map[string]interface{}{"p0": int64(1)}},
+			want: spanner.Statement{
+				SQL:    "SELECT EXISTS(SELECT 1 FROM test_table WHERE Id = @p0 LIMIT 1)",
+				Params: map[string]interface{}{"p0": int64(1)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := existsStatement("test_table", tt.filter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("existsStatement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Any(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	data, err := proto.Marshal(wrapperspb.Int64(id))
+	if err != nil {
+		t.Fatalf("proto.Marshal() error = %v", err)
+	}
+	if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id, "Data": data}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+	})
+
+	tests := []struct {
+		name string
+		id   int64
+		want bool
+	}{
+		{name: "matching row", id: id, want: true},
+		{name: "no matching row", id: -id, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sproto.Any(ctx, "test_table", &spanner.Statement{SQL: "Id = @id", Params: map[string]interface{}{"id": tt.id}})
+			if err != nil {
+				t.Fatalf("Any() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Any() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
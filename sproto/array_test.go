@@ -0,0 +1,81 @@
+package sproto
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+func TestClient_ArrayColumns(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	t.Cleanup(func() {
+		_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+	})
+
+	if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{
+		"Id":     id,
+		"Tags":   []string{"a", "b"},
+		"Scores": []int{1, 2, 3},
+	}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+
+	row, err := sproto.ReadRow(ctx, "test_table", spanner.Key{id}, []string{"Tags", "Scores"}, nil)
+	if err != nil {
+		t.Fatalf("ReadRow() error = %v", err)
+	}
+	if got, want := row["Tags"], []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadRow() Tags = %#v, want %#v", got, want)
+	}
+	if got, want := row["Scores"], []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadRow() Scores = %#v, want %#v", got, want)
+	}
+
+	// The decoded values can be written back as is.
+	row["Id"] = id
+	row["Tags"] = append(row["Tags"].([]string), "c")
+	if err := sproto.UpsertRow(ctx, "test_table", row); err != nil {
+		t.Fatalf("UpsertRow() error = %v", err)
+	}
+
+	rows, _, err := sproto.QueryRows(ctx, "test_table", []string{"Id", "Tags", "Scores"}, &spanner.Statement{
+		SQL:    "Id = @id",
+		Params: map[string]interface{}{"id": id},
+	}, nil)
+	if err != nil {
+		t.Fatalf("QueryRows() error = %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("QueryRows() got %d rows, want 1", len(rows))
+	}
+	if got, want := rows[0]["Tags"], []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryRows() Tags = %#v, want %#v", got, want)
+	}
+	if got, want := rows[0]["Scores"], []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryRows() Scores = %#v, want %#v", got, want)
+	}
+}
+
+func Test_encodeColumnValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{}
+	}{
+		{name: "Ints", value: []int{1, 2}, want: []int64{1, 2}},
+		{name: "Int32s", value: []int32{3}, want: []int64{3}},
+		{name: "Strings", value: []string{"a"}, want: []string{"a"}},
+		{name: "Scalar", value: "a", want: "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encodeColumnValue(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("encodeColumnValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package sproto
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"cloud.google.com/go/spanner/apiv1/spannerpb"
+	"google.golang.org/grpc/status"
+)
+
+/*
+MutationGroup is a group of mutations which are committed atomically with respect to each other by
+BatchWriteIndependent.
+*/
+type MutationGroup struct {
+	// Mutations to commit together.
+	Mutations []*spanner.Mutation
+}
+
+/*
+MutationGroupResult is the outcome of committing a single MutationGroup using BatchWriteIndependent.
+*/
+type MutationGroupResult struct {
+	// Err is nil if the group was committed, else the reason it was not.
+	Err error
+	// CommitTimestamp is the time at which the group was committed. It is zero if the group was not committed.
+	CommitTimestamp time.Time
+}
+
+/*
+BatchWriteIndependent commits the provided mutation groups using the Spanner BatchWrite API.
+
+Unlike the other write methods, the groups are NOT committed in a single transaction. Each group is committed
+atomically on its own, in no particular order, and possibly together with other groups. A failing group therefore
+does not prevent the remaining groups from being committed, and groups which were committed are not rolled back if
+others fail. Use this for high-throughput writes of independent rows, where partial success is acceptable, and
+idempotent mutations (e.g. spanner.InsertOrUpdate) so that failed groups can simply be retried.
+
+The returned results correspond 1-to-1 to the provided groups, i.e. index i of the results is the outcome of group i.
+The returned error is only non-nil if the batch write itself failed, in which case the results of the groups whose
+outcome was not reported are set to that error.
+*/
+func (s *Client) BatchWriteIndependent(ctx context.Context, groups []MutationGroup) ([]MutationGroupResult, error) {
+	results := make([]MutationGroupResult, len(groups))
+	if len(groups) == 0 {
+		return results, nil
+	}
+
+	mutationGroups := make([]*spanner.MutationGroup, len(groups))
+	for i, group := range groups {
+		mutationGroups[i] = &spanner.MutationGroup{Mutations: group.Mutations}
+	}
+
+	reported := make([]bool, len(groups))
+	err := s.client.BatchWrite(ctx, mutationGroups).Do(func(response *spannerpb.BatchWriteResponse) error {
+		result := MutationGroupResult{
+			Err: status.ErrorProto(response.GetStatus()),
+		}
+		if result.Err == nil {
+			result.CommitTimestamp = response.GetCommitTimestamp().AsTime()
+		}
+
+		for _, index := range response.GetIndexes() {
+			if int(index) >= len(groups) {
+				return fmt.Errorf("batch write returned an invalid group index %d", index)
+			}
+			results[index] = result
+			reported[index] = true
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range results {
+			if !reported[i] {
+				results[i].Err = err
+			}
+		}
+		return results, err
+	}
+
+	return results, nil
+}
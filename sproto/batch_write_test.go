@@ -0,0 +1,58 @@
+package sproto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClient_BatchWriteIndependent(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	ids := []int64{id, id + 1, id + 2}
+	t.Cleanup(func() {
+		for _, id := range ids {
+			_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+		}
+	})
+
+	groups := []MutationGroup{
+		{Mutations: []*spanner.Mutation{spanner.InsertOrUpdate("test_table", []string{"Id", "Name"}, []interface{}{ids[0], "first"})}},
+		// Updating a row which does not exist fails, without affecting the other groups.
+		{Mutations: []*spanner.Mutation{spanner.Update("test_table", []string{"Id", "Name"}, []interface{}{id - 1, "missing"})}},
+		{Mutations: []*spanner.Mutation{spanner.InsertOrUpdate("test_table", []string{"Id", "Name"}, []interface{}{ids[1], "second"})}},
+		{Mutations: []*spanner.Mutation{spanner.InsertOrUpdate("test_table", []string{"Id", "Name"}, []interface{}{ids[2], "third"})}},
+	}
+	results, err := sproto.BatchWriteIndependent(ctx, groups)
+	if err != nil {
+		t.Fatalf("BatchWriteIndependent() error = %v", err)
+	}
+	if len(results) != len(groups) {
+		t.Fatalf("BatchWriteIndependent() got %d results, want %d", len(results), len(groups))
+	}
+
+	for i, result := range results {
+		if i == 1 {
+			if status.Code(result.Err) != codes.NotFound {
+				t.Errorf("results[%d].Err = %v, want NotFound", i, result.Err)
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Errorf("results[%d].Err = %v, want nil", i, result.Err)
+		}
+		if result.CommitTimestamp.IsZero() {
+			t.Errorf("results[%d].CommitTimestamp is zero, want the commit timestamp", i)
+		}
+	}
+
+	for i, id := range ids {
+		if _, err := sproto.ReadRow(ctx, "test_table", spanner.Key{id}, []string{"Name"}, nil); err != nil {
+			t.Errorf("ReadRow(%d) error = %v, want row %d to be committed", id, err, i)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+/*
+columnResolver maps the field names used by an API, typically in snake_case, to the column names of a table.
+*/
+type columnResolver struct {
+	tableName string
+	// columns maps both the column names and their snake_case form to the column names.
+	columns map[string]string
+}
+
+/*
+newColumnResolver builds a columnResolver from the provided column names, in their ordinal position.
+If several columns have the same snake_case form, the name resolves to the first one.
+*/
+func newColumnResolver(tableName string, columnNames []string) *columnResolver {
+	columns := make(map[string]string, 2*len(columnNames))
+	for _, column := range columnNames {
+		columns[column] = column
+	}
+	for _, column := range columnNames {
+		if _, ok := columns[snakeCase(column)]; !ok {
+			columns[snakeCase(column)] = column
+		}
+	}
+	return &columnResolver{tableName: tableName, columns: columns}
+}
+
+/*
+resolve returns the column name matching the provided name, which is either the column name itself or its snake_case
+form. For example, both "PortfolioName" and "portfolio_name" resolve to the PortfolioName column.
+
+An ErrInvalidArguments error is returned if the name does not match any column of the table.
+A nil resolver returns the name unchanged.
+*/
+func (r *columnResolver) resolve(name string) (string, error) {
+	if r == nil {
+		return name, nil
+	}
+	if column, ok := r.columns[name]; ok {
+		return column, nil
+	}
+	return "", ErrInvalidArguments{
+		err:    fmt.Errorf("%s does not match any column of table %s", name, r.tableName),
+		fields: []string{name},
+	}
+}
+
+// resolveAll resolves each of the provided names, see resolve.
+func (r *columnResolver) resolveAll(names []string) ([]string, error) {
+	if r == nil {
+		return names, nil
+	}
+	columns := make([]string, len(names))
+	for i, name := range names {
+		column, err := r.resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		columns[i] = column
+	}
+	return columns, nil
+}
+
+// resolveSortColumns resolves the keys of the provided sort columns, see resolve.
+func (r *columnResolver) resolveSortColumns(sortColumns map[string]SortOrder) (map[string]SortOrder, error) {
+	if r == nil || len(sortColumns) == 0 {
+		return sortColumns, nil
+	}
+	res := make(map[string]SortOrder, len(sortColumns))
+	for name, order := range sortColumns {
+		column, err := r.resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		res[column] = order
+	}
+	return res, nil
+}
+
+/*
+resolveSnakeCaseColumns resolves the columns to read and the sort columns of the provided options against the table
+schema if the options have SnakeCaseColumns set, and returns them unchanged otherwise. The options are not modified.
+*/
+func resolveSnakeCaseColumns(ctx context.Context, s *Client, tableName string, columns []string, opts *ReadOptions) ([]string, *ReadOptions, error) {
+	if opts == nil || !opts.SnakeCaseColumns {
+		return columns, opts, nil
+	}
+	columnNames, err := getColumnNames(ctx, s.client, tableName)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolver := newColumnResolver(tableName, columnNames)
+	if columns, err = resolver.resolveAll(columns); err != nil {
+		return nil, nil, err
+	}
+	sortColumns, err := resolver.resolveSortColumns(opts.SortColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolvedOpts := *opts
+	resolvedOpts.SortColumns = sortColumns
+	return columns, &resolvedOpts, nil
+}
+
+/*
+snakeCase converts a column name to snake_case, for example "PortfolioName" to "portfolio_name" and "HTTPStatus" to
+"http_status". Names already in snake_case are returned unchanged.
+*/
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' &&
+				(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+					(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// getColumnNames returns the column names of the table, in their ordinal position.
+func getColumnNames(ctx context.Context, client *spanner.Client, tableName string) ([]string, error) {
+	stmt := spanner.Statement{
+		SQL: `
+			select column_name from information_schema.columns where table_name=@tableName order by ordinal_position
+			`,
+		Params: map[string]interface{}{
+			"tableName": tableName,
+		},
+	}
+
+	iter := client.Single().Query(ctx, stmt)
+	defer iter.Stop()
+
+	var result []string
+	for {
+		row, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var columnName string
+		if err := row.ColumnByName("column_name", &columnName); err != nil {
+			return nil, err
+		}
+		result = append(result, columnName)
+	}
+	if len(result) == 0 {
+		return nil, ErrNotFound{
+			err: fmt.Errorf("table %s", tableName),
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func Test_snakeCase(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Id", want: "id"},
+		{in: "PortfolioName", want: "portfolio_name"},
+		{in: "HTTPStatus", want: "http_status"},
+		{in: "Address2Line", want: "address2_line"},
+		{in: "portfolio_name", want: "portfolio_name"},
+		{in: "Portfolio_Name", want: "portfolio_name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := snakeCase(tt.in); got != tt.want {
+				t.Errorf("snakeCase(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_columnResolver(t *testing.T) {
+	resolver := newColumnResolver("portfolios", []string{"Id", "PortfolioName", "owner"})
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr error
+	}{
+		{name: "snake case", in: "portfolio_name", want: "PortfolioName"},
+		{name: "column name", in: "PortfolioName", want: "PortfolioName"},
+		{name: "snake case column", in: "owner", want: "owner"},
+		{name: "unknown", in: "portfolio_title", wantErr: ErrInvalidArguments{}},
+		{name: "wrong case", in: "portfolioname", wantErr: ErrInvalidArguments{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.resolve(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("resolve(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolve(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	sortColumns, err := resolver.resolveSortColumns(map[string]SortOrder{"portfolio_name": SortOrderAsc})
+	if err != nil {
+		t.Fatalf("resolveSortColumns() error = %v", err)
+	}
+	if want := map[string]SortOrder{"PortfolioName": SortOrderAsc}; !reflect.DeepEqual(sortColumns, want) {
+		t.Errorf("resolveSortColumns() = %v, want %v", sortColumns, want)
+	}
+}
+
+func TestClient_QueryRows_SnakeCaseColumns(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	data, err := proto.Marshal(wrapperspb.Int64(id))
+	if err != nil {
+		t.Fatalf("proto.Marshal() error = %v", err)
+	}
+	if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id, "Data": data}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+	})
+
+	filter := &spanner.Statement{SQL: "Id = @id", Params: map[string]interface{}{"id": id}}
+	rows, _, err := sproto.QueryRows(ctx, "test_table", []string{"id"}, filter, &ReadOptions{
+		SortColumns:      map[string]SortOrder{"id": SortOrderAsc},
+		SnakeCaseColumns: true,
+	})
+	if err != nil {
+		t.Fatalf("QueryRows() error = %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("QueryRows() = %v, want a single row", rows)
+	}
+	if _, ok := rows[0]["Id"]; !ok {
+		t.Errorf("QueryRows() = %v, want the row keyed by the Id column", rows)
+	}
+
+	_, _, err = sproto.QueryRows(ctx, "test_table", []string{"unknown_column"}, filter, &ReadOptions{SnakeCaseColumns: true})
+	if !errors.Is(err, ErrInvalidArguments{}) {
+		t.Errorf("QueryRows() error = %v, want ErrInvalidArguments", err)
+	}
+}
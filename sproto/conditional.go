@@ -0,0 +1,160 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+/*
+WriteCondition guards a conditional write: the write is only applied if the column currently holds the expected value.
+
+The expected value must be of a Go type the column can be decoded into, e.g. string for STRING columns, int64 for
+INT64 columns and bool for BOOL columns. A nil value expects the column to be NULL.
+*/
+type WriteCondition struct {
+	// The name of the guard column, which must be a scalar column.
+	Column string
+	// The value the guard column is expected to hold.
+	Value interface{}
+}
+
+/*
+UpdateRowIf updates a row in the specified table using the provided column values, provided that the guard column of
+the condition holds the expected value, e.g. to only set Status to DONE if it is currently RUNNING:
+
+	err := client.UpdateRowIf(ctx, "Jobs", spanner.Key{id}, map[string]interface{}{"Id": id, "Status": "DONE"},
+		sproto.WriteCondition{Column: "Status", Value: "RUNNING"})
+
+The guard column is read and the row updated within a single read-write transaction, so no concurrent write can
+change the guard column in between.
+
+The primary key value(s) must be included in the row, and must match the row key, otherwise ErrInvalidArguments is
+returned.
+If the row does not exist, ErrNotFound is returned. If the guard column holds a different value, ErrConditionFailed is
+returned and the row is left unchanged.
+*/
+func (s *Client) UpdateRowIf(ctx context.Context, tableName string, rowKey spanner.Key, row map[string]interface{}, condition WriteCondition) error {
+	if condition.Column == "" {
+		return ErrInvalidArguments{
+			err:    fmt.Errorf("condition column is required"),
+			fields: []string{"condition.Column"},
+		}
+	}
+
+	// Ensure the row updates the row the condition is read on
+	primaryKeyColumns, err := s.primaryKeyColumns(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	if err := checkRowKey(primaryKeyColumns, rowKey, row); err != nil {
+		return err
+	}
+
+	// Construct columns and values
+	columns := make([]string, 0, len(row))
+	values := make([]interface{}, 0, len(row))
+	for column, value := range row {
+		columns = append(columns, column)
+		values = append(values, encodeColumnValue(value))
+	}
+
+	_, err = s.client.ReadWriteTransactionWithOptions(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		current, err := txn.ReadRowWithOptions(ctx, tableName, rowKey, []string{condition.Column}, s.readOptions(nil))
+		if err != nil {
+			return err
+		}
+
+		var gcv spanner.GenericColumnValue
+		if err := current.Column(0, &gcv); err != nil {
+			return err
+		}
+		actual, err := decodeConditionValue(gcv, condition.Value)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(actual, condition.Value) {
+			return ErrConditionFailed{
+				Column:   condition.Column,
+				Expected: condition.Value,
+				Actual:   actual,
+			}
+		}
+
+		return txn.BufferWrite([]*spanner.Mutation{spanner.Update(tableName, columns, values)})
+	}, s.transactionOptions())
+	if err != nil {
+		var errConditionFailed ErrConditionFailed
+		if errors.As(err, &errConditionFailed) {
+			return errConditionFailed
+		}
+		switch spanner.ErrCode(err) {
+		case codes.NotFound:
+			return ErrNotFound{
+				RowKey: rowKey.String(),
+				err:    err,
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+/*
+checkRowKey returns an ErrInvalidArguments error if the primary key values of the row do not match the row key, since
+the condition would otherwise be read on one row and the update applied to another.
+*/
+func checkRowKey(primaryKeyColumns []*primaryKeyColumn, rowKey spanner.Key, row map[string]interface{}) error {
+	if len(primaryKeyColumns) != len(rowKey) {
+		return ErrInvalidArguments{
+			err:    fmt.Errorf("row key length does not match the primary key columns length"),
+			fields: []string{"rowKey"},
+		}
+	}
+	for i, column := range primaryKeyColumns {
+		value, ok := row[column.columnName]
+		if !ok {
+			return ErrInvalidArguments{
+				err:    fmt.Errorf("row is missing primary key column %s", column.columnName),
+				fields: []string{"row"},
+			}
+		}
+		if fmt.Sprintf("%v", value) != fmt.Sprintf("%v", rowKey[i]) {
+			return ErrInvalidArguments{
+				err:    fmt.Errorf("row value %v of primary key column %s does not match the row key value %v", value, column.columnName, rowKey[i]),
+				fields: []string{"row"},
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+decodeConditionValue decodes the column value into the Go type of the expected value.
+NULL values are decoded to nil, as are all values if the expected value is nil.
+*/
+func decodeConditionValue(gcv spanner.GenericColumnValue, expected interface{}) (interface{}, error) {
+	if _, ok := gcv.Value.GetKind().(*structpb.Value_NullValue); ok {
+		return nil, nil
+	}
+	if expected == nil {
+		return parseStructPbValue(gcv.Value), nil
+	}
+
+	ptr := reflect.New(reflect.TypeOf(expected))
+	if err := gcv.Decode(ptr.Interface()); err != nil {
+		return nil, ErrInvalidArguments{
+			err:    fmt.Errorf("decode %s into %T: %w", gcv.Type.GetCode(), expected, err),
+			fields: []string{"condition.Value"},
+		}
+	}
+	return ptr.Elem().Interface(), nil
+}
@@ -0,0 +1,115 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+func TestClient_UpdateRowIf(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id, "Name": "RUNNING"}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+	})
+
+	tests := []struct {
+		name      string
+		condition WriteCondition
+		wantErr   error
+		wantName  string
+	}{
+		{
+			name:      "mismatch",
+			condition: WriteCondition{Column: "Name", Value: "PENDING"},
+			wantErr:   ErrConditionFailed{},
+			wantName:  "RUNNING",
+		},
+		{
+			name:      "expected NULL",
+			condition: WriteCondition{Column: "Name", Value: nil},
+			wantErr:   ErrConditionFailed{},
+			wantName:  "RUNNING",
+		},
+		{
+			name:      "match",
+			condition: WriteCondition{Column: "Name", Value: "RUNNING"},
+			wantName:  "DONE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sproto.UpdateRowIf(ctx, "test_table", spanner.Key{id}, map[string]interface{}{"Id": id, "Name": "DONE"}, tt.condition)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateRowIf() error = %v, want %v", err, tt.wantErr)
+			}
+
+			row, err := sproto.ReadRow(ctx, "test_table", spanner.Key{id}, []string{"Name"}, nil)
+			if err != nil {
+				t.Fatalf("ReadRow() error = %v", err)
+			}
+			if row["Name"] != tt.wantName {
+				t.Errorf("Name = %v, want %v", row["Name"], tt.wantName)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		err := sproto.UpdateRowIf(ctx, "test_table", spanner.Key{id + 1}, map[string]interface{}{"Id": id + 1, "Name": "DONE"},
+			WriteCondition{Column: "Name", Value: "RUNNING"})
+		if !errors.Is(err, ErrNotFound{}) {
+			t.Errorf("UpdateRowIf() error = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func Test_checkRowKey(t *testing.T) {
+	primaryKeyColumns := []*primaryKeyColumn{NewPrimaryKeyColumn("Id", false, false)}
+	tests := []struct {
+		name    string
+		rowKey  spanner.Key
+		row     map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:   "match",
+			rowKey: spanner.Key{int64(1)},
+			row:    map[string]interface{}{"Id": 1, "Name": "DONE"},
+		},
+		{
+			name:    "mismatch",
+			rowKey:  spanner.Key{int64(1)},
+			row:     map[string]interface{}{"Id": 2, "Name": "DONE"},
+			wantErr: true,
+		},
+		{
+			name:    "missing key column",
+			rowKey:  spanner.Key{int64(1)},
+			row:     map[string]interface{}{"Name": "DONE"},
+			wantErr: true,
+		},
+		{
+			name:    "key length",
+			rowKey:  spanner.Key{int64(1), "a"},
+			row:     map[string]interface{}{"Id": 1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRowKey(primaryKeyColumns, tt.rowKey, tt.row)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkRowKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidArguments{}) {
+				t.Errorf("checkRowKey() error = %v, want ErrInvalidArguments", err)
+			}
+		})
+	}
+}
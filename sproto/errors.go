@@ -106,6 +106,24 @@ func (e ErrInvalidArguments) GRPCStatus() *status.Status {
 	return status.New(codes.InvalidArgument, e.Error())
 }
 
+// ErrMutationLimitExceeded is returned when a batch write exceeds the mutation limit of a single commit.
+type ErrMutationLimitExceeded struct {
+	mutations int
+	limit     int
+}
+
+func (e ErrMutationLimitExceeded) Error() string {
+	return fmt.Sprintf("batch requires %d mutations, which exceeds the limit of %d mutations per commit: "+
+		"write the rows in smaller chunks or enable MutationLimitSplit", e.mutations, e.limit)
+}
+func (e ErrMutationLimitExceeded) Is(target error) bool {
+	var errMutationLimitExceeded ErrMutationLimitExceeded
+	return errors.As(target, &errMutationLimitExceeded)
+}
+func (e ErrMutationLimitExceeded) GRPCStatus() *status.Status {
+	return status.New(codes.InvalidArgument, e.Error())
+}
+
 // ErrAlreadyExists is returned when the desired resource already exists in Spanner.
 type ErrAlreadyExists struct {
 	err error
@@ -121,3 +139,22 @@ func (e ErrAlreadyExists) Is(target error) bool {
 func (e ErrAlreadyExists) GRPCStatus() *status.Status {
 	return status.New(codes.AlreadyExists, e.Error())
 }
+
+// ErrConditionFailed is returned when a conditional write is rejected because the guard column does not hold the
+// expected value.
+type ErrConditionFailed struct {
+	Column   string
+	Expected interface{}
+	Actual   interface{}
+}
+
+func (e ErrConditionFailed) Error() string {
+	return fmt.Sprintf("condition failed: expected %s to be %v, got %v", e.Column, e.Expected, e.Actual)
+}
+func (e ErrConditionFailed) Is(target error) bool {
+	var errConditionFailed ErrConditionFailed
+	return errors.As(target, &errConditionFailed)
+}
+func (e ErrConditionFailed) GRPCStatus() *status.Status {
+	return status.New(codes.FailedPrecondition, e.Error())
+}
@@ -0,0 +1,65 @@
+package sproto
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+/*
+Etag returns a checksum of the provided message, which changes whenever the message does. Return it to the callers
+reading the message, e.g. in the etag field of a resource, and pass it back to UpdateProtoIf to reject their update if
+the message has changed since they read it.
+
+The checksum is computed from the deterministic wire encoding of the message, which is stable for a given build of
+the message type, but may change when the proto definition or the protobuf library is upgraded.
+*/
+func Etag(message proto.Message) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+/*
+UpdateProtoIf updates a proto message in the same way as UpdateProto, provided that the etag of the current message,
+see Etag, matches the provided one. This implements optimistic concurrency control on top of a read-modify-write
+cycle spanning several requests, e.g. an API read followed by an update from the same client:
+
+	err := client.UpdateProtoIf(ctx, "Books", spanner.Key{id}, "Book", book, updateMask, req.GetBook().GetEtag())
+
+The etag is checked and the message updated within a single read-write transaction. If the current message has a
+different etag, ErrConditionFailed is returned and the row is left unchanged.
+*/
+func (s *Client) UpdateProtoIf(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message, updateMask *fieldmaskpb.FieldMask, etag string) error {
+	return s.RunInTransaction(ctx, func(ctx context.Context, tx *Tx) error {
+		return tx.UpdateProtoIf(ctx, tableName, rowKey, columnName, message, updateMask, etag)
+	})
+}
+
+/*
+UpdateProtoIf updates a proto message within the transaction, provided that the etag of the current message matches
+the provided one. See Client.UpdateProtoIf for details.
+*/
+func (tx *Tx) UpdateProtoIf(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message, updateMask *fieldmaskpb.FieldMask, etag string) error {
+	return tx.updateProto(ctx, tableName, rowKey, columnName, message, updateMask, func(current proto.Message) error {
+		currentEtag, err := Etag(current)
+		if err != nil {
+			return err
+		}
+		if currentEtag != etag {
+			return ErrConditionFailed{
+				Column:   columnName,
+				Expected: etag,
+				Actual:   currentEtag,
+			}
+		}
+		return nil
+	})
+}
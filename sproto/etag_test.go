@@ -0,0 +1,66 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestEtag(t *testing.T) {
+	first, err := Etag(wrapperspb.String("first"))
+	if err != nil {
+		t.Fatalf("Etag() error = %v", err)
+	}
+	again, err := Etag(wrapperspb.String("first"))
+	if err != nil {
+		t.Fatalf("Etag() error = %v", err)
+	}
+	second, err := Etag(wrapperspb.String("second"))
+	if err != nil {
+		t.Fatalf("Etag() error = %v", err)
+	}
+
+	if first != again {
+		t.Errorf("Etag() = %v and %v for equal messages, want the same etag", first, again)
+	}
+	if first == second {
+		t.Errorf("Etag() = %v for different messages, want different etags", first)
+	}
+}
+
+func TestClient_UpdateProtoIf(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	data, err := proto.Marshal(wrapperspb.String("current"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id, "Data": data}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+	})
+
+	staleEtag, err := Etag(wrapperspb.String("stale"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sproto.UpdateProtoIf(ctx, "test_table", spanner.Key{id}, "Data", wrapperspb.String("updated"), nil, staleEtag)
+	if !errors.Is(err, ErrConditionFailed{}) {
+		t.Fatalf("UpdateProtoIf() error = %v, want ErrConditionFailed", err)
+	}
+
+	got := &wrapperspb.StringValue{}
+	if err := sproto.ReadProto(ctx, "test_table", spanner.Key{id}, "Data", got, nil); err != nil {
+		t.Fatalf("ReadProto() error = %v", err)
+	}
+	if got.GetValue() != "current" {
+		t.Errorf("ReadProto() = %v, want the message left unchanged", got.GetValue())
+	}
+}
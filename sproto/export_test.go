@@ -0,0 +1,87 @@
+package sproto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+func TestClient_ExportQuery(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := []map[string]interface{}{
+		{"Id": id, "Name": "first, with comma", "IsActive": true, "CreatedAt": createdAt, "Data": []byte("hello"), "Tags": []string{"a", "b"}},
+		{"Id": id + 1, "Name": "second"},
+	}
+	for _, row := range rows {
+		if err := sproto.InsertRow(ctx, "test_table", row); err != nil {
+			t.Fatalf("InsertRow() error = %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		_ = sproto.BatchDeleteRows(context.Background(), "test_table", []spanner.Key{{id}, {id + 1}})
+	})
+
+	columns := []string{"Id", "Name", "IsActive", "CreatedAt", "Data", "Tags"}
+	filter := &spanner.Statement{SQL: "Id >= @id", Params: map[string]interface{}{"id": id}}
+	opts := &ReadOptions{SortColumns: map[string]SortOrder{"Id": SortOrderAsc}, Limit: 2}
+
+	t.Run("CSV", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := sproto.ExportQuery(ctx, &buf, ExportFormatCSV, "test_table", columns, filter, opts); err != nil {
+			t.Fatalf("ExportQuery() error = %v", err)
+		}
+		want := strings.Join([]string{
+			"Id,Name,IsActive,CreatedAt,Data,Tags",
+			fmt.Sprintf(`%d,"first, with comma",true,2024-01-02T03:04:05Z,aGVsbG8=,"[""a"",""b""]"`, id),
+			fmt.Sprintf("%d,second,,,,", id+1),
+		}, "\n") + "\n"
+		if got := buf.String(); got != want {
+			t.Errorf("ExportQuery() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := sproto.ExportQuery(ctx, &buf, ExportFormatJSON, "test_table", columns, filter, opts); err != nil {
+			t.Fatalf("ExportQuery() error = %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("ExportQuery() wrote %d lines, want 2", len(lines))
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		want := map[string]interface{}{
+			"Id":        float64(id),
+			"Name":      "first, with comma",
+			"IsActive":  true,
+			"CreatedAt": "2024-01-02T03:04:05Z",
+			"Data":      "aGVsbG8=",
+			"Tags":      []interface{}{"a", "b"},
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("ExportQuery() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		var buf bytes.Buffer
+		empty := &spanner.Statement{SQL: "Id = @id", Params: map[string]interface{}{"id": -1}}
+		if err := sproto.ExportQuery(ctx, &buf, ExportFormatCSV, "test_table", columns, empty, nil); err != nil {
+			t.Fatalf("ExportQuery() error = %v", err)
+		}
+		if got, want := buf.String(), "Id,Name,IsActive,CreatedAt,Data,Tags\n"; got != want {
+			t.Errorf("ExportQuery() = %q, want %q", got, want)
+		}
+	})
+}
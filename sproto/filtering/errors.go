@@ -3,6 +3,7 @@ package filtering
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -45,3 +46,24 @@ func (e ErrInvalidIdentifier) Is(target error) bool {
 func (e ErrInvalidIdentifier) GRPCStatus() *status.Status {
 	return status.New(codes.InvalidArgument, e.Error())
 }
+
+type ErrOperatorNotAllowed struct {
+	identifier string
+	operator   Operator
+	allowed    []Operator
+}
+
+func (e ErrOperatorNotAllowed) Error() string {
+	allowed := make([]string, len(e.allowed))
+	for i, op := range e.allowed {
+		allowed[i] = string(op)
+	}
+	return fmt.Sprintf("operator %s is not allowed on %s (allowed: %s)", e.operator, e.identifier, strings.Join(allowed, ", "))
+}
+func (e ErrOperatorNotAllowed) Is(target error) bool {
+	var errOperatorNotAllowed ErrOperatorNotAllowed
+	return errors.As(target, &errOperatorNotAllowed)
+}
+func (e ErrOperatorNotAllowed) GRPCStatus() *status.Status {
+	return status.New(codes.InvalidArgument, e.Error())
+}
@@ -1,13 +1,17 @@
 package filtering
 
 import (
+	"fmt"
 	"regexp"
+	"sort"
+	"strings"
 
 	"cloud.google.com/go/spanner"
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/traits"
 	"github.com/google/cel-go/ext"
+	expr "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
 	"google.golang.org/genproto/googleapis/type/date"
 	"google.golang.org/genproto/googleapis/type/money"
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -141,6 +145,28 @@ func (t dateIdentifier) Path() string {
 	return t.path
 }
 
+type numericIdentifier struct {
+	path string
+}
+
+func (t numericIdentifier) envType() *cel.Type {
+	return cel.DoubleType
+}
+func (t numericIdentifier) Path() string {
+	return t.path
+}
+
+type jsonIdentifier struct {
+	path string
+}
+
+func (t jsonIdentifier) envType() *cel.Type {
+	return cel.MapType(cel.StringType, cel.DynType)
+}
+func (t jsonIdentifier) Path() string {
+	return t.path
+}
+
 type uintIdentifier struct {
 	path string
 }
@@ -188,6 +214,117 @@ func (t reservedIdentifier) Path() string {
 	return t.name
 }
 
+/*
+Operator represents an operator that can be applied to an identifier in a filter.
+*/
+type Operator string
+
+const (
+	// OperatorEquals represents the equality operator, i.e. `=` or `==`.
+	OperatorEquals Operator = "="
+	// OperatorNotEquals represents the inequality operator, i.e. `!=`.
+	OperatorNotEquals Operator = "!="
+	// OperatorLessThan represents the less than operator, i.e. `<`.
+	OperatorLessThan Operator = "<"
+	// OperatorLessThanOrEquals represents the less than or equals operator, i.e. `<=`.
+	OperatorLessThanOrEquals Operator = "<="
+	// OperatorGreaterThan represents the greater than operator, i.e. `>`.
+	OperatorGreaterThan Operator = ">"
+	// OperatorGreaterThanOrEquals represents the greater than or equals operator, i.e. `>=`.
+	OperatorGreaterThanOrEquals Operator = ">="
+	// OperatorIn represents the membership operator, i.e. `IN`.
+	OperatorIn Operator = "IN"
+	// OperatorNotIn represents the negated membership operator, i.e. `NOT IN`.
+	OperatorNotIn Operator = "NOT IN"
+	// OperatorBetween represents the range operator, i.e. `BETWEEN`.
+	OperatorBetween Operator = "BETWEEN"
+	// OperatorNotBetween represents the negated range operator, i.e. `NOT BETWEEN`.
+	OperatorNotBetween Operator = "NOT BETWEEN"
+	// OperatorPrefix represents the prefix() function.
+	OperatorPrefix Operator = "prefix"
+	// OperatorSuffix represents the suffix() function.
+	OperatorSuffix Operator = "suffix"
+)
+
+type restrictedIdentifier struct {
+	Identifier
+	operators []Operator
+}
+
+/*
+Field declares a column/field without any type conversion.
+
+It is typically used together with Restrict to limit the operators allowed on a field which does not otherwise need
+to be declared.
+
+Example:
+
+	Field("Proto.state")
+*/
+func Field(path string) Identifier {
+	return anyIdentifier{
+		path: path,
+	}
+}
+
+/*
+Restrict limits the operators that may be applied to the provided identifier.
+
+Parse returns an ErrInvalidFilter wrapping an ErrOperatorNotAllowed error if the filter applies any other operator to
+the identifier. This is useful to constrain filters to shapes that can be served by an index, avoiding full table scans.
+
+Example:
+
+	Restrict(Field("Proto.state"), OperatorEquals, OperatorIn)
+	Restrict(Timestamp("create_time"), OperatorGreaterThan, OperatorLessThan)
+*/
+func Restrict(identifier Identifier, operators ...Operator) Identifier {
+	return restrictedIdentifier{
+		Identifier: identifier,
+		operators:  operators,
+	}
+}
+
+type columnIdentifier struct {
+	Identifier
+	column string
+}
+
+/*
+Column maps the provided identifier to a differently named column, or an expression such as a proto field, in the
+emitted SQL. The identifier is still referenced by its own path in filters, and any conversion of the identifier
+(e.g. Timestamp) is applied to the column.
+
+Example:
+
+	Column(Timestamp("created"), "create_time")
+	Column(Field("state"), "Proto.state")
+	Restrict(Column(Field("state"), "Proto.state"), OperatorEquals)
+*/
+func Column(identifier Identifier, column string) Identifier {
+	return columnIdentifier{
+		Identifier: identifier,
+		column:     column,
+	}
+}
+
+// unwrapIdentifier returns the underlying identifier along with the allowed operators, if restricted, and the
+// column it is mapped to, if any.
+func unwrapIdentifier(identifier Identifier) (Identifier, []Operator, string) {
+	var operators []Operator
+	var column string
+	for {
+		switch i := identifier.(type) {
+		case restrictedIdentifier:
+			identifier, operators = i.Identifier, i.operators
+		case columnIdentifier:
+			identifier, column = i.Identifier, i.column
+		default:
+			return identifier, operators, column
+		}
+	}
+}
+
 /*
 Duration enables conversion of google.protobuf.Duration to a spanner int type.
 
@@ -236,6 +373,42 @@ func Date(path string) Identifier {
 	}
 }
 
+/*
+Numeric declares a NUMERIC column/field, e.g. a monetary amount.
+
+Literals compared to the identifier are bound as spanner.NullNumeric rather than as floating point values, so the
+comparison is exact. Integer, decimal and quoted decimal literals are accepted.
+
+It takes in the path to the column/field.
+
+Example:
+
+	Numeric("amount")
+	Restrict(Numeric("amount"), OperatorGreaterThan, OperatorLessThan)
+*/
+func Numeric(path string) Identifier {
+	return numericIdentifier{
+		path: path,
+	}
+}
+
+/*
+JSON declares a JSON column/field, whose keys are accessed using a JSONPath rather than as the entries of a proto map
+field, e.g. metadata.'my-key' = 'x' and metadata['my-key'] = 'x' both become JSON_VALUE(metadata, '$."my-key"') = @p0.
+
+It takes in the path to the column/field.
+
+Example:
+
+	JSON("metadata")
+	JSON("Proto.attributes")
+*/
+func JSON(path string) Identifier {
+	return jsonIdentifier{
+		path: path,
+	}
+}
+
 /*
 Reserved allows for the querying of columns with reserved keywords.
 It instructs the parser to wrap the column names with backticks(`).
@@ -255,17 +428,34 @@ type sanitizersRegex struct {
 	logicalEqRegex  *regexp.Regexp
 	nullRegex       *regexp.Regexp
 	inRegex         *regexp.Regexp
+	notInRegex      *regexp.Regexp
+	betweenRegex    *regexp.Regexp
+	notBetweenRegex *regexp.Regexp
+	notRegex        *regexp.Regexp
+	quotedKeyRegex  *regexp.Regexp
 }
 
+/*
+quotedLiteral matches a quoted string literal. It is the first alternative of all the sanitizer regexes, so that the
+keywords within string literals, e.g. 'WILL NOT SHIP', are left as is, see replaceUnquoted.
+*/
+const quotedLiteral = `('(?:[^'\\]|\\.)*'|"(?:[^"\\]|\\.)*")`
+
+// betweenOperand matches a single BETWEEN bound, i.e. a quoted string, a function call or a plain literal/path.
+const betweenOperand = `('[^']*'|"[^"]*"|\w+\([^)]*\)|[\w.+-]+)`
+
 /*
 Filter is a CEL filter expression to Spanner query parser.
 
 It is used to parse a CEL filter expression and convert it to a Spanner statement.
 */
 type Filter struct {
-	identifiers     map[string]Identifier
-	env             *cel.Env
-	sanitizersRegex *sanitizersRegex
+	identifiers      map[string]Identifier
+	allowedOperators map[string][]Operator
+	columns          map[string]string
+	tableAlias       string
+	env              *cel.Env
+	sanitizersRegex  *sanitizersRegex
 }
 
 /*
@@ -273,15 +463,25 @@ NewFilter creates a new Filter instance with the given identifiers.
 
 Identifiers are used to declare common protocol buffer types for conversion.
 Common identifiers are Timestamp, Duration, Date etc.
+Use Restrict to limit the operators allowed on an identifier, and Column to map it to a differently named column.
 */
 func NewFilter(identifiers ...Identifier) (*Filter, error) {
 
 	// Create a CEL environment with the given identifiers.
 	identifiersMap := make(map[string]Identifier)
+	allowedOperators := make(map[string][]Operator)
+	columns := make(map[string]string)
 	var opts []cel.EnvOption
-	for _, i := range identifiers {
+	for _, identifier := range identifiers {
+		i, operators, column := unwrapIdentifier(identifier)
 		opts = append(opts, cel.Variable(i.Path(), i.envType()))
 		identifiersMap[i.Path()] = i
+		if operators != nil {
+			allowedOperators[i.Path()] = operators
+		}
+		if column != "" {
+			columns[i.Path()] = column
+		}
 	}
 	opts = append(opts, cel.Types(&durationpb.Duration{}, &timestamppb.Timestamp{}, &date.Date{}, &money.Money{}), ext.Protos())
 
@@ -290,44 +490,115 @@ func NewFilter(identifiers ...Identifier) (*Filter, error) {
 		return nil, err
 	}
 
-	logicalAndRegex, err := regexp.Compile(`\bAND\b`)
+	logicalAndRegex, err := regexp.Compile(quotedLiteral + `|\bAND\b`)
+	if err != nil {
+		return nil, err
+	}
+
+	logicalOrRegex, err := regexp.Compile(quotedLiteral + `|\bOR\b`)
+	if err != nil {
+		return nil, err
+	}
+
+	logicalEqRegex, err := regexp.Compile(quotedLiteral + `|\s+=\s+`)
+	if err != nil {
+		return nil, err
+	}
+
+	nullRegex, err := regexp.Compile(quotedLiteral + `|\bNULL\b`)
 	if err != nil {
 		return nil, err
 	}
 
-	logicalOrRegex, err := regexp.Compile(`\bOR\b`)
+	inRegex, err := regexp.Compile(quotedLiteral + `|\bIN\b`)
 	if err != nil {
 		return nil, err
 	}
 
-	logicalEqRegex, err := regexp.Compile(`\s+=\s+`)
+	notInRegex, err := regexp.Compile(quotedLiteral + `|(?i)([\w.]+)\s+NOT\s+IN\s*(\[[^\]]*\])`)
 	if err != nil {
 		return nil, err
 	}
 
-	nullRegex, err := regexp.Compile(`\bNULL\b`)
+	betweenRegex, err := regexp.Compile(quotedLiteral + `|(?i)([\w.]+)\s+BETWEEN\s+` + betweenOperand + `\s+AND\s+` + betweenOperand)
 	if err != nil {
 		return nil, err
 	}
 
-	inRegex, err := regexp.Compile(`\bIN\b`)
+	notBetweenRegex, err := regexp.Compile(quotedLiteral + `|(?i)([\w.]+)\s+NOT\s+BETWEEN\s+` + betweenOperand + `\s+AND\s+` + betweenOperand)
+	if err != nil {
+		return nil, err
+	}
+
+	notRegex, err := regexp.Compile(quotedLiteral + `|(?i)\bNOT\b`)
+	if err != nil {
+		return nil, err
+	}
+
+	quotedKeyRegex, err := regexp.Compile(quotedLiteral + `|([\w\x60\]])\.('[^'\s]*'|"[^"\s]*")`)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Filter{
-		env:         env,
-		identifiers: identifiersMap,
+		env:              env,
+		identifiers:      identifiersMap,
+		allowedOperators: allowedOperators,
+		columns:          columns,
 		sanitizersRegex: &sanitizersRegex{
 			logicalAndRegex: logicalAndRegex,
 			logicalOrRegex:  logicalOrRegex,
 			logicalEqRegex:  logicalEqRegex,
 			nullRegex:       nullRegex,
 			inRegex:         inRegex,
+			notInRegex:      notInRegex,
+			betweenRegex:    betweenRegex,
+			notBetweenRegex: notBetweenRegex,
+			notRegex:        notRegex,
+			quotedKeyRegex:  quotedKeyRegex,
 		},
 	}, nil
 }
 
+/*
+WithTableAlias returns a copy of the Filter which qualifies the emitted columns with the provided table alias, e.g.
+"t.name = @p0" rather than "name = @p0" for the alias "t". This avoids ambiguous columns when the filter is composed
+into a query joining several tables.
+
+Reserved keywords are still quoted in the column part, e.g. "t.`order`", and identifiers mapped using Column are
+qualified as well.
+
+May return an ErrInvalidIdentifier error if the alias is not a valid identifier.
+*/
+func (f *Filter) WithTableAlias(alias string) (*Filter, error) {
+	if !aliasRegex.MatchString(alias) {
+		return nil, ErrInvalidIdentifier{
+			identifier: alias,
+			err:        fmt.Errorf("invalid table alias"),
+		}
+	}
+
+	aliased := *f
+	aliased.identifiers = make(map[string]Identifier, len(f.identifiers))
+	for k, v := range f.identifiers {
+		aliased.identifiers[k] = v
+	}
+	aliased.allowedOperators = make(map[string][]Operator, len(f.allowedOperators))
+	for k, v := range f.allowedOperators {
+		aliased.allowedOperators[k] = v
+	}
+	aliased.columns = make(map[string]string, len(f.columns))
+	for k, v := range f.columns {
+		aliased.columns[k] = v
+	}
+	aliased.tableAlias = alias
+
+	return &aliased, nil
+}
+
+// aliasRegex matches valid table aliases.
+var aliasRegex = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+
 /*
 DeclareIdentifier declares a new Identifier in the environment.
 
@@ -336,6 +607,7 @@ This is useful when you want to add a new identifier to the environment after cr
 May return an ErrInvalidIdentifier error if the identifier is invalid.
 */
 func (f *Filter) DeclareIdentifier(identifier Identifier) error {
+	identifier, operators, column := unwrapIdentifier(identifier)
 	env, err := f.env.Extend(cel.Variable(identifier.Path(), identifier.envType()))
 	if err != nil {
 		return ErrInvalidIdentifier{
@@ -345,16 +617,31 @@ func (f *Filter) DeclareIdentifier(identifier Identifier) error {
 	}
 
 	f.env = env
+	f.identifiers[identifier.Path()] = identifier
+	if operators != nil {
+		f.allowedOperators[identifier.Path()] = operators
+	}
+	if column != "" {
+		f.columns[identifier.Path()] = column
+	}
 
 	return nil
 }
 
 func (f *Filter) sanitize(filter string) string {
-	filter = f.sanitizersRegex.logicalAndRegex.ReplaceAllString(filter, "&&")
-	filter = f.sanitizersRegex.logicalOrRegex.ReplaceAllString(filter, "||")
-	filter = f.sanitizersRegex.logicalEqRegex.ReplaceAllString(filter, " == ")
-	filter = f.sanitizersRegex.nullRegex.ReplaceAllString(filter, "null")
-	filter = f.sanitizersRegex.inRegex.ReplaceAllString(filter, "in")
+	// CEL has no BETWEEN or NOT IN syntax, these are rewritten to an equivalent CEL expression first.
+	// This needs to happen before AND is replaced, since it also separates the bounds of BETWEEN.
+	filter = replaceUnquoted(f.sanitizersRegex.notBetweenRegex, filter, "!between($2, $3, $4)")
+	filter = replaceUnquoted(f.sanitizersRegex.betweenRegex, filter, "between($2, $3, $4)")
+	filter = replaceUnquoted(f.sanitizersRegex.notInRegex, filter, "!($2 in $3)")
+	filter = replaceUnquoted(f.sanitizersRegex.notRegex, filter, "!")
+	// Quoted map keys, e.g. labels.'my-key', are rewritten to the CEL index syntax, i.e. labels['my-key'].
+	filter = replaceUnquoted(f.sanitizersRegex.quotedKeyRegex, filter, "$2[$3]")
+	filter = replaceUnquoted(f.sanitizersRegex.logicalAndRegex, filter, "&&")
+	filter = replaceUnquoted(f.sanitizersRegex.logicalOrRegex, filter, "||")
+	filter = replaceUnquoted(f.sanitizersRegex.logicalEqRegex, filter, " == ")
+	filter = replaceUnquoted(f.sanitizersRegex.nullRegex, filter, "null")
+	filter = replaceUnquoted(f.sanitizersRegex.inRegex, filter, "in")
 
 	//filter = strings.ReplaceAll(filter, " TIMESTAMP(", " timestamp(")
 	//filter = strings.ReplaceAll(filter, " DURATION(", " duration(")
@@ -362,6 +649,27 @@ func (f *Filter) sanitize(filter string) string {
 	return filter
 }
 
+/*
+replaceUnquoted replaces the matches of a sanitizer regex with the expanded template, leaving the quoted string
+literals, matched by its first group, as is. The groups of the rewritten pattern therefore start at $2.
+*/
+func replaceUnquoted(re *regexp.Regexp, filter string, template string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range re.FindAllStringSubmatchIndex(filter, -1) {
+		b.WriteString(filter[last:match[0]])
+		if match[2] >= 0 {
+			b.WriteString(filter[match[0]:match[1]])
+		} else {
+			b.Write(re.ExpandString(nil, template, filter, match))
+		}
+		last = match[1]
+	}
+	b.WriteString(filter[last:])
+
+	return b.String()
+}
+
 /*
 Parse parses a CEL filter expression and returns a Spanner statement.
 
@@ -371,33 +679,116 @@ Examples:
 	filter.Parse("key = 'resources/1' OR Proto.effective_date = date('2021-01-01')")
 	filter.Parse("Proto.state = 'ACTIVE'"
 	filter.Parse("key IN ['resources/1', 'resources/2']")
+	filter.Parse("key NOT IN ['resources/1', 'resources/2']")
+	filter.Parse("age BETWEEN 18 AND 65")
+	filter.Parse("Proto.labels.'my-key' = 'prod'")
+	filter.Parse("create_time NOT BETWEEN timestamp('2021-01-01T00:00:00Z') AND timestamp('2022-01-01T00:00:00Z')")
 	filter.Parse("effective_date != null)
 	filter.Parse("count >= 10)
 
 May return an ErrInvalidFilter error if the filter is invalid.
 */
 func (f *Filter) Parse(filter string) (*spanner.Statement, error) {
+	stmt, _, err := f.parse(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+/*
+ParseWithFields parses a CEL filter expression and returns a Spanner statement along with the fields referenced by the
+filter.
+
+The fields are the identifier paths (e.g. "key" or "Proto.effective_date") as they appear in the filter, deduplicated
+and sorted. This allows callers to decide whether a suitable index exists before executing the statement.
+
+Example:
+
+	stmt, fields, err := filter.ParseWithFields("Proto.state = 'ACTIVE' AND (create_time > timestamp('2021-01-01T00:00:00Z') OR key IN ['a', 'b'])")
+	// fields: []string{"Proto.state", "create_time", "key"}
+
+May return an ErrInvalidFilter error if the filter is invalid.
+*/
+func (f *Filter) ParseWithFields(filter string) (*spanner.Statement, []string, error) {
+	stmt, parsedExpr, err := f.parse(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fieldSet := make(map[string]bool)
+	collectFields(parsedExpr, fieldSet)
+	fields := make([]string, 0, len(fieldSet))
+	for field := range fieldSet {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return stmt, fields, nil
+}
+
+/*
+Canonicalize returns the canonical form of a filter expression, so that filters which only differ in formatting, e.g.
+in whitespace, quoting or keyword spelling, share the same form. This makes it suitable as a key to cache parsed
+statements by.
+
+The canonical form uses the CEL syntax, i.e. "&&", "||", "==" and "!" instead of "AND", "OR", "=" and "NOT", and
+double-quoted strings. It is itself a valid filter, which parses to the same statement as the original filter:
+
+	filter.Canonicalize("key = 'resources/1'   AND NOT (age BETWEEN 18 AND 65)")
+	// key == "resources/1" && !between(age, 18, 65)
+
+Only the syntax of the filter is checked, so a canonical form may still fail to Parse, e.g. if it uses an operator
+which is not allowed on an identifier.
+
+May return an ErrInvalidFilter error if the filter is invalid.
+*/
+func (f *Filter) Canonicalize(filter string) (string, error) {
+	sanitized := f.sanitize(filter)
+
+	ast, issues := f.env.Parse(sanitized)
+	if issues != nil && issues.Err() != nil {
+		return "", ErrInvalidFilter{
+			filter: sanitized,
+			err:    issues.Err(),
+		}
+	}
+
+	canonical, err := cel.AstToString(ast)
+	if err != nil {
+		return "", ErrInvalidFilter{
+			filter: sanitized,
+			err:    err,
+		}
+	}
+
+	return canonical, nil
+}
+
+// parse parses a CEL filter expression and returns a Spanner statement along with the parsed CEL expression.
+func (f *Filter) parse(filter string) (*spanner.Statement, *expr.Expr, error) {
 	filter = f.sanitize(filter)
 
 	ast, issues := f.env.Parse(filter)
 	if issues != nil && issues.Err() != nil {
-		return nil, ErrInvalidFilter{
+		return nil, nil, ErrInvalidFilter{
 			filter: filter,
 			err:    issues.Err(),
 		}
 	}
 
-	expr, err := cel.AstToParsedExpr(ast)
+	parsedExpr, err := cel.AstToParsedExpr(ast)
 	if err != nil {
-		return nil, ErrInvalidFilter{
+		return nil, nil, ErrInvalidFilter{
 			filter: filter,
 			err:    err,
 		}
 	}
 
-	sql, params, _, err := f.parseExpr(expr.GetExpr(), nil)
+	sql, params, _, err := f.parseExpr(parsedExpr.GetExpr(), nil)
 	if err != nil {
-		return nil, ErrInvalidFilter{
+		return nil, nil, ErrInvalidFilter{
 			filter: filter,
 			err:    err,
 		}
@@ -406,5 +797,5 @@ func (f *Filter) Parse(filter string) (*spanner.Statement, error) {
 	return &spanner.Statement{
 		SQL:    sql,
 		Params: params,
-	}, nil
+	}, parsedExpr.GetExpr(), nil
 }
@@ -1,6 +1,9 @@
 package filtering
 
 import (
+	"errors"
+	"math/big"
+	"reflect"
 	"testing"
 
 	"cloud.google.com/go/spanner"
@@ -67,3 +70,1062 @@ func TestNewFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestFilter_Restrict(t *testing.T) {
+	filter, err := NewFilter(
+		Restrict(Field("Proto.state"), OperatorEquals, OperatorIn),
+		Restrict(Timestamp("create_time"), OperatorGreaterThan, OperatorLessThan),
+		Restrict(Field("status"), OperatorIn),
+	)
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name    string
+		filter  string
+		wantErr bool
+	}{
+		{
+			name:    "TestFilter_Restrict_AllowedEquals",
+			filter:  "Proto.state = 'ACTIVE'",
+			wantErr: false,
+		},
+		{
+			name:    "TestFilter_Restrict_AllowedIn",
+			filter:  "Proto.state IN ['ACTIVE', 'PENDING']",
+			wantErr: false,
+		},
+		{
+			name:    "TestFilter_Restrict_AllowedRange",
+			filter:  "create_time > timestamp('2021-01-01T00:00:00Z') AND create_time < timestamp('2022-01-01T00:00:00Z')",
+			wantErr: false,
+		},
+		{
+			name:    "TestFilter_Restrict_UnrestrictedField",
+			filter:  "Proto.display_name >= 'A'",
+			wantErr: false,
+		},
+		{
+			name:    "TestFilter_Restrict_RejectedRange",
+			filter:  "Proto.state > 'ACTIVE'",
+			wantErr: true,
+		},
+		{
+			name:    "TestFilter_Restrict_RejectedPrefix",
+			filter:  "prefix(Proto.state, 'ACT')",
+			wantErr: true,
+		},
+		{
+			name:    "TestFilter_Restrict_RejectedNested",
+			filter:  "Proto.display_name = 'A' OR create_time >= timestamp('2021-01-01T00:00:00Z')",
+			wantErr: true,
+		},
+		{
+			name:    "TestFilter_Restrict_AllowedFunction",
+			filter:  "lower(Proto.state) = 'active'",
+			wantErr: false,
+		},
+		{
+			name:    "TestFilter_Restrict_RejectedFunction",
+			filter:  "lower(status) == 'x'",
+			wantErr: true,
+		},
+		{
+			name:    "TestFilter_Restrict_RejectedCast",
+			filter:  "cast(status, STRING) > 'a'",
+			wantErr: true,
+		},
+		{
+			name:    "TestFilter_Restrict_RejectedRightHandSide",
+			filter:  "'x' == status",
+			wantErr: true,
+		},
+		{
+			name:    "TestFilter_Restrict_RejectedFieldComparison",
+			filter:  "a < status",
+			wantErr: true,
+		},
+		{
+			name:    "TestFilter_Restrict_AllowedFieldComparison",
+			filter:  "create_time < Proto.end_time",
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Parse(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("filter.Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrOperatorNotAllowed{}) {
+					t.Errorf("filter.Parse() error = %v, want ErrOperatorNotAllowed", err)
+				}
+				return
+			}
+
+			t.Logf("got SQL: %s", got.SQL)
+			t.Logf("got Params: %+v", got.Params)
+		})
+	}
+}
+
+func TestFilter_ParseWithFields(t *testing.T) {
+	filter, err := NewFilter(
+		Timestamp("create_time"),
+		Reserved("key"),
+	)
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name       string
+		filter     string
+		wantFields []string
+	}{
+		{
+			name:       "TestFilter_ParseWithFields_Single",
+			filter:     "Proto.state = 'ACTIVE'",
+			wantFields: []string{"Proto.state"},
+		},
+		{
+			name:       "TestFilter_ParseWithFields_Complex",
+			filter:     "(Proto.state = 'ACTIVE' OR Proto.state IN ['PENDING']) AND create_time > timestamp('2021-01-01T00:00:00Z') AND prefix(key, 'resources/') AND Proto.effective_date.year >= 2021",
+			wantFields: []string{"Proto.effective_date.year", "Proto.state", "create_time", "key"},
+		},
+		{
+			name:       "TestFilter_ParseWithFields_Cast",
+			filter:     "cast(Proto.code, INT64) > 100",
+			wantFields: []string{"Proto.code"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotFields, err := filter.ParseWithFields(tt.filter)
+			if err != nil {
+				t.Errorf("filter.ParseWithFields() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(gotFields, tt.wantFields) {
+				t.Errorf("filter.ParseWithFields() fields = %v, want %v", gotFields, tt.wantFields)
+			}
+
+			t.Logf("got SQL: %s", got.SQL)
+		})
+	}
+}
+
+func TestFilter_ReservedKeywordSegments(t *testing.T) {
+	filter, err := NewFilter(Timestamp("Proto.window.end"))
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name    string
+		filter  string
+		wantSQL string
+	}{
+		{
+			name:    "TestFilter_ReservedKeywordSegments_Nested",
+			filter:  "Proto.order.status = 'ACTIVE'",
+			wantSQL: "Proto.`order`.status = @p0",
+		},
+		{
+			name:    "TestFilter_ReservedKeywordSegments_Leaf",
+			filter:  "user.address.group = 'A'",
+			wantSQL: "user.address.`group` = @p0",
+		},
+		{
+			name:    "TestFilter_ReservedKeywordSegments_Root",
+			filter:  "order.status = 'ACTIVE'",
+			wantSQL: "`order`.status = @p0",
+		},
+		{
+			name:    "TestFilter_ReservedKeywordSegments_Prefix",
+			filter:  "prefix(Proto.order.name, 'orders/')",
+			wantSQL: "STARTS_WITH(Proto.`order`.name, @p0)",
+		},
+		{
+			name:    "TestFilter_ReservedKeywordSegments_DeclaredIdentifier",
+			filter:  "Proto.window.end > timestamp('2021-01-01T00:00:00Z')",
+			wantSQL: "TIMESTAMP_ADD(TIMESTAMP_SECONDS(Proto.`window`.`end`.seconds),INTERVAL CAST(FLOOR(IFNULL(Proto.`window`.`end`.nanos,0) / 1000) AS INT64) MICROSECOND) > PARSE_TIMESTAMP('%c',@p0)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Parse(tt.filter)
+			if err != nil {
+				t.Errorf("filter.Parse() error = %v", err)
+				return
+			}
+			if got.SQL != tt.wantSQL {
+				t.Errorf("filter.Parse() SQL = %v, want %v", got.SQL, tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestFilter_Negation(t *testing.T) {
+	filter, err := NewFilter(
+		Timestamp("create_time"),
+		Restrict(Field("Proto.state"), OperatorIn),
+	)
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name       string
+		filter     string
+		wantSQL    string
+		wantParams map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "TestFilter_Negation_In",
+			filter:     "age IN [1, 2, 3]",
+			wantSQL:    "age IN UNNEST(@p0)",
+			wantParams: map[string]interface{}{"p0": []int64{1, 2, 3}},
+		},
+		{
+			name:       "TestFilter_Negation_NotIn",
+			filter:     "age not in [1, 2, 3]",
+			wantSQL:    "age NOT IN UNNEST(@p0)",
+			wantParams: map[string]interface{}{"p0": []int64{1, 2, 3}},
+		},
+		{
+			name:       "TestFilter_Negation_NotInCombined",
+			filter:     "name = 'Alice' AND Proto.status NOT IN ['ACTIVE']",
+			wantSQL:    "(name = @p0 AND Proto.status NOT IN UNNEST(@p1))",
+			wantParams: map[string]interface{}{"p0": "Alice", "p1": []string{"ACTIVE"}},
+		},
+		{
+			name:       "TestFilter_Negation_Between",
+			filter:     "age BETWEEN 18 AND 65",
+			wantSQL:    "age BETWEEN @p0 AND @p1",
+			wantParams: map[string]interface{}{"p0": "18", "p1": "65"},
+		},
+		{
+			name:       "TestFilter_Negation_NotBetween",
+			filter:     "age not between 18 and 65",
+			wantSQL:    "age NOT BETWEEN @p0 AND @p1",
+			wantParams: map[string]interface{}{"p0": "18", "p1": "65"},
+		},
+		{
+			name:    "TestFilter_Negation_NotBetweenTimestamps",
+			filter:  "create_time NOT BETWEEN timestamp('2021-01-01T00:00:00Z') AND timestamp('2022-01-01T00:00:00Z')",
+			wantSQL: "TIMESTAMP_ADD(TIMESTAMP_SECONDS(create_time.seconds),INTERVAL CAST(FLOOR(IFNULL(create_time.nanos,0) / 1000) AS INT64) MICROSECOND) NOT BETWEEN PARSE_TIMESTAMP('%c',@p0) AND PARSE_TIMESTAMP('%c',@p1)",
+			wantParams: map[string]interface{}{
+				"p0": "2021-01-01T00:00:00Z",
+				"p1": "2022-01-01T00:00:00Z",
+			},
+		},
+		{
+			name:       "TestFilter_Negation_Not",
+			filter:     "NOT (age > 18)",
+			wantSQL:    "NOT (age > @p0)",
+			wantParams: map[string]interface{}{"p0": "18"},
+		},
+		{
+			name:       "TestFilter_Negation_NotInStrings",
+			filter:     "Proto.status NOT IN ['ACTIVE', 'DONE']",
+			wantSQL:    "Proto.status NOT IN UNNEST(@p0)",
+			wantParams: map[string]interface{}{"p0": []string{"ACTIVE", "DONE"}},
+		},
+		{
+			name:       "TestFilter_Negation_LowercaseNot",
+			filter:     "not (age > 18)",
+			wantSQL:    "NOT (age > @p0)",
+			wantParams: map[string]interface{}{"p0": "18"},
+		},
+		{
+			name:       "TestFilter_Negation_KeywordsInLiterals",
+			filter:     "name = 'WILL NOT SHIP' AND note != 'SALT AND PEPPER' AND x = 'a NOT IN [b]'",
+			wantSQL:    "((name = @p0 AND note != @p1) AND x = @p2)",
+			wantParams: map[string]interface{}{"p0": "WILL NOT SHIP", "p1": "SALT AND PEPPER", "p2": "a NOT IN [b]"},
+		},
+		{
+			name:    "TestFilter_Negation_RestrictedNotIn",
+			filter:  "Proto.state NOT IN ['ACTIVE']",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Parse(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("filter.Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.SQL != tt.wantSQL {
+				t.Errorf("filter.Parse() SQL = %v, want %v", got.SQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(got.Params, tt.wantParams) {
+				t.Errorf("filter.Parse() Params = %v, want %v", got.Params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestFilter_QuotedMapKeys(t *testing.T) {
+	filter, err := NewFilter(
+		Restrict(Field("Proto.labels.env"), OperatorEquals),
+		JSON("metadata"),
+	)
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name       string
+		filter     string
+		wantSQL    string
+		wantParams map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "TestFilter_QuotedMapKeys_Hyphenated",
+			filter:     "labels.'my-key' = 'x'",
+			wantSQL:    "EXISTS(SELECT 1 FROM UNNEST(labels) AS _entry0 WHERE _entry0.key = @p0 AND _entry0.value = @p1)",
+			wantParams: map[string]interface{}{"p0": "my-key", "p1": "x"},
+		},
+		{
+			name:       "TestFilter_QuotedMapKeys_DoubleQuoted",
+			filter:     `Proto.labels."team/owner" = 'platform'`,
+			wantSQL:    "EXISTS(SELECT 1 FROM UNNEST(Proto.labels) AS _entry0 WHERE _entry0.key = @p0 AND _entry0.value = @p1)",
+			wantParams: map[string]interface{}{"p0": "team/owner", "p1": "platform"},
+		},
+		{
+			name:       "TestFilter_QuotedMapKeys_ReservedWord",
+			filter:     "labels.'group' != 'admins' AND Proto.labels.'env' = 'prod'",
+			wantSQL:    "(EXISTS(SELECT 1 FROM UNNEST(labels) AS _entry0 WHERE _entry0.key = @p0 AND _entry0.value != @p1) AND EXISTS(SELECT 1 FROM UNNEST(Proto.labels) AS _entry0 WHERE _entry0.key = @p2 AND _entry0.value = @p3))",
+			wantParams: map[string]interface{}{"p0": "group", "p1": "admins", "p2": "env", "p3": "prod"},
+		},
+		{
+			name:       "TestFilter_QuotedMapKeys_IndexSyntax",
+			filter:     "labels['my-key'] IN ['a', 'b']",
+			wantSQL:    "EXISTS(SELECT 1 FROM UNNEST(labels) AS _entry0 WHERE _entry0.key = @p0 AND _entry0.value IN UNNEST(@p1))",
+			wantParams: map[string]interface{}{"p0": "my-key", "p1": []string{"a", "b"}},
+		},
+		{
+			name:       "TestFilter_QuotedMapKeys_NotIn",
+			filter:     "!(labels['my-key'] in ['a'])",
+			wantSQL:    "EXISTS(SELECT 1 FROM UNNEST(labels) AS _entry0 WHERE _entry0.key = @p0 AND _entry0.value NOT IN UNNEST(@p1))",
+			wantParams: map[string]interface{}{"p0": "my-key", "p1": []string{"a"}},
+		},
+		{
+			name:       "TestFilter_QuotedMapKeys_Function",
+			filter:     "lower(labels.'my-key') = 'x'",
+			wantSQL:    "EXISTS(SELECT 1 FROM UNNEST(labels) AS _entry0 WHERE _entry0.key = @p0 AND LOWER(_entry0.value) = @p1)",
+			wantParams: map[string]interface{}{"p0": "my-key", "p1": "x"},
+		},
+		{
+			name:       "TestFilter_QuotedMapKeys_DotInValue",
+			filter:     "labels.'my-key' = 'v1.' OR version = 'v2'",
+			wantSQL:    "(EXISTS(SELECT 1 FROM UNNEST(labels) AS _entry0 WHERE _entry0.key = @p0 AND _entry0.value = @p1) OR version = @p2)",
+			wantParams: map[string]interface{}{"p0": "my-key", "p1": "v1.", "p2": "v2"},
+		},
+		{
+			name:       "TestFilter_QuotedMapKeys_JSON",
+			filter:     "metadata.'my-key' = 'x'",
+			wantSQL:    `JSON_VALUE(metadata, '$."my-key"') = @p0`,
+			wantParams: map[string]interface{}{"p0": "x"},
+		},
+		{
+			name:       "TestFilter_QuotedMapKeys_NestedJSON",
+			filter:     "metadata['owner']['team/name'] != 'platform'",
+			wantSQL:    `JSON_VALUE(metadata, '$."owner"."team/name"') != @p0`,
+			wantParams: map[string]interface{}{"p0": "platform"},
+		},
+		{
+			name:    "TestFilter_QuotedMapKeys_Restricted",
+			filter:  "Proto.labels.'env' > 'prod'",
+			wantErr: true,
+		},
+		{
+			name:    "TestFilter_QuotedMapKeys_NonStringKey",
+			filter:  "labels[1] = 'x'",
+			wantErr: true,
+		},
+		{
+			name:    "TestFilter_QuotedMapKeys_QuoteInJSONKey",
+			filter:  `metadata['a"b'] = 'x'`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Parse(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("filter.Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.SQL != tt.wantSQL {
+				t.Errorf("filter.Parse() SQL = %v, want %v", got.SQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(got.Params, tt.wantParams) {
+				t.Errorf("filter.Parse() Params = %v, want %v", got.Params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestFilter_Column(t *testing.T) {
+	filter, err := NewFilter(
+		Column(Timestamp("created"), "create_time"),
+		Restrict(Column(Field("state"), "Proto.state"), OperatorEquals, OperatorIn),
+		Column(Field("Proto.owner"), "CreatedBy"),
+	)
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+	if err := filter.DeclareIdentifier(Column(Field("display_name"), "Proto.display_name")); err != nil {
+		t.Errorf("DeclareIdentifier() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name    string
+		filter  string
+		wantSQL string
+		wantErr bool
+	}{
+		{
+			name:    "TestFilter_Column_Timestamp",
+			filter:  "created > timestamp('2021-01-01T00:00:00Z')",
+			wantSQL: "TIMESTAMP_ADD(TIMESTAMP_SECONDS(create_time.seconds),INTERVAL CAST(FLOOR(IFNULL(create_time.nanos,0) / 1000) AS INT64) MICROSECOND) > PARSE_TIMESTAMP('%c',@p0)",
+		},
+		{
+			name:    "TestFilter_Column_ProtoField",
+			filter:  "state = 'ACTIVE' OR state IN ['PENDING']",
+			wantSQL: "(Proto.state = @p0 OR Proto.state IN UNNEST(@p1))",
+		},
+		{
+			name:    "TestFilter_Column_SelectToColumn",
+			filter:  "prefix(Proto.owner, 'users/')",
+			wantSQL: "STARTS_WITH(CreatedBy, @p0)",
+		},
+		{
+			name:    "TestFilter_Column_Declared",
+			filter:  "display_name != 'A'",
+			wantSQL: "Proto.display_name != @p0",
+		},
+		{
+			name:    "TestFilter_Column_Restricted",
+			filter:  "state > 'ACTIVE'",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Parse(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("filter.Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.SQL != tt.wantSQL {
+				t.Errorf("filter.Parse() SQL = %v, want %v", got.SQL, tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestFilter_SearchAcross(t *testing.T) {
+	filter, err := NewFilter(Column(Field("title"), "Proto.display_name"))
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name       string
+		fields     []string
+		term       string
+		wantSQL    string
+		wantParams map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "TestFilter_SearchAcross_Single",
+			fields:     []string{"name"},
+			term:       "Acme",
+			wantSQL:    "LOWER(name) LIKE LOWER(@search)",
+			wantParams: map[string]interface{}{"search": "%Acme%"},
+		},
+		{
+			name:       "TestFilter_SearchAcross_Multiple",
+			fields:     []string{"title", "Proto.description", "Proto.order"},
+			term:       "acme",
+			wantSQL:    "(LOWER(Proto.display_name) LIKE LOWER(@search) OR LOWER(Proto.description) LIKE LOWER(@search) OR LOWER(Proto.`order`) LIKE LOWER(@search))",
+			wantParams: map[string]interface{}{"search": "%acme%"},
+		},
+		{
+			name:       "TestFilter_SearchAcross_EscapesWildcards",
+			fields:     []string{"name"},
+			term:       `50%_off\`,
+			wantSQL:    "LOWER(name) LIKE LOWER(@search)",
+			wantParams: map[string]interface{}{"search": `%50\%\_off\\%`},
+		},
+		{
+			name:    "TestFilter_SearchAcross_NoFields",
+			term:    "acme",
+			wantErr: true,
+		},
+		{
+			name:    "TestFilter_SearchAcross_InvalidField",
+			fields:  []string{"name) OR (1=1"},
+			term:    "acme",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.SearchAcross(tt.fields, tt.term)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("filter.SearchAcross() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.SQL != tt.wantSQL {
+				t.Errorf("filter.SearchAcross() SQL = %v, want %v", got.SQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(got.Params, tt.wantParams) {
+				t.Errorf("filter.SearchAcross() Params = %v, want %v", got.Params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestFilter_WithTableAlias(t *testing.T) {
+	base, err := NewFilter(
+		Timestamp("create_time"),
+		Column(Field("state"), "Proto.state"),
+	)
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+	filter, err := base.WithTableAlias("t")
+	if err != nil {
+		t.Errorf("WithTableAlias() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name    string
+		filter  string
+		wantSQL string
+	}{
+		{
+			name:    "TestFilter_WithTableAlias_Column",
+			filter:  "name = 'abc'",
+			wantSQL: "t.name = @p0",
+		},
+		{
+			name:    "TestFilter_WithTableAlias_Reserved",
+			filter:  "order = 1 AND Proto.group = 'a'",
+			wantSQL: "(t.`order` = @p0 AND t.Proto.`group` = @p1)",
+		},
+		{
+			name:    "TestFilter_WithTableAlias_Timestamp",
+			filter:  "create_time > timestamp('2021-01-01T00:00:00Z')",
+			wantSQL: "TIMESTAMP_ADD(TIMESTAMP_SECONDS(t.create_time.seconds),INTERVAL CAST(FLOOR(IFNULL(t.create_time.nanos,0) / 1000) AS INT64) MICROSECOND) > PARSE_TIMESTAMP('%c',@p0)",
+		},
+		{
+			name:    "TestFilter_WithTableAlias_MappedColumn",
+			filter:  "state IN ['ACTIVE'] OR prefix(Proto.owner, 'users/')",
+			wantSQL: "(t.Proto.state IN UNNEST(@p0) OR STARTS_WITH(t.Proto.owner, @p1))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Parse(tt.filter)
+			if err != nil {
+				t.Errorf("filter.Parse() error = %v", err)
+				return
+			}
+			if got.SQL != tt.wantSQL {
+				t.Errorf("filter.Parse() SQL = %v, want %v", got.SQL, tt.wantSQL)
+			}
+		})
+	}
+
+	// The original filter is not qualified.
+	got, err := base.Parse("name = 'abc'")
+	if err != nil {
+		t.Errorf("filter.Parse() error = %v", err)
+		return
+	}
+	if want := "name = @p0"; got.SQL != want {
+		t.Errorf("filter.Parse() SQL = %v, want %v", got.SQL, want)
+	}
+
+	if _, err := base.WithTableAlias("t; DROP"); err == nil {
+		t.Errorf("WithTableAlias() error = nil, want error for an invalid alias")
+	}
+}
+
+func TestFilter_Numeric(t *testing.T) {
+	filter, err := NewFilter(Numeric("amount"), Column(Numeric("total"), "Proto.total"))
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+
+	rat := func(s string) spanner.NullNumeric {
+		r, _ := new(big.Rat).SetString(s)
+		return spanner.NullNumeric{Numeric: *r, Valid: true}
+	}
+	tests := []struct {
+		name       string
+		filter     string
+		wantSQL    string
+		wantParams map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "TestFilter_Numeric_Decimal",
+			filter:     "amount > 19.99",
+			wantSQL:    "amount > @p0",
+			wantParams: map[string]interface{}{"p0": rat("19.99")},
+		},
+		{
+			name:       "TestFilter_Numeric_Integer",
+			filter:     "amount <= 20",
+			wantSQL:    "amount <= @p0",
+			wantParams: map[string]interface{}{"p0": rat("20")},
+		},
+		{
+			name:       "TestFilter_Numeric_QuotedDecimal",
+			filter:     "total = '0.000000001'",
+			wantSQL:    "Proto.total = @p0",
+			wantParams: map[string]interface{}{"p0": rat("0.000000001")},
+		},
+		{
+			name:       "TestFilter_Numeric_Between",
+			filter:     "amount BETWEEN 0.1 AND 0.3",
+			wantSQL:    "amount BETWEEN @p0 AND @p1",
+			wantParams: map[string]interface{}{"p0": rat("0.1"), "p1": rat("0.3")},
+		},
+		{
+			name:       "TestFilter_Numeric_Null",
+			filter:     "amount != null",
+			wantSQL:    "amount != @p0",
+			wantParams: map[string]interface{}{"p0": spanner.NullNumeric{}},
+		},
+		{
+			name:       "TestFilter_Numeric_OtherFieldUnaffected",
+			filter:     "price > 19.99",
+			wantSQL:    "price > @p0",
+			wantParams: map[string]interface{}{"p0": "19.990000"},
+		},
+		{
+			name:    "TestFilter_Numeric_InvalidLiteral",
+			filter:  "amount > 'abc'",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Parse(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("filter.Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.SQL != tt.wantSQL {
+				t.Errorf("filter.Parse() SQL = %v, want %v", got.SQL, tt.wantSQL)
+			}
+			if len(got.Params) != len(tt.wantParams) {
+				t.Fatalf("filter.Parse() Params = %v, want %v", got.Params, tt.wantParams)
+			}
+			for k, want := range tt.wantParams {
+				switch want := want.(type) {
+				case spanner.NullNumeric:
+					value, ok := got.Params[k].(spanner.NullNumeric)
+					if !ok {
+						t.Errorf("filter.Parse() Params[%s] = %T, want spanner.NullNumeric", k, got.Params[k])
+						continue
+					}
+					if value.Valid != want.Valid || value.Numeric.Cmp(&want.Numeric) != 0 {
+						t.Errorf("filter.Parse() Params[%s] = %v, want %v", k, value, want)
+					}
+				default:
+					if got.Params[k] != want {
+						t.Errorf("filter.Parse() Params[%s] = %v, want %v", k, got.Params[k], want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFilter_FieldComparison(t *testing.T) {
+	filter, err := NewFilter(
+		Timestamp("Proto.start_time"),
+		Timestamp("Proto.end_time"),
+		Column(Timestamp("deadline"), "Proto.deadline"),
+	)
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name       string
+		filter     string
+		wantSQL    string
+		wantParams map[string]interface{}
+	}{
+		{
+			name:       "TestFilter_FieldComparison_Numeric",
+			filter:     "start_balance < end_balance",
+			wantSQL:    "start_balance < end_balance",
+			wantParams: map[string]interface{}{},
+		},
+		{
+			name:       "TestFilter_FieldComparison_NestedNumeric",
+			filter:     "Proto.used >= Proto.quota AND Proto.used != 0",
+			wantSQL:    "(Proto.used >= Proto.quota AND Proto.used != @p0)",
+			wantParams: map[string]interface{}{"p0": "0"},
+		},
+		{
+			name:   "TestFilter_FieldComparison_Timestamp",
+			filter: "Proto.end_time > Proto.start_time",
+			wantSQL: "TIMESTAMP_ADD(TIMESTAMP_SECONDS(Proto.end_time.seconds),INTERVAL CAST(FLOOR(IFNULL(Proto.end_time.nanos,0) / 1000) AS INT64) MICROSECOND) > " +
+				"TIMESTAMP_ADD(TIMESTAMP_SECONDS(Proto.start_time.seconds),INTERVAL CAST(FLOOR(IFNULL(Proto.start_time.nanos,0) / 1000) AS INT64) MICROSECOND)",
+			wantParams: map[string]interface{}{},
+		},
+		{
+			name:   "TestFilter_FieldComparison_MappedTimestamp",
+			filter: "Proto.end_time <= deadline",
+			wantSQL: "TIMESTAMP_ADD(TIMESTAMP_SECONDS(Proto.end_time.seconds),INTERVAL CAST(FLOOR(IFNULL(Proto.end_time.nanos,0) / 1000) AS INT64) MICROSECOND) <= " +
+				"TIMESTAMP_ADD(TIMESTAMP_SECONDS(Proto.deadline.seconds),INTERVAL CAST(FLOOR(IFNULL(Proto.deadline.nanos,0) / 1000) AS INT64) MICROSECOND)",
+			wantParams: map[string]interface{}{},
+		},
+		{
+			name:       "TestFilter_FieldComparison_Reserved",
+			filter:     "order = Proto.group",
+			wantSQL:    "`order` = Proto.`group`",
+			wantParams: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Parse(tt.filter)
+			if err != nil {
+				t.Errorf("filter.Parse() error = %v", err)
+				return
+			}
+			if got.SQL != tt.wantSQL {
+				t.Errorf("filter.Parse() SQL = %v, want %v", got.SQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(got.Params, tt.wantParams) {
+				t.Errorf("filter.Parse() Params = %v, want %v", got.Params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestFilter_Canonicalize(t *testing.T) {
+	filter, err := NewFilter(Timestamp("Proto.create_time"))
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name     string
+		variants []string
+	}{
+		{
+			name: "TestFilter_Canonicalize_Whitespace",
+			variants: []string{
+				"key = 'resources/1' AND age > 18",
+				"key   =   'resources/1'   AND\tage>18",
+				"  key = 'resources/1'\nAND age > 18  ",
+			},
+		},
+		{
+			name: "TestFilter_Canonicalize_Quoting",
+			variants: []string{
+				"key = 'resources/1' OR Proto.create_time > timestamp('2021-01-01T00:00:00Z')",
+				`key = "resources/1" OR Proto.create_time > timestamp("2021-01-01T00:00:00Z")`,
+			},
+		},
+		{
+			name: "TestFilter_Canonicalize_Keywords",
+			variants: []string{
+				"NOT (key IN ['a', 'b']) AND deleted = NULL",
+				"!(key in ['a', 'b']) && deleted == null",
+				"key NOT IN ['a', 'b'] AND deleted = null",
+			},
+		},
+		{
+			name: "TestFilter_Canonicalize_Parentheses",
+			variants: []string{
+				"(age BETWEEN 18 AND 65) AND (name = 'abc')",
+				"age BETWEEN 18 AND 65 AND name = 'abc'",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := filter.Canonicalize(tt.variants[0])
+			if err != nil {
+				t.Fatalf("Canonicalize(%q) error = %v", tt.variants[0], err)
+			}
+			wantStmt, err := filter.Parse(tt.variants[0])
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.variants[0], err)
+			}
+			for _, variant := range tt.variants {
+				got, err := filter.Canonicalize(variant)
+				if err != nil {
+					t.Fatalf("Canonicalize(%q) error = %v", variant, err)
+				}
+				if got != want {
+					t.Errorf("Canonicalize(%q) = %q, want %q", variant, got, want)
+				}
+				// The canonical form must parse to the same statement as the original filter.
+				gotStmt, err := filter.Parse(got)
+				if err != nil {
+					t.Fatalf("Parse(%q) error = %v", got, err)
+				}
+				if !reflect.DeepEqual(gotStmt, wantStmt) {
+					t.Errorf("Parse(%q) = %v, want %v", got, gotStmt, wantStmt)
+				}
+			}
+		})
+	}
+
+	if _, err := filter.Canonicalize("key = "); !errors.Is(err, ErrInvalidFilter{}) {
+		t.Errorf("Canonicalize() error = %v, want ErrInvalidFilter", err)
+	}
+}
+
+func TestFilter_InNull(t *testing.T) {
+	filter, err := NewFilter()
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name       string
+		filter     string
+		wantSQL    string
+		wantParams map[string]interface{}
+	}{
+		{
+			name:       "TestFilter_InNull_Mixed",
+			filter:     "Proto.status in ['ACTIVE', null]",
+			wantSQL:    "(Proto.status IN UNNEST(@p0) OR Proto.status IS NULL)",
+			wantParams: map[string]interface{}{"p0": []string{"ACTIVE"}},
+		},
+		{
+			name:       "TestFilter_InNull_MixedUppercase",
+			filter:     "Proto.status IN [NULL, 'ACTIVE', 'PENDING']",
+			wantSQL:    "(Proto.status IN UNNEST(@p0) OR Proto.status IS NULL)",
+			wantParams: map[string]interface{}{"p0": []string{"ACTIVE", "PENDING"}},
+		},
+		{
+			name:       "TestFilter_InNull_MixedIntegers",
+			filter:     "Proto.priority in [1, null, 2, 3]",
+			wantSQL:    "(Proto.priority IN UNNEST(@p0) OR Proto.priority IS NULL)",
+			wantParams: map[string]interface{}{"p0": []int64{1, 2, 3}},
+		},
+		{
+			name:       "TestFilter_InNull_NotIn",
+			filter:     "Proto.status NOT IN ['ACTIVE', null]",
+			wantSQL:    "(Proto.status NOT IN UNNEST(@p0) AND Proto.status IS NOT NULL)",
+			wantParams: map[string]interface{}{"p0": []string{"ACTIVE"}},
+		},
+		{
+			name:       "TestFilter_InNull_OnlyNull",
+			filter:     "Proto.status in [null]",
+			wantSQL:    "Proto.status IS NULL",
+			wantParams: map[string]interface{}{},
+		},
+		{
+			name:       "TestFilter_InNull_ValuesOnly",
+			filter:     "Proto.status in ['ACTIVE', 'PENDING']",
+			wantSQL:    "Proto.status IN UNNEST(@p0)",
+			wantParams: map[string]interface{}{"p0": []string{"ACTIVE", "PENDING"}},
+		},
+		{
+			name:       "TestFilter_InNull_Floats",
+			filter:     "Proto.score in [1, 2.5]",
+			wantSQL:    "Proto.score IN UNNEST(@p0)",
+			wantParams: map[string]interface{}{"p0": []float64{1, 2.5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Parse(tt.filter)
+			if err != nil {
+				t.Errorf("filter.Parse() error = %v", err)
+				return
+			}
+			if got.SQL != tt.wantSQL {
+				t.Errorf("filter.Parse() SQL = %v, want %v", got.SQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(got.Params, tt.wantParams) {
+				t.Errorf("filter.Parse() Params = %v, want %v", got.Params, tt.wantParams)
+			}
+		})
+	}
+
+	if _, err := filter.Parse("Proto.status in ['ACTIVE', 1]"); err == nil {
+		t.Errorf("filter.Parse() error = nil, want an error for a list of mixed types")
+	}
+}
+
+func TestFilter_StringFunctions(t *testing.T) {
+	filter, err := NewFilter(Reserved("order"))
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name       string
+		filter     string
+		wantSQL    string
+		wantParams map[string]interface{}
+	}{
+		{
+			name:       "TestFilter_StringFunctions_LowerField",
+			filter:     "a == lower(b)",
+			wantSQL:    "a = LOWER(b)",
+			wantParams: map[string]interface{}{},
+		},
+		{
+			name:       "TestFilter_StringFunctions_Concat",
+			filter:     "x == concat('p', y)",
+			wantSQL:    "x = CONCAT(@p0, y)",
+			wantParams: map[string]interface{}{"p0": "p"},
+		},
+		{
+			name:       "TestFilter_StringFunctions_Nested",
+			filter:     "Proto.name != upper(concat(Proto.code, '-', order))",
+			wantSQL:    "Proto.name != UPPER(CONCAT(Proto.code, @p0, `order`))",
+			wantParams: map[string]interface{}{"p0": "-"},
+		},
+		{
+			name:       "TestFilter_StringFunctions_BothSides",
+			filter:     "lower(name) == lower('Alice')",
+			wantSQL:    "LOWER(name) = LOWER(@p0)",
+			wantParams: map[string]interface{}{"p0": "Alice"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Parse(tt.filter)
+			if err != nil {
+				t.Errorf("filter.Parse() error = %v", err)
+				return
+			}
+			if got.SQL != tt.wantSQL {
+				t.Errorf("filter.Parse() SQL = %v, want %v", got.SQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(got.Params, tt.wantParams) {
+				t.Errorf("filter.Parse() Params = %v, want %v", got.Params, tt.wantParams)
+			}
+		})
+	}
+
+	if _, err := filter.Parse("name == lower(a, b)"); err == nil {
+		t.Errorf("filter.Parse() error = nil, want an error for too many arguments")
+	}
+
+	// Restrictions apply to the identifiers passed to functions on either side of the comparison.
+	restricted, err := NewFilter(Restrict(Field("status"), OperatorIn))
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+	for _, rejected := range []string{"x == lower(status)", "x != concat('p', upper(status))", "lower(status) == x"} {
+		if _, err := restricted.Parse(rejected); !errors.Is(err, ErrOperatorNotAllowed{}) {
+			t.Errorf("filter.Parse(%q) error = %v, want ErrOperatorNotAllowed", rejected, err)
+		}
+	}
+}
+
+func TestFilter_Cast(t *testing.T) {
+	filter, err := NewFilter()
+	if err != nil {
+		t.Errorf("NewFilter() error = %v", err)
+		return
+	}
+
+	tests := []struct {
+		name       string
+		filter     string
+		wantSQL    string
+		wantParams map[string]interface{}
+	}{
+		{
+			name:       "TestFilter_Cast_Int64",
+			filter:     "cast(Proto.code, INT64) > 100",
+			wantSQL:    "CAST(Proto.code AS INT64) > @p0",
+			wantParams: map[string]interface{}{"p0": int64(100)},
+		},
+		{
+			name:       "TestFilter_Cast_String",
+			filter:     "cast(count, string) == '42'",
+			wantSQL:    "CAST(count AS STRING) = @p0",
+			wantParams: map[string]interface{}{"p0": "42"},
+		},
+		{
+			name:       "TestFilter_Cast_Nested",
+			filter:     "lower(cast(id, STRING)) == name",
+			wantSQL:    "LOWER(CAST(id AS STRING)) = name",
+			wantParams: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Parse(tt.filter)
+			if err != nil {
+				t.Errorf("filter.Parse() error = %v", err)
+				return
+			}
+			if got.SQL != tt.wantSQL {
+				t.Errorf("filter.Parse() SQL = %v, want %v", got.SQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(got.Params, tt.wantParams) {
+				t.Errorf("filter.Parse() Params = %v, want %v", got.Params, tt.wantParams)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"cast(code, UNKNOWN) > 1", "cast(code) > 1", "cast(code, INT64) > 'abc'"} {
+		if _, err := filter.Parse(invalid); err == nil {
+			t.Errorf("filter.Parse(%q) error = nil, want an error", invalid)
+		}
+	}
+}
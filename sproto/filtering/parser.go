@@ -3,9 +3,14 @@ package filtering
 import (
 	"encoding/base64"
 	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"cloud.google.com/go/spanner"
 	expr "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
 )
 
@@ -19,6 +24,14 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 	case *expr.Expr_CallExpr:
 		call := expression.GetCallExpr()
 
+		// Comparisons on the entries of proto map fields, e.g. labels['my-key'] == 'x', are evaluated against the
+		// matching entries, see parseMapEntries.
+		if operator, ok := predicateOperators[call.Function]; ok && f.containsMapEntry(expression) {
+			return f.parseMapEntries(call, operator, params, func(call *expr.Expr_Call, params map[string]any) (string, map[string]any, bool, error) {
+				return f.parseExpr(&expr.Expr{Id: expression.GetId(), ExprKind: &expr.Expr_CallExpr{CallExpr: call}}, params)
+			})
+		}
+
 		switch call.Function {
 		case "_&&_":
 			leftSQL, leftParams, _, err := f.parseExpr(call.Args[0], params)
@@ -53,6 +66,9 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 			}
 			return fmt.Sprintf("(%s OR %s)", leftSQL, rightSQL), params, false, nil
 		case "_>_":
+			if err := f.validateOperands(call, OperatorGreaterThan); err != nil {
+				return "", nil, false, err
+			}
 			leftSQL, _, _, err := f.parseExpr(call.Args[0], params)
 			if err != nil {
 				return "", nil, false, err
@@ -66,16 +82,30 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 			// and apply the necessary transformation
 			leftSQL = f.parseIdentifier(leftSQL)
 
-			// Check if the right side of the comparison is a function.
+			// Check if the right side of the comparison is another field, e.g. start_balance < end_balance.
+			// If it is, the same transformation applies to it
+			isField := exprPath(call.Args[1]) != ""
+			if isField {
+				rightSQL = f.parseIdentifier(rightSQL)
+			}
+
+			// Check if the right side of the comparison is a function or a field.
 			// If it is, we don't need to add it as a parameter but instead as a literal value
-			if isFunction {
+			if isFunction || isField {
 				return fmt.Sprintf("%s > %s", leftSQL, rightSQL), params, false, nil
 			}
 
+			value, err := f.paramValue(call.Args[0], call.Args[1], rightSQL)
+			if err != nil {
+				return "", nil, false, err
+			}
 			paramName := fmt.Sprintf("p%d", len(params))
-			params[paramName] = rightSQL
+			params[paramName] = value
 			return fmt.Sprintf("%s > @%s", leftSQL, paramName), params, false, nil
 		case "_>=_":
+			if err := f.validateOperands(call, OperatorGreaterThanOrEquals); err != nil {
+				return "", nil, false, err
+			}
 			leftSQL, _, _, err := f.parseExpr(call.Args[0], params)
 			if err != nil {
 				return "", nil, false, err
@@ -89,17 +119,31 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 			// and apply the necessary transformation
 			leftSQL = f.parseIdentifier(leftSQL)
 
-			// Check if the right side of the comparison is a function.
+			// Check if the right side of the comparison is another field, e.g. start_balance < end_balance.
+			// If it is, the same transformation applies to it
+			isField := exprPath(call.Args[1]) != ""
+			if isField {
+				rightSQL = f.parseIdentifier(rightSQL)
+			}
+
+			// Check if the right side of the comparison is a function or a field.
 			// If it is, we don't need to add it as a parameter but instead as a literal value
-			if isFunction {
+			if isFunction || isField {
 				return fmt.Sprintf("%s >= %s", leftSQL, rightSQL), params, false, nil
 			}
 
+			value, err := f.paramValue(call.Args[0], call.Args[1], rightSQL)
+			if err != nil {
+				return "", nil, false, err
+			}
 			paramName := fmt.Sprintf("p%d", len(params))
-			params[paramName] = rightSQL
+			params[paramName] = value
 			return fmt.Sprintf("%s >= @%s", leftSQL, paramName), params, false, nil
 
 		case "_<_":
+			if err := f.validateOperands(call, OperatorLessThan); err != nil {
+				return "", nil, false, err
+			}
 			leftSQL, _, _, err := f.parseExpr(call.Args[0], params)
 			if err != nil {
 				return "", nil, false, err
@@ -113,16 +157,30 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 			// and apply the necessary transformation
 			leftSQL = f.parseIdentifier(leftSQL)
 
-			// Check if the right side of the comparison is a function.
+			// Check if the right side of the comparison is another field, e.g. start_balance < end_balance.
+			// If it is, the same transformation applies to it
+			isField := exprPath(call.Args[1]) != ""
+			if isField {
+				rightSQL = f.parseIdentifier(rightSQL)
+			}
+
+			// Check if the right side of the comparison is a function or a field.
 			// If it is, we don't need to add it as a parameter but instead as a literal value
-			if isFunction {
+			if isFunction || isField {
 				return fmt.Sprintf("%s < %s", leftSQL, rightSQL), params, false, nil
 			}
 
+			value, err := f.paramValue(call.Args[0], call.Args[1], rightSQL)
+			if err != nil {
+				return "", nil, false, err
+			}
 			paramName := fmt.Sprintf("p%d", len(params))
-			params[paramName] = rightSQL
+			params[paramName] = value
 			return fmt.Sprintf("%s < @%s", leftSQL, paramName), params, false, nil
 		case "_<=_":
+			if err := f.validateOperands(call, OperatorLessThanOrEquals); err != nil {
+				return "", nil, false, err
+			}
 			leftSQL, _, _, err := f.parseExpr(call.Args[0], params)
 			if err != nil {
 				return "", nil, false, err
@@ -136,16 +194,30 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 			// and apply the necessary transformation
 			leftSQL = f.parseIdentifier(leftSQL)
 
-			// Check if the right side of the comparison is a function.
+			// Check if the right side of the comparison is another field, e.g. start_balance < end_balance.
+			// If it is, the same transformation applies to it
+			isField := exprPath(call.Args[1]) != ""
+			if isField {
+				rightSQL = f.parseIdentifier(rightSQL)
+			}
+
+			// Check if the right side of the comparison is a function or a field.
 			// If it is, we don't need to add it as a parameter but instead as a literal value
-			if isFunction {
+			if isFunction || isField {
 				return fmt.Sprintf("%s <= %s", leftSQL, rightSQL), params, false, nil
 			}
 
+			value, err := f.paramValue(call.Args[0], call.Args[1], rightSQL)
+			if err != nil {
+				return "", nil, false, err
+			}
 			paramName := fmt.Sprintf("p%d", len(params))
-			params[paramName] = rightSQL
+			params[paramName] = value
 			return fmt.Sprintf("%s <= @%s", leftSQL, paramName), params, false, nil
 		case "_==_":
+			if err := f.validateOperands(call, OperatorEquals); err != nil {
+				return "", nil, false, err
+			}
 			leftSQL, _, _, err := f.parseExpr(call.Args[0], params)
 			if err != nil {
 				return "", nil, false, err
@@ -159,16 +231,30 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 			// and apply the necessary transformation
 			leftSQL = f.parseIdentifier(leftSQL)
 
-			// Check if the right side of the comparison is a function.
+			// Check if the right side of the comparison is another field, e.g. start_balance < end_balance.
+			// If it is, the same transformation applies to it
+			isField := exprPath(call.Args[1]) != ""
+			if isField {
+				rightSQL = f.parseIdentifier(rightSQL)
+			}
+
+			// Check if the right side of the comparison is a function or a field.
 			// If it is, we don't need to add it as a parameter but instead as a literal value
-			if isFunction {
+			if isFunction || isField {
 				return fmt.Sprintf("%s = %s", leftSQL, rightSQL), params, false, nil
 			}
 
+			value, err := f.paramValue(call.Args[0], call.Args[1], rightSQL)
+			if err != nil {
+				return "", nil, false, err
+			}
 			paramName := fmt.Sprintf("p%d", len(params))
-			params[paramName] = rightSQL
+			params[paramName] = value
 			return fmt.Sprintf("%s = @%s", leftSQL, paramName), params, false, nil
 		case "_!=_":
+			if err := f.validateOperands(call, OperatorNotEquals); err != nil {
+				return "", nil, false, err
+			}
 			leftSQL, _, _, err := f.parseExpr(call.Args[0], params)
 			if err != nil {
 				return "", nil, false, err
@@ -182,14 +268,25 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 			// and apply the necessary transformation
 			leftSQL = f.parseIdentifier(leftSQL)
 
-			// Check if the right side of the comparison is a function.
+			// Check if the right side of the comparison is another field, e.g. start_balance < end_balance.
+			// If it is, the same transformation applies to it
+			isField := exprPath(call.Args[1]) != ""
+			if isField {
+				rightSQL = f.parseIdentifier(rightSQL)
+			}
+
+			// Check if the right side of the comparison is a function or a field.
 			// If it is, we don't need to add it as a parameter but instead as a literal value
-			if isFunction {
+			if isFunction || isField {
 				return fmt.Sprintf("%s != %s", leftSQL, rightSQL), params, false, nil
 			}
 
+			value, err := f.paramValue(call.Args[0], call.Args[1], rightSQL)
+			if err != nil {
+				return "", nil, false, err
+			}
 			paramName := fmt.Sprintf("p%d", len(params))
-			params[paramName] = rightSQL
+			params[paramName] = value
 			// TODO: Handle comparison of different types e.g NULL, FALSE
 			return fmt.Sprintf("%s != @%s", leftSQL, paramName), params, false, nil
 		case "timestamp", "TIMESTAMP":
@@ -216,10 +313,14 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 
 			return fmt.Sprintf("DATE(@%s)", paramName), params, true, nil
 		case "prefix", "PREFIX":
+			if err := f.validateOperands(call, OperatorPrefix); err != nil {
+				return "", nil, false, err
+			}
 			identSQL, _, _, err := f.parseExpr(call.Args[0], params)
 			if err != nil {
 				return "", nil, false, err
 			}
+			identSQL = f.parseIdentifier(identSQL)
 
 			constSQL, _, _, err := f.parseExpr(call.Args[1], params)
 			if err != nil {
@@ -231,10 +332,14 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 
 			return fmt.Sprintf("STARTS_WITH(%s, @%s)", identSQL, paramName), params, false, nil
 		case "suffix", "SUFFIX":
+			if err := f.validateOperands(call, OperatorSuffix); err != nil {
+				return "", nil, false, err
+			}
 			identSQL, _, _, err := f.parseExpr(call.Args[0], params)
 			if err != nil {
 				return "", nil, false, err
 			}
+			identSQL = f.parseIdentifier(identSQL)
 
 			constSQL, _, _, err := f.parseExpr(call.Args[1], params)
 			if err != nil {
@@ -245,16 +350,54 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 			params[paramName] = constSQL
 
 			return fmt.Sprintf("ENDS_WITH(%s, @%s)", identSQL, paramName), params, false, nil
+		case "lower", "LOWER", "upper", "UPPER", "concat", "CONCAT":
+			return f.parseStringFunction(call, params)
+		case "cast", "CAST":
+			return f.parseCast(call, params)
 		case "@in":
-			leftSQL, _, _, err := f.parseExpr(call.Args[0], params)
+			return f.parseIn(call, params, OperatorIn)
+		case "between", "BETWEEN":
+			return f.parseBetween(call, params, OperatorBetween)
+		case "_[_]":
+			// Keys of JSON identifiers, e.g. metadata.'my-key' or metadata['my-key'], are accessed using a JSONPath.
+			// The entries of proto map fields are only valid within a comparison, see parseMapEntries.
+			if !f.isJSONAccess(expression) {
+				return "", nil, false, fmt.Errorf("map entries can only be compared, e.g. labels['my-key'] = 'x'")
+			}
+			column, path, err := f.jsonPath(expression, params)
 			if err != nil {
 				return "", nil, false, err
 			}
-			rightSQL, _, _, err := f.parseExpr(call.Args[1], params)
+			return fmt.Sprintf("JSON_VALUE(%s, '%s')", column, path), params, false, nil
+		case "!_":
+			// Negated membership and range checks are emitted as NOT IN and NOT BETWEEN respectively,
+			// using the same parameter binding as their positive forms.
+			if negated := call.Args[0].GetCallExpr(); negated != nil {
+				switch negated.Function {
+				case "@in":
+					parseNotIn := func(call *expr.Expr_Call, params map[string]any) (string, map[string]any, bool, error) {
+						return f.parseIn(call, params, OperatorNotIn)
+					}
+					if f.containsMapEntry(call.Args[0]) {
+						return f.parseMapEntries(negated, OperatorNotIn, params, parseNotIn)
+					}
+					return parseNotIn(negated, params)
+				case "between", "BETWEEN":
+					parseNotBetween := func(call *expr.Expr_Call, params map[string]any) (string, map[string]any, bool, error) {
+						return f.parseBetween(call, params, OperatorNotBetween)
+					}
+					if f.containsMapEntry(call.Args[0]) {
+						return f.parseMapEntries(negated, OperatorNotBetween, params, parseNotBetween)
+					}
+					return parseNotBetween(negated, params)
+				}
+			}
+
+			operandSQL, _, _, err := f.parseExpr(call.Args[0], params)
 			if err != nil {
 				return "", nil, false, err
 			}
-			return fmt.Sprintf("%s IN (%s)", leftSQL, rightSQL), params, false, nil
+			return fmt.Sprintf("NOT (%s)", operandSQL), params, false, nil
 
 		default:
 			return "", nil, false, fmt.Errorf("unsupported function: %s", call.Function)
@@ -295,17 +438,12 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 
 		return operandSQL, params, false, nil
 	case *expr.Expr_ListExpr:
-		listExpr := expression.GetListExpr()
-		var sqlList []string
-		for _, elem := range listExpr.Elements {
-			elemSQL, _, _, err := f.parseExpr(elem, params)
-			if err != nil {
-				return "", nil, false, err
-			}
-			sqlList = append(sqlList, elemSQL)
+		value, err := f.listParamValue(nil, expression.GetListExpr().GetElements())
+		if err != nil {
+			return "", nil, false, err
 		}
 		paramName := fmt.Sprintf("p%d", len(params))
-		params[paramName] = strings.Join(sqlList, ", ")
+		params[paramName] = value
 
 		return fmt.Sprintf("@%s", paramName), params, false, nil
 	case *expr.Expr_StructExpr:
@@ -333,6 +471,464 @@ func (f *Filter) parseExpr(expression *expr.Expr, params map[string]any) (string
 	return "", params, false, nil
 }
 
+/*
+parseStringFunction handles the string functions lower, upper and concat, e.g. `name == lower(input)`, which may be
+used on either side of a comparison. Fields passed as arguments are transformed like any other identifier and
+literals are bound as parameters.
+*/
+func (f *Filter) parseStringFunction(call *expr.Expr_Call, params map[string]any) (string, map[string]any, bool, error) {
+	function := strings.ToUpper(call.Function)
+	if function != "CONCAT" && len(call.Args) != 1 {
+		return "", nil, false, fmt.Errorf("%s expects a single argument", call.Function)
+	}
+	if len(call.Args) == 0 {
+		return "", nil, false, fmt.Errorf("%s expects at least one argument", call.Function)
+	}
+
+	args := make([]string, 0, len(call.Args))
+	for _, arg := range call.Args {
+		argSQL, _, isFunction, err := f.parseExpr(arg, params)
+		if err != nil {
+			return "", nil, false, err
+		}
+		switch {
+		case exprPath(arg) != "":
+			argSQL = f.parseIdentifier(argSQL)
+		case arg.GetConstExpr() != nil:
+			paramName := fmt.Sprintf("p%d", len(params))
+			params[paramName] = argSQL
+			argSQL = "@" + paramName
+		case !isFunction:
+			return "", nil, false, fmt.Errorf("unsupported argument of %s: %v", call.Function, arg.GetExprKind())
+		}
+		args = append(args, argSQL)
+	}
+
+	return fmt.Sprintf("%s(%s)", function, strings.Join(args, ", ")), params, true, nil
+}
+
+// castTypes are the types a value may be converted to using cast.
+var castTypes = map[string]bool{
+	"BOOL": true, "BYTES": true, "DATE": true, "FLOAT64": true, "INT64": true, "NUMERIC": true, "STRING": true,
+	"TIMESTAMP": true,
+}
+
+/*
+parseCast handles the explicit conversion `cast(field, TYPE)`, e.g. `cast(code, INT64) > 100`, which is emitted as
+CAST(field AS TYPE). The type is case-insensitive and must be one of castTypes.
+*/
+func (f *Filter) parseCast(call *expr.Expr_Call, params map[string]any) (string, map[string]any, bool, error) {
+	if len(call.Args) != 2 {
+		return "", nil, false, fmt.Errorf("%s expects a value and a type", call.Function)
+	}
+	target, err := castType(call)
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	arg := call.Args[0]
+	argSQL, _, isFunction, err := f.parseExpr(arg, params)
+	if err != nil {
+		return "", nil, false, err
+	}
+	switch {
+	case exprPath(arg) != "":
+		argSQL = f.parseIdentifier(argSQL)
+	case arg.GetConstExpr() != nil:
+		paramName := fmt.Sprintf("p%d", len(params))
+		params[paramName] = argSQL
+		argSQL = "@" + paramName
+	case !isFunction:
+		return "", nil, false, fmt.Errorf("unsupported argument of %s: %v", call.Function, arg.GetExprKind())
+	}
+
+	return fmt.Sprintf("CAST(%s AS %s)", argSQL, target), params, true, nil
+}
+
+// castType returns the validated target type of a cast call.
+func castType(call *expr.Expr_Call) (string, error) {
+	name := call.Args[1].GetIdentExpr().GetName()
+	if name == "" {
+		name = call.Args[1].GetConstExpr().GetStringValue()
+	}
+	target := strings.ToUpper(name)
+	if !castTypes[target] {
+		return "", fmt.Errorf("unsupported type of %s: %q", call.Function, name)
+	}
+	return target, nil
+}
+
+/*
+parseIn handles the membership check `x in [a, b]`, emitting either IN or NOT IN depending on the operator.
+
+The elements of the list are bound as a single typed array, e.g. []string or []int64, which is expanded using UNNEST:
+`x IN UNNEST(@p0)`.
+*/
+func (f *Filter) parseIn(call *expr.Expr_Call, params map[string]any, operator Operator) (string, map[string]any, bool, error) {
+	if err := f.validateOperands(call, operator); err != nil {
+		return "", nil, false, err
+	}
+	leftSQL, _, _, err := f.parseExpr(call.Args[0], params)
+	if err != nil {
+		return "", nil, false, err
+	}
+	leftSQL = f.parseIdentifier(leftSQL)
+
+	// Membership in an array field, e.g. `x in Proto.tags`, is checked against its elements.
+	if exprPath(call.Args[1]) != "" {
+		rightSQL, _, _, err := f.parseExpr(call.Args[1], params)
+		if err != nil {
+			return "", nil, false, err
+		}
+		return fmt.Sprintf("%s %s UNNEST(%s)", leftSQL, operator, f.parseIdentifier(rightSQL)), params, false, nil
+	}
+	if call.Args[1].GetListExpr() == nil {
+		return "", nil, false, fmt.Errorf("%s expects a list or a field", operator)
+	}
+
+	// NULL never matches IN, and cannot be bound within a typed array, so null elements are removed from the list and
+	// checked using IS NULL instead, e.g. `x in ['a', null]` becomes `(x IN UNNEST(@p0) OR x IS NULL)`.
+	values, hasNull := withoutNulls(call.Args[1])
+	elements := values.GetListExpr().GetElements()
+	if hasNull && len(elements) == 0 {
+		if operator == OperatorNotIn {
+			return fmt.Sprintf("%s IS NOT NULL", leftSQL), params, false, nil
+		}
+		return fmt.Sprintf("%s IS NULL", leftSQL), params, false, nil
+	}
+
+	value, err := f.listParamValue(call.Args[0], elements)
+	if err != nil {
+		return "", nil, false, err
+	}
+	paramName := fmt.Sprintf("p%d", len(params))
+	params[paramName] = value
+	sql := fmt.Sprintf("%s %s UNNEST(@%s)", leftSQL, operator, paramName)
+
+	if hasNull {
+		if operator == OperatorNotIn {
+			return fmt.Sprintf("(%s AND %s IS NOT NULL)", sql, leftSQL), params, false, nil
+		}
+		return fmt.Sprintf("(%s OR %s IS NULL)", sql, leftSQL), params, false, nil
+	}
+	return sql, params, false, nil
+}
+
+/*
+listParamValue returns the typed array to bind for the elements of a list, e.g. []string for `['a', 'b']`, which must
+all be literals of the same type. Integers are converted to FLOAT64 if the list also contains floating point literals.
+
+The elements of a list compared to a Numeric identifier are bound as []spanner.NullNumeric, like the other literals
+compared to it.
+*/
+func (f *Filter) listParamValue(operand *expr.Expr, elements []*expr.Expr) (any, error) {
+	if _, ok := f.identifiers[exprPath(operand)].(numericIdentifier); ok {
+		values := make([]spanner.NullNumeric, len(elements))
+		for i, elem := range elements {
+			value, err := f.paramValue(operand, elem, "")
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value.(spanner.NullNumeric)
+		}
+		return values, nil
+	}
+
+	var kind string
+	for _, elem := range elements {
+		var elemKind string
+		switch elem.GetConstExpr().GetConstantKind().(type) {
+		case *expr.Constant_StringValue:
+			elemKind = "STRING"
+		case *expr.Constant_Int64Value:
+			elemKind = "INT64"
+		case *expr.Constant_DoubleValue:
+			elemKind = "FLOAT64"
+		case *expr.Constant_BoolValue:
+			elemKind = "BOOL"
+		case *expr.Constant_BytesValue:
+			elemKind = "BYTES"
+		default:
+			return nil, fmt.Errorf("lists may only contain string, integer, floating point, boolean or bytes literals")
+		}
+
+		switch {
+		case kind == "" || kind == elemKind:
+			kind = elemKind
+		case (kind == "INT64" || kind == "FLOAT64") && (elemKind == "INT64" || elemKind == "FLOAT64"):
+			kind = "FLOAT64"
+		default:
+			return nil, fmt.Errorf("lists must contain literals of a single type, got %s and %s", kind, elemKind)
+		}
+	}
+
+	switch kind {
+	case "INT64":
+		values := make([]int64, len(elements))
+		for i, elem := range elements {
+			values[i] = elem.GetConstExpr().GetInt64Value()
+		}
+		return values, nil
+	case "FLOAT64":
+		values := make([]float64, len(elements))
+		for i, elem := range elements {
+			if constant, ok := elem.GetConstExpr().GetConstantKind().(*expr.Constant_Int64Value); ok {
+				values[i] = float64(constant.Int64Value)
+			} else {
+				values[i] = elem.GetConstExpr().GetDoubleValue()
+			}
+		}
+		return values, nil
+	case "BOOL":
+		values := make([]bool, len(elements))
+		for i, elem := range elements {
+			values[i] = elem.GetConstExpr().GetBoolValue()
+		}
+		return values, nil
+	case "BYTES":
+		values := make([][]byte, len(elements))
+		for i, elem := range elements {
+			values[i] = elem.GetConstExpr().GetBytesValue()
+		}
+		return values, nil
+	default:
+		values := make([]string, len(elements))
+		for i, elem := range elements {
+			values[i] = elem.GetConstExpr().GetStringValue()
+		}
+		return values, nil
+	}
+}
+
+// withoutNulls returns a copy of the provided list expression without its null elements, and whether it had any.
+// Any other expression is returned as is.
+func withoutNulls(expression *expr.Expr) (*expr.Expr, bool) {
+	listExpr := expression.GetListExpr()
+	if listExpr == nil {
+		return expression, false
+	}
+
+	elements := make([]*expr.Expr, 0, len(listExpr.GetElements()))
+	for _, elem := range listExpr.GetElements() {
+		if _, isNull := elem.GetConstExpr().GetConstantKind().(*expr.Constant_NullValue); isNull {
+			continue
+		}
+		elements = append(elements, elem)
+	}
+	if len(elements) == len(listExpr.GetElements()) {
+		return expression, false
+	}
+
+	return &expr.Expr{
+		Id: expression.GetId(),
+		ExprKind: &expr.Expr_ListExpr{
+			ListExpr: &expr.Expr_CreateList{Elements: elements},
+		},
+	}, true
+}
+
+// parseBetween handles the range check `between(x, a, b)`, emitting either BETWEEN or NOT BETWEEN depending on the
+// operator.
+func (f *Filter) parseBetween(call *expr.Expr_Call, params map[string]any, operator Operator) (string, map[string]any, bool, error) {
+	if len(call.Args) != 3 {
+		return "", nil, false, fmt.Errorf("%s expects 3 arguments, got %d", operator, len(call.Args))
+	}
+	if err := f.validateOperands(call, operator); err != nil {
+		return "", nil, false, err
+	}
+	leftSQL, _, _, err := f.parseExpr(call.Args[0], params)
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	// Check if the left side of the comparison is a registered identifier
+	// and apply the necessary transformation
+	leftSQL = f.parseIdentifier(leftSQL)
+
+	bounds := make([]string, 2)
+	for i, arg := range call.Args[1:] {
+		boundSQL, _, isFunction, err := f.parseExpr(arg, params)
+		if err != nil {
+			return "", nil, false, err
+		}
+
+		// Functions are added as literal values, like in the other comparisons
+		if isFunction {
+			bounds[i] = boundSQL
+			continue
+		}
+
+		value, err := f.paramValue(call.Args[0], arg, boundSQL)
+		if err != nil {
+			return "", nil, false, err
+		}
+		paramName := fmt.Sprintf("p%d", len(params))
+		params[paramName] = value
+		bounds[i] = "@" + paramName
+	}
+
+	return fmt.Sprintf("%s %s %s AND %s", leftSQL, operator, bounds[0], bounds[1]), params, false, nil
+}
+
+// predicateOperators are the operators of the comparison functions, which may be applied to the entries of proto map
+// fields.
+var predicateOperators = map[string]Operator{
+	"_==_": OperatorEquals, "_!=_": OperatorNotEquals, "_<_": OperatorLessThan, "_<=_": OperatorLessThanOrEquals,
+	"_>_": OperatorGreaterThan, "_>=_": OperatorGreaterThanOrEquals, "@in": OperatorIn, "between": OperatorBetween,
+	"BETWEEN": OperatorBetween, "prefix": OperatorPrefix, "PREFIX": OperatorPrefix, "suffix": OperatorSuffix,
+	"SUFFIX": OperatorSuffix,
+}
+
+// mapEntryAlias is the prefix of the aliases of the proto map entries unnested by parseMapEntries.
+const mapEntryAlias = "_entry"
+
+/*
+parseMapEntries handles a comparison on the entries of proto map fields, which are stored as repeated key/value
+messages, by checking that a matching entry exists, e.g. `labels['my-key'] == 'x'` becomes
+`EXISTS(SELECT 1 FROM UNNEST(labels) AS _entry0 WHERE _entry0.key = @p0 AND _entry0.value = @p1)`. The keys are bound
+as parameters, so they may contain any character.
+
+The operator is validated against the entries as written in the filter, e.g. labels.my-key, before they are replaced
+with the value of the matching entry and the comparison is parsed using parse.
+*/
+func (f *Filter) parseMapEntries(call *expr.Expr_Call, operator Operator, params map[string]any, parse func(call *expr.Expr_Call, params map[string]any) (string, map[string]any, bool, error)) (string, map[string]any, bool, error) {
+	if err := f.validateOperands(call, operator); err != nil {
+		return "", nil, false, err
+	}
+
+	var unnests, conditions []string
+	args := make([]*expr.Expr, len(call.Args))
+	for i, arg := range call.Args {
+		replaced, err := f.replaceMapEntries(arg, params, &unnests, &conditions)
+		if err != nil {
+			return "", nil, false, err
+		}
+		args[i] = replaced
+	}
+
+	sql, params, _, err := parse(&expr.Expr_Call{Target: call.Target, Function: call.Function, Args: args}, params)
+	if err != nil {
+		return "", nil, false, err
+	}
+	conditions = append(conditions, sql)
+
+	return fmt.Sprintf("EXISTS(SELECT 1 FROM %s WHERE %s)", strings.Join(unnests, ", "), strings.Join(conditions, " AND ")), params, false, nil
+}
+
+// replaceMapEntries returns a copy of the expression in which the entries of proto map fields are replaced with the
+// value of the matching entry, adding the UNNEST of each map field and the condition on its key.
+func (f *Filter) replaceMapEntries(expression *expr.Expr, params map[string]any, unnests *[]string, conditions *[]string) (*expr.Expr, error) {
+	switch kind := expression.GetExprKind().(type) {
+	case *expr.Expr_SelectExpr:
+		operand, err := f.replaceMapEntries(kind.SelectExpr.GetOperand(), params, unnests, conditions)
+		if err != nil {
+			return nil, err
+		}
+		return &expr.Expr{
+			Id: expression.GetId(),
+			ExprKind: &expr.Expr_SelectExpr{
+				SelectExpr: &expr.Expr_Select{Operand: operand, Field: kind.SelectExpr.GetField(), TestOnly: kind.SelectExpr.GetTestOnly()},
+			},
+		}, nil
+	case *expr.Expr_CallExpr:
+		call := kind.CallExpr
+		if call.GetFunction() == "_[_]" && !f.isJSONAccess(expression) {
+			key, err := quotedKey(call.Args[1])
+			if err != nil {
+				return nil, err
+			}
+			operandSQL, _, _, err := f.parseExpr(call.Args[0], params)
+			if err != nil {
+				return nil, err
+			}
+
+			alias := fmt.Sprintf("%s%d", mapEntryAlias, len(*unnests))
+			paramName := fmt.Sprintf("p%d", len(params))
+			params[paramName] = key
+			*unnests = append(*unnests, fmt.Sprintf("UNNEST(%s) AS %s", f.parseIdentifier(operandSQL), alias))
+			*conditions = append(*conditions, fmt.Sprintf("%s.key = @%s", alias, paramName))
+
+			return &expr.Expr{
+				Id:       expression.GetId(),
+				ExprKind: &expr.Expr_IdentExpr{IdentExpr: &expr.Expr_Ident{Name: alias + ".value"}},
+			}, nil
+		}
+
+		// Entries passed to functions, e.g. lower(labels['env']), are replaced as well.
+		args := make([]*expr.Expr, len(call.GetArgs()))
+		for i, arg := range call.GetArgs() {
+			replaced, err := f.replaceMapEntries(arg, params, unnests, conditions)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = replaced
+		}
+		return &expr.Expr{
+			Id:       expression.GetId(),
+			ExprKind: &expr.Expr_CallExpr{CallExpr: &expr.Expr_Call{Target: call.GetTarget(), Function: call.GetFunction(), Args: args}},
+		}, nil
+	default:
+		return expression, nil
+	}
+}
+
+// containsMapEntry reports whether the expression accesses an entry of a proto map field, including within the
+// arguments of a function.
+func (f *Filter) containsMapEntry(expression *expr.Expr) bool {
+	switch kind := expression.GetExprKind().(type) {
+	case *expr.Expr_SelectExpr:
+		return f.containsMapEntry(kind.SelectExpr.GetOperand())
+	case *expr.Expr_CallExpr:
+		if kind.CallExpr.GetFunction() == "_[_]" && !f.isJSONAccess(expression) {
+			return true
+		}
+		for _, arg := range kind.CallExpr.GetArgs() {
+			if f.containsMapEntry(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isJSONAccess reports whether the index expression accesses a key of a JSON identifier, e.g. metadata['a']['b'] for
+// JSON("metadata").
+func (f *Filter) isJSONAccess(expression *expr.Expr) bool {
+	operand := expression.GetCallExpr().GetArgs()[0]
+	if operand.GetCallExpr().GetFunction() == "_[_]" {
+		return f.isJSONAccess(operand)
+	}
+	_, ok := f.identifiers[exprPath(operand)].(jsonIdentifier)
+	return ok
+}
+
+// jsonPath returns the column and the JSONPath accessed by an index expression on a JSON identifier, e.g. metadata and
+// $."a"."b" for metadata['a']['b'].
+func (f *Filter) jsonPath(expression *expr.Expr, params map[string]any) (string, string, error) {
+	call := expression.GetCallExpr()
+	key, err := quotedKey(call.Args[1])
+	if err != nil {
+		return "", "", err
+	}
+	// The key is embedded in a string literal, it may therefore not contain quotes, escapes or line breaks.
+	if strings.ContainsAny(key, "'\"\\\n\r") {
+		return "", "", fmt.Errorf("invalid JSON key: %q", key)
+	}
+
+	if operand := call.Args[0]; operand.GetCallExpr().GetFunction() == "_[_]" {
+		column, path, err := f.jsonPath(operand, params)
+		if err != nil {
+			return "", "", err
+		}
+		return column, fmt.Sprintf(`%s."%s"`, path, key), nil
+	}
+	columnSQL, _, _, err := f.parseExpr(call.Args[0], params)
+	if err != nil {
+		return "", "", err
+	}
+	return f.parseIdentifier(columnSQL), fmt.Sprintf(`$."%s"`, key), nil
+}
+
 // parseSelectExpr handles field selection (e.g., `message.field`) in CEL expressions
 func (f *Filter) parseSelectExpr(selectExpr *expr.Expr_Select, params map[string]interface{}) (string, error) {
 	// Recursively resolve the operand (which could itself be a SelectExpr or IdentExpr)
@@ -341,15 +937,35 @@ func (f *Filter) parseSelectExpr(selectExpr *expr.Expr_Select, params map[string
 		return "", err
 	}
 
-	// Concatenate the operand and field (e.g., `user.address.city`)
-	return fmt.Sprintf("%s.%s", operandSQL, selectExpr.Field), nil
+	// Concatenate the operand and field (e.g., `user.address.city`), quoting the field if it is a reserved keyword.
+	return fmt.Sprintf("%s.%s", operandSQL, quoteReservedKeyword(selectExpr.Field)), nil
 }
 
 func (f *Filter) parseIdentifier(sql string) string {
-	if ident, ok := f.identifiers[sql]; ok {
+	// Function calls, e.g. LOWER(name), have already been translated along with their arguments.
+	if strings.Contains(sql, "(") {
+		return sql
+	}
+
+	// Paths already qualified with the table alias are looked up without it, and qualified again below.
+	qualified := false
+	if f.tableAlias != "" && strings.HasPrefix(sql, f.tableAlias+".") {
+		sql = strings.TrimPrefix(sql, f.tableAlias+".")
+		qualified = true
+	}
+
+	// Identifiers are registered by their unquoted path.
+	path := strings.ReplaceAll(sql, "`", "")
+	if ident, ok := f.identifiers[path]; ok {
+		// Identifiers mapped to a differently named column are emitted as that column, before any conversion.
+		if column, ok := f.columns[path]; ok {
+			sql = column
+		}
+		if _, ok := ident.(reservedIdentifier); ok {
+			sql = fmt.Sprintf("`%s`", strings.Trim(sql, "`"))
+		}
+		sql = f.qualify(sql)
 		switch ident.(type) {
-		case reservedIdentifier:
-			sql = fmt.Sprintf("`%s`", sql)
 		case timestampIdentifier:
 			sql = fmt.Sprintf("TIMESTAMP_ADD(TIMESTAMP_SECONDS(%s.seconds),INTERVAL CAST(FLOOR(IFNULL(%s.nanos,0) / 1000) AS INT64) MICROSECOND)", sql, sql)
 		case durationIdentifier:
@@ -357,7 +973,230 @@ func (f *Filter) parseIdentifier(sql string) string {
 		case dateIdentifier:
 			sql = fmt.Sprintf("DATE(%s.year, %s.month, %s.day)", sql, sql, sql)
 		}
+	} else if !strings.Contains(sql, ".") {
+		sql = f.qualify(quoteReservedKeyword(sql))
+	} else if qualified {
+		sql = f.qualify(sql)
 	}
 
 	return sql
 }
+
+// qualify prefixes the column with the table alias, if any.
+func (f *Filter) qualify(column string) string {
+	if f.tableAlias == "" {
+		return column
+	}
+	return f.tableAlias + "." + column
+}
+
+/*
+paramValue returns the value to bind for the literal compared to the operand.
+
+Literals compared to a Numeric identifier are bound as spanner.NullNumeric, so the comparison against the NUMERIC
+column is exact. All other literals are bound as their SQL representation.
+*/
+func (f *Filter) paramValue(operand *expr.Expr, literal *expr.Expr, literalSQL string) (any, error) {
+	if call := operand.GetCallExpr(); call != nil && strings.EqualFold(call.Function, "cast") && len(call.Args) == 2 {
+		return castParamValue(call, literal, literalSQL)
+	}
+	if _, ok := f.identifiers[exprPath(operand)].(numericIdentifier); !ok {
+		return literalSQL, nil
+	}
+
+	var value string
+	switch constant := literal.GetConstExpr().GetConstantKind().(type) {
+	case *expr.Constant_NullValue:
+		return spanner.NullNumeric{}, nil
+	case *expr.Constant_Int64Value:
+		value = strconv.FormatInt(constant.Int64Value, 10)
+	case *expr.Constant_DoubleValue:
+		// The shortest representation which round-trips is the decimal literal as written in the filter.
+		value = strconv.FormatFloat(constant.DoubleValue, 'f', -1, 64)
+	case *expr.Constant_StringValue:
+		value = constant.StringValue
+	default:
+		return nil, fmt.Errorf("%s expects a numeric literal", exprPath(operand))
+	}
+
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, fmt.Errorf("%s expects a numeric literal, got %q", exprPath(operand), value)
+	}
+	return spanner.NullNumeric{Numeric: *r, Valid: true}, nil
+}
+
+/*
+castParamValue returns the value to bind for the literal compared to a cast, e.g. `cast(code, INT64) > 100`.
+
+Literals compared to a cast to INT64, FLOAT64 or BOOL are bound with the matching type, so that the comparison is
+valid. All other literals are bound as their SQL representation.
+*/
+func castParamValue(call *expr.Expr_Call, literal *expr.Expr, literalSQL string) (any, error) {
+	target, err := castType(call)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := literal.GetConstExpr().GetConstantKind().(*expr.Constant_NullValue); ok {
+		return literalSQL, nil
+	}
+	var value any
+	switch target {
+	case "INT64":
+		value, err = strconv.ParseInt(literalSQL, 10, 64)
+	case "FLOAT64":
+		value, err = strconv.ParseFloat(literalSQL, 64)
+	case "BOOL":
+		value, err = strconv.ParseBool(literalSQL)
+	default:
+		return literalSQL, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s expects a %s literal, got %q", call.Function, target, literalSQL)
+	}
+	return value, nil
+}
+
+/*
+validateOperands ensures the operator is allowed on the identifiers referenced by any of the operands of the call, so
+that a restricted identifier cannot be compared by placing it on the right-hand side, e.g. `'x' == status`.
+*/
+func (f *Filter) validateOperands(call *expr.Expr_Call, operator Operator) error {
+	for _, operand := range call.Args {
+		if err := f.validateOperator(operand, operator); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+validateOperator ensures the operator is allowed on the identifiers referenced by the operand, if any of them is
+restricted. The identifiers passed to functions and casts are resolved as well, e.g. status in `lower(status)` or
+`cast(status, STRING)`, since the operator still applies to them.
+*/
+func (f *Filter) validateOperator(operand *expr.Expr, operator Operator) error {
+	fields := make(map[string]bool)
+	collectFields(operand, fields)
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		allowed, ok := f.allowedOperators[path]
+		if !ok || slices.Contains(allowed, operator) {
+			continue
+		}
+		return ErrOperatorNotAllowed{
+			identifier: path,
+			operator:   operator,
+			allowed:    allowed,
+		}
+	}
+
+	return nil
+}
+
+// exprPath returns the dotted path of an identifier or field selection expression (e.g. `Proto.state`).
+// An empty string is returned for any other expression.
+func exprPath(expression *expr.Expr) string {
+	switch expression.GetExprKind().(type) {
+	case *expr.Expr_IdentExpr:
+		return expression.GetIdentExpr().GetName()
+	case *expr.Expr_SelectExpr:
+		operandPath := exprPath(expression.GetSelectExpr().GetOperand())
+		if operandPath == "" {
+			return ""
+		}
+		return operandPath + "." + expression.GetSelectExpr().GetField()
+	case *expr.Expr_CallExpr:
+		call := expression.GetCallExpr()
+		if call.GetFunction() != "_[_]" {
+			return ""
+		}
+		operandPath := exprPath(call.GetArgs()[0])
+		key, err := quotedKey(call.GetArgs()[1])
+		if operandPath == "" || err != nil {
+			return ""
+		}
+		return operandPath + "." + key
+	default:
+		return ""
+	}
+}
+
+// quotedKey returns the map key or field name of an index expression, which must be a string constant.
+func quotedKey(expression *expr.Expr) (string, error) {
+	constant, ok := expression.GetExprKind().(*expr.Expr_ConstExpr)
+	if !ok {
+		return "", fmt.Errorf("map keys must be string constants")
+	}
+	key, ok := constant.ConstExpr.GetConstantKind().(*expr.Constant_StringValue)
+	if !ok {
+		return "", fmt.Errorf("map keys must be string constants")
+	}
+	if key.StringValue == "" || strings.Contains(key.StringValue, "`") {
+		return "", fmt.Errorf("invalid map key: %q", key.StringValue)
+	}
+	return key.StringValue, nil
+}
+
+// collectFields adds the paths of all identifiers and field selections referenced in the expression to fields.
+func collectFields(expression *expr.Expr, fields map[string]bool) {
+	switch expression.GetExprKind().(type) {
+	case *expr.Expr_IdentExpr, *expr.Expr_SelectExpr:
+		if path := exprPath(expression); path != "" {
+			fields[path] = true
+		}
+	case *expr.Expr_CallExpr:
+		if path := exprPath(expression); path != "" {
+			fields[path] = true
+			return
+		}
+		// The second argument of cast is the target type, not a field.
+		if call := expression.GetCallExpr(); strings.EqualFold(call.GetFunction(), "cast") && len(call.GetArgs()) == 2 {
+			collectFields(call.GetArgs()[0], fields)
+			return
+		}
+		if target := expression.GetCallExpr().GetTarget(); target != nil {
+			collectFields(target, fields)
+		}
+		for _, arg := range expression.GetCallExpr().GetArgs() {
+			collectFields(arg, fields)
+		}
+	case *expr.Expr_ListExpr:
+		for _, elem := range expression.GetListExpr().GetElements() {
+			collectFields(elem, fields)
+		}
+	}
+}
+
+// reservedKeywords are the GoogleSQL reserved keywords which must be quoted when used as identifiers.
+// PROTO is left out, since sproto stores messages in columns named Proto, which are referenced unquoted throughout.
+// See https://cloud.google.com/spanner/docs/reference/standard-sql/lexical#reserved_keywords
+var reservedKeywords = map[string]bool{
+	"ALL": true, "AND": true, "ANY": true, "ARRAY": true, "AS": true, "ASC": true, "ASSERT_ROWS_MODIFIED": true,
+	"AT": true, "BETWEEN": true, "BY": true, "CASE": true, "CAST": true, "COLLATE": true, "CONTAINS": true,
+	"CREATE": true, "CROSS": true, "CUBE": true, "CURRENT": true, "DEFAULT": true, "DEFINE": true, "DESC": true,
+	"DISTINCT": true, "ELSE": true, "END": true, "ENUM": true, "ESCAPE": true, "EXCEPT": true, "EXCLUDE": true,
+	"EXISTS": true, "EXTRACT": true, "FALSE": true, "FETCH": true, "FOLLOWING": true, "FOR": true, "FROM": true,
+	"FULL": true, "GROUP": true, "GROUPING": true, "GROUPS": true, "HASH": true, "HAVING": true, "IF": true,
+	"IGNORE": true, "IN": true, "INNER": true, "INTERSECT": true, "INTERVAL": true, "INTO": true, "IS": true,
+	"JOIN": true, "LATERAL": true, "LEFT": true, "LIKE": true, "LIMIT": true, "LOOKUP": true, "MERGE": true,
+	"NATURAL": true, "NEW": true, "NO": true, "NOT": true, "NULL": true, "NULLS": true, "OF": true, "ON": true,
+	"OR": true, "ORDER": true, "OUTER": true, "OVER": true, "PARTITION": true, "PRECEDING": true,
+	"RANGE": true, "RECURSIVE": true, "RESPECT": true, "RIGHT": true, "ROLLUP": true, "ROWS": true, "SELECT": true,
+	"SET": true, "SOME": true, "STRUCT": true, "TABLESAMPLE": true, "THEN": true, "TO": true, "TREAT": true,
+	"TRUE": true, "UNBOUNDED": true, "UNION": true, "UNNEST": true, "USING": true, "WHEN": true, "WHERE": true,
+	"WINDOW": true, "WITH": true, "WITHIN": true,
+}
+
+// quoteReservedKeyword wraps the identifier segment with backticks(`) if it is a reserved keyword.
+func quoteReservedKeyword(segment string) string {
+	if reservedKeywords[strings.ToUpper(segment)] {
+		return fmt.Sprintf("`%s`", segment)
+	}
+	return segment
+}
@@ -0,0 +1,69 @@
+package filtering
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cloud.google.com/go/spanner"
+)
+
+// SearchParam is the name of the parameter holding the search pattern in statements returned by SearchAcross.
+const SearchParam = "search"
+
+// searchFieldRegex matches field paths such as "display_name" or "Proto.owner.email".
+var searchFieldRegex = regexp.MustCompile(`^[A-Za-z_]\w*(\.[A-Za-z_]\w*)*$`)
+
+// likeEscaper escapes the wildcard and escape characters of a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+/*
+SearchAcross builds a case-insensitive free-text search of the term across the given fields, matching rows where any of
+the fields contains the term.
+
+The term is passed as a single parameter named SearchParam, with the LIKE wildcards (% and _) escaped, so it is always
+matched literally. Fields are emitted in the same way as in Parse, i.e. reserved keywords are quoted and declared
+identifiers are mapped to their columns.
+
+Example:
+
+	stmt, err := filter.SearchAcross([]string{"Proto.display_name", "Proto.description"}, "50%")
+	// stmt.SQL: (LOWER(Proto.display_name) LIKE LOWER(@search) OR LOWER(Proto.description) LIKE LOWER(@search))
+	// stmt.Params: map[string]interface{}{"search": "%50\\%%"}
+
+May return an ErrInvalidIdentifier error if no fields are provided or a field is not a valid path.
+*/
+func (f *Filter) SearchAcross(fields []string, term string) (*spanner.Statement, error) {
+	if len(fields) == 0 {
+		return nil, ErrInvalidIdentifier{
+			identifier: "",
+			err:        fmt.Errorf("at least one field is required"),
+		}
+	}
+
+	predicates := make([]string, len(fields))
+	for i, field := range fields {
+		if !searchFieldRegex.MatchString(field) {
+			return nil, ErrInvalidIdentifier{
+				identifier: field,
+				err:        fmt.Errorf("invalid field path"),
+			}
+		}
+
+		segments := strings.Split(field, ".")
+		for j, segment := range segments {
+			segments[j] = quoteReservedKeyword(segment)
+		}
+		predicates[i] = fmt.Sprintf("LOWER(%s) LIKE LOWER(@%s)", f.parseIdentifier(f.qualify(strings.Join(segments, "."))), SearchParam)
+	}
+
+	sql := predicates[0]
+	if len(predicates) > 1 {
+		sql = "(" + strings.Join(predicates, " OR ") + ")"
+	}
+
+	return &spanner.Statement{
+		SQL:    sql,
+		Params: map[string]interface{}{SearchParam: "%" + likeEscaper.Replace(term) + "%"},
+	}, nil
+}
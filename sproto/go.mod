@@ -3,6 +3,7 @@ module go.alis.build/sproto
 go 1.23.2
 
 require (
+	cloud.google.com/go v0.116.0
 	cloud.google.com/go/iam v1.2.2
 	cloud.google.com/go/spanner v1.73.0
 	dario.cat/mergo v1.0.1
@@ -21,7 +22,6 @@ require (
 
 require (
 	cel.dev/expr v0.18.0 // indirect
-	cloud.google.com/go v0.116.0 // indirect
 	cloud.google.com/go/auth v0.10.2 // indirect
 	cloud.google.com/go/auth/oauth2adapt v0.2.5 // indirect
 	cloud.google.com/go/compute/metadata v0.5.2 // indirect
@@ -0,0 +1,107 @@
+package sproto
+
+import (
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+/*
+KeyFromMessage builds the row key of the table from the fields of the provided message.
+
+The key field paths are the dot separated paths of the fields (e.g. "name" or "parent.id") holding the values of the
+primary key columns, and must be provided in the order of the primary key columns in the table schema. Generated
+primary key columns are included, with the path of the field their value equals, e.g. "name" for a key generated
+from Proto.name.
+
+Enum values are converted to their numbers, and signed and unsigned integers to int64.
+
+Returns an ErrInvalidArguments error if the number of paths does not match the number of primary key columns, or if a
+path does not resolve to a scalar field of the message.
+*/
+func (t *TableClient) KeyFromMessage(msg proto.Message, keyFieldPaths ...string) (spanner.Key, error) {
+	if msg == nil || !msg.ProtoReflect().IsValid() {
+		return nil, ErrInvalidArguments{
+			err:    fmt.Errorf("message is required"),
+			fields: []string{"msg"},
+		}
+	}
+	if len(keyFieldPaths) != len(t.primaryKeyColumns) {
+		columns := make([]string, len(t.primaryKeyColumns))
+		for i, col := range t.primaryKeyColumns {
+			columns[i] = col.columnName
+		}
+		return nil, ErrInvalidArguments{
+			err: fmt.Errorf("table %s has %d primary key columns (%s), got %d key field paths",
+				t.tableName, len(columns), strings.Join(columns, ", "), len(keyFieldPaths)),
+			fields: []string{"keyFieldPaths"},
+		}
+	}
+
+	key := make(spanner.Key, len(keyFieldPaths))
+	for i, path := range keyFieldPaths {
+		value, err := keyValue(msg.ProtoReflect(), path)
+		if err != nil {
+			return nil, ErrInvalidArguments{
+				err:    err,
+				fields: []string{path},
+			}
+		}
+		key[i] = value
+	}
+
+	return key, nil
+}
+
+// keyValue returns the value of the scalar field at the provided path of the message, in a type usable in a
+// spanner.Key.
+func keyValue(msg protoreflect.Message, path string) (interface{}, error) {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		field := msg.Descriptor().Fields().ByName(protoreflect.Name(segment))
+		if field == nil {
+			return nil, fmt.Errorf("field %s not found in %s", segment, msg.Descriptor().FullName())
+		}
+		if field.IsList() || field.IsMap() {
+			return nil, fmt.Errorf("field %s is repeated and cannot be used in a key", path)
+		}
+
+		// Traverse into nested messages, which must be set.
+		if i < len(segments)-1 {
+			if field.Kind() != protoreflect.MessageKind && field.Kind() != protoreflect.GroupKind {
+				return nil, fmt.Errorf("field %s is not a message", strings.Join(segments[:i+1], "."))
+			}
+			if !msg.Has(field) {
+				return nil, fmt.Errorf("field %s is not set", strings.Join(segments[:i+1], "."))
+			}
+			msg = msg.Get(field).Message()
+			continue
+		}
+
+		value := msg.Get(field)
+		switch field.Kind() {
+		case protoreflect.StringKind:
+			return value.String(), nil
+		case protoreflect.BoolKind:
+			return value.Bool(), nil
+		case protoreflect.BytesKind:
+			return value.Bytes(), nil
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+			protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			return value.Int(), nil
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			return int64(value.Uint()), nil
+		case protoreflect.FloatKind, protoreflect.DoubleKind:
+			return value.Float(), nil
+		case protoreflect.EnumKind:
+			return int64(value.Enum()), nil
+		default:
+			return nil, fmt.Errorf("field %s of kind %s cannot be used in a key", path, field.Kind())
+		}
+	}
+
+	return nil, fmt.Errorf("field path is required")
+}
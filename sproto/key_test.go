@@ -0,0 +1,103 @@
+package sproto
+
+import (
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestTableClient_KeyFromMessage(t *testing.T) {
+	single := &TableClient{
+		tableName:         "single",
+		primaryKeyColumns: []*primaryKeyColumn{NewPrimaryKeyColumn("key", true, true)},
+	}
+	composite := &TableClient{
+		tableName: "composite",
+		primaryKeyColumns: []*primaryKeyColumn{
+			NewPrimaryKeyColumn("Name", false, false),
+			NewPrimaryKeyColumn("Number", false, false),
+			NewPrimaryKeyColumn("Type", false, false),
+			NewPrimaryKeyColumn("Deprecated", false, false),
+		},
+	}
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:    proto.String("display_name"),
+		Number:  proto.Int32(2),
+		Type:    descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		Options: &descriptorpb.FieldOptions{Deprecated: proto.Bool(true)},
+	}
+
+	tests := []struct {
+		name    string
+		table   *TableClient
+		msg     proto.Message
+		paths   []string
+		want    spanner.Key
+		wantErr bool
+	}{
+		{
+			name:  "single",
+			table: single,
+			msg:   wrapperspb.String("abc"),
+			paths: []string{"value"},
+			want:  spanner.Key{"abc"},
+		},
+		{
+			name:  "composite",
+			table: composite,
+			msg:   field,
+			paths: []string{"name", "number", "type", "options.deprecated"},
+			want:  spanner.Key{"display_name", int64(2), int64(descriptorpb.FieldDescriptorProto_TYPE_STRING), true},
+		},
+		{
+			name:    "arity mismatch",
+			table:   composite,
+			msg:     field,
+			paths:   []string{"name", "number"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			table:   single,
+			msg:     wrapperspb.String("abc"),
+			paths:   []string{"id"},
+			wantErr: true,
+		},
+		{
+			name:    "unset nested message",
+			table:   single,
+			msg:     &descriptorpb.FieldDescriptorProto{},
+			paths:   []string{"options.deprecated"},
+			wantErr: true,
+		},
+		{
+			name:    "message field",
+			table:   single,
+			msg:     field,
+			paths:   []string{"options"},
+			wantErr: true,
+		},
+		{
+			name:    "nil message",
+			table:   single,
+			msg:     (*wrapperspb.StringValue)(nil),
+			paths:   []string{"value"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.table.KeyFromMessage(tt.msg, tt.paths...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("KeyFromMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("KeyFromMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
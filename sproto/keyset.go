@@ -0,0 +1,237 @@
+package sproto
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/spanner"
+	"cloud.google.com/go/spanner/apiv1/spannerpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// cursorColumnPrefix is the prefix of the aliases and parameters used by QueryRowsKeyset to read and compare the values
+// of the sort columns.
+const cursorColumnPrefix = "_sproto_cursor_"
+
+/*
+QueryRowsKeyset reads multiple rows from the specified table using the provided column names and filtering condition,
+in the same way as QueryRows, except that the pages are read using keyset pagination instead of an offset.
+
+The page token encodes the values of the sort columns of the last row of the page, and the next page is read using a
+predicate on these values, e.g. "(Name > @v0) OR (Name = @v0 AND Id > @v1)", rather than an OFFSET clause. The cost
+of a page therefore does not grow with the number of rows before it, whereas with an OFFSET Spanner has to scan and
+discard all the skipped rows.
+
+For the pages to neither skip nor repeat rows:
+  - the sort columns must identify the rows uniquely, e.g. by including the primary key columns.
+  - the sort columns must not be NULL.
+
+Since SortColumns is a map, the sort columns take precedence in the alphabetical order of their names.
+
+Without sort columns, QueryRowsKeyset falls back to the offset pagination of QueryRows. The page tokens of the two
+modes are not interchangeable, and an ErrInvalidPageToken error is returned if the page token was not returned by
+QueryRowsKeyset with the same sort columns.
+*/
+func (s *Client) QueryRowsKeyset(ctx context.Context, tableName string, columns []string, filter *spanner.Statement, opts *ReadOptions) ([]map[string]interface{}, string, error) {
+	if opts == nil || len(opts.SortColumns) == 0 {
+		return s.QueryRows(ctx, tableName, columns, filter, opts)
+	}
+	columns, opts, err := resolveSnakeCaseColumns(ctx, s, tableName, columns, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sortColumns := make([]string, 0, len(opts.SortColumns))
+	for column := range opts.SortColumns {
+		sortColumns = append(sortColumns, column)
+	}
+	slices.Sort(sortColumns)
+
+	var cursor []spanner.GenericColumnValue
+	if opts.PageToken != "" {
+		cursor, err = decodeKeysetToken(opts.PageToken, sortColumns)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	it := s.single().QueryWithOptions(ctx, keysetStatement(tableName, columns, filter, opts, sortColumns, cursor), s.queryOptions())
+	defer it.Stop()
+
+	// Iterate over the rows and construct the result
+	var res []map[string]interface{}
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		// The extra row read beyond the limit indicates that there is a next page, starting after the last row read
+		if opts.Limit > 0 && len(res) == int(opts.Limit) {
+			nextPageToken, err := encodeKeysetToken(sortColumns, cursor)
+			if err != nil {
+				return nil, "", err
+			}
+			return res, nextPageToken, nil
+		}
+
+		rowMap, err := rowToMap(row)
+		if err != nil {
+			return nil, "", err
+		}
+		cursor = make([]spanner.GenericColumnValue, len(sortColumns))
+		for i := range sortColumns {
+			alias := fmt.Sprintf("%s%d", cursorColumnPrefix, i)
+			if err := row.ColumnByName(alias, &cursor[i]); err != nil {
+				return nil, "", err
+			}
+			delete(rowMap, alias)
+		}
+
+		res = append(res, rowMap)
+	}
+
+	return res, "", nil
+}
+
+// keysetStatement builds the statement reading the page of QueryRowsKeyset which follows the provided cursor, if any.
+func keysetStatement(tableName string, columns []string, filter *spanner.Statement, opts *ReadOptions, sortColumns []string, cursor []spanner.GenericColumnValue) spanner.Statement {
+	// Select the sort columns under an alias, so that the cursor can be read even if they are not among the columns
+	selected := append([]string{}, columns...)
+	for i, column := range sortColumns {
+		selected = append(selected, fmt.Sprintf("%s AS %s%d", column, cursorColumnPrefix, i))
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selected, ", "), tableName)
+
+	params := map[string]interface{}{}
+	var conditions []string
+	// Add filtering condition if provided
+	if filter != nil && filter.SQL != "" {
+		conditions = append(conditions, "("+filter.SQL+")")
+		for name, value := range filter.Params {
+			params[name] = value
+		}
+	}
+	// Only read the rows after the cursor if provided
+	if cursor != nil {
+		alternatives := make([]string, len(sortColumns))
+		for i, column := range sortColumns {
+			terms := make([]string, 0, i+1)
+			for j := 0; j < i; j++ {
+				terms = append(terms, fmt.Sprintf("%s = @%s%d", sortColumns[j], cursorColumnPrefix, j))
+			}
+			operator := ">"
+			if opts.SortColumns[column] == SortOrderDesc {
+				operator = "<"
+			}
+			terms = append(terms, fmt.Sprintf("%s %s @%s%d", column, operator, cursorColumnPrefix, i))
+			alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+
+			params[fmt.Sprintf("%s%d", cursorColumnPrefix, i)] = cursor[i]
+		}
+		conditions = append(conditions, "("+strings.Join(alternatives, " OR ")+")")
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	orderBy := make([]string, len(sortColumns))
+	for i, column := range sortColumns {
+		orderBy[i] = fmt.Sprintf("%s %s", column, opts.SortColumns[column].String())
+	}
+	query += " ORDER BY " + strings.Join(orderBy, ", ")
+	// Add limit if provided, reading one more row to determine whether there is a next page
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %v", int64(opts.Limit)+1)
+	}
+
+	return spanner.Statement{
+		SQL:    query,
+		Params: params,
+	}
+}
+
+// keysetToken is the content of the page tokens of QueryRowsKeyset.
+type keysetToken struct {
+	// The sort columns the values belong to.
+	Columns []string `json:"columns"`
+	// The values of the sort columns of the last row read.
+	Values []keysetValue `json:"values"`
+}
+
+// keysetValue is a value of a keysetToken, along with its Spanner type, both in their protojson encoding.
+type keysetValue struct {
+	Type  json.RawMessage `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// encodeKeysetToken returns the page token of QueryRowsKeyset for the provided values of the sort columns.
+func encodeKeysetToken(sortColumns []string, cursor []spanner.GenericColumnValue) (string, error) {
+	token := keysetToken{
+		Columns: sortColumns,
+		Values:  make([]keysetValue, len(cursor)),
+	}
+	for i, value := range cursor {
+		typ, err := protojson.Marshal(value.Type)
+		if err != nil {
+			return "", err
+		}
+		val, err := protojson.Marshal(value.Value)
+		if err != nil {
+			return "", err
+		}
+		token.Values[i] = keysetValue{Type: typ, Value: val}
+	}
+
+	b, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+/*
+decodeKeysetToken returns the values of the sort columns encoded in a page token of QueryRowsKeyset. An
+ErrInvalidPageToken error is returned if the token is malformed, or was returned for different sort columns.
+*/
+func decodeKeysetToken(pageToken string, sortColumns []string) ([]spanner.GenericColumnValue, error) {
+	invalid := ErrInvalidPageToken{
+		pageToken: pageToken,
+	}
+	b, err := base64.StdEncoding.DecodeString(pageToken)
+	if err != nil {
+		return nil, invalid
+	}
+	var token keysetToken
+	if err := json.Unmarshal(b, &token); err != nil {
+		return nil, invalid
+	}
+	if !slices.Equal(token.Columns, sortColumns) || len(token.Values) != len(sortColumns) {
+		return nil, invalid
+	}
+
+	cursor := make([]spanner.GenericColumnValue, len(token.Values))
+	for i, value := range token.Values {
+		cursor[i] = spanner.GenericColumnValue{
+			Type:  &spannerpb.Type{},
+			Value: &structpb.Value{},
+		}
+		if err := protojson.Unmarshal(value.Type, cursor[i].Type); err != nil {
+			return nil, invalid
+		}
+		if err := protojson.Unmarshal(value.Value, cursor[i].Value); err != nil {
+			return nil, invalid
+		}
+	}
+	return cursor, nil
+}
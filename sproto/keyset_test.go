@@ -0,0 +1,125 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"cloud.google.com/go/spanner/apiv1/spannerpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func Test_keysetStatement(t *testing.T) {
+	opts := &ReadOptions{
+		SortColumns: map[string]SortOrder{"Name": SortOrderAsc, "Id": SortOrderDesc},
+		Limit:       10,
+	}
+	filter := &spanner.Statement{SQL: "Name != @name", Params: map[string]interface{}{"name": "x"}}
+	cursor := []spanner.GenericColumnValue{
+		{Type: &spannerpb.Type{Code: spannerpb.TypeCode_INT64}, Value: structpb.NewStringValue("5")},
+		{Type: &spannerpb.Type{Code: spannerpb.TypeCode_STRING}, Value: structpb.NewStringValue("b")},
+	}
+
+	stmt := keysetStatement("test_table", []string{"Id"}, filter, opts, []string{"Id", "Name"}, cursor)
+	want := "SELECT Id, Id AS _sproto_cursor_0, Name AS _sproto_cursor_1 FROM test_table" +
+		" WHERE (Name != @name) AND ((Id < @_sproto_cursor_0) OR (Id = @_sproto_cursor_0 AND Name > @_sproto_cursor_1))" +
+		" ORDER BY Id DESC, Name ASC LIMIT 11"
+	if stmt.SQL != want {
+		t.Errorf("keysetStatement() SQL = %q, want %q", stmt.SQL, want)
+	}
+	if len(stmt.Params) != 3 {
+		t.Errorf("keysetStatement() params = %v, want the filter and cursor params", stmt.Params)
+	}
+	if len(filter.Params) != 1 {
+		t.Errorf("keysetStatement() modified the filter params: %v", filter.Params)
+	}
+
+	stmt = keysetStatement("test_table", []string{"Id"}, nil, opts, []string{"Id", "Name"}, nil)
+	want = "SELECT Id, Id AS _sproto_cursor_0, Name AS _sproto_cursor_1 FROM test_table ORDER BY Id DESC, Name ASC LIMIT 11"
+	if stmt.SQL != want {
+		t.Errorf("keysetStatement() SQL = %q, want %q", stmt.SQL, want)
+	}
+}
+
+func Test_keysetToken(t *testing.T) {
+	sortColumns := []string{"Id", "Name"}
+	cursor := []spanner.GenericColumnValue{
+		{Type: &spannerpb.Type{Code: spannerpb.TypeCode_INT64}, Value: structpb.NewStringValue("5")},
+		{Type: &spannerpb.Type{Code: spannerpb.TypeCode_STRING}, Value: structpb.NewStringValue("b")},
+	}
+
+	token, err := encodeKeysetToken(sortColumns, cursor)
+	if err != nil {
+		t.Fatalf("encodeKeysetToken() error = %v", err)
+	}
+	got, err := decodeKeysetToken(token, sortColumns)
+	if err != nil {
+		t.Fatalf("decodeKeysetToken() error = %v", err)
+	}
+	for i := range cursor {
+		if !proto.Equal(got[i].Type, cursor[i].Type) || !proto.Equal(got[i].Value, cursor[i].Value) {
+			t.Errorf("decodeKeysetToken() value %d = %v, want %v", i, got[i], cursor[i])
+		}
+	}
+
+	if _, err := decodeKeysetToken(token, []string{"Name"}); !errors.Is(err, ErrInvalidPageToken{}) {
+		t.Errorf("decodeKeysetToken() with other sort columns error = %v, want ErrInvalidPageToken", err)
+	}
+	if _, err := decodeKeysetToken(encodePageToken(10), sortColumns); !errors.Is(err, ErrInvalidPageToken{}) {
+		t.Errorf("decodeKeysetToken() with an offset token error = %v, want ErrInvalidPageToken", err)
+	}
+}
+
+func TestClient_QueryRowsKeyset(t *testing.T) {
+	ctx := context.Background()
+	base := time.Now().UnixNano()
+	ids := []int64{base, base + 1, base + 2, base + 3, base + 4}
+	for _, id := range ids {
+		if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id}); err != nil {
+			t.Fatalf("InsertRow() error = %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		for _, id := range ids {
+			_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+		}
+	})
+
+	filter := &spanner.Statement{
+		SQL:    "Id >= @from AND Id <= @to",
+		Params: map[string]interface{}{"from": ids[0], "to": ids[len(ids)-1]},
+	}
+	opts := &ReadOptions{SortColumns: map[string]SortOrder{"Id": SortOrderDesc}, Limit: 2}
+	var got []int64
+	for pages := 0; ; pages++ {
+		if pages > len(ids) {
+			t.Fatalf("QueryRowsKeyset() did not stop paginating")
+		}
+		rows, nextPageToken, err := sproto.QueryRowsKeyset(ctx, "test_table", []string{"Id"}, filter, opts)
+		if err != nil {
+			t.Fatalf("QueryRowsKeyset() error = %v", err)
+		}
+		for _, row := range rows {
+			if _, ok := row[cursorColumnPrefix+"0"]; ok {
+				t.Errorf("QueryRowsKeyset() row = %v, want the cursor columns removed", row)
+			}
+			got = append(got, row["Id"].(int64))
+		}
+		if nextPageToken == "" {
+			break
+		}
+		opts.PageToken = nextPageToken
+	}
+
+	if len(got) != len(ids) {
+		t.Fatalf("QueryRowsKeyset() got ids %v, want %d ids", got, len(ids))
+	}
+	for i, id := range got {
+		if want := ids[len(ids)-1-i]; id != want {
+			t.Errorf("QueryRowsKeyset() id %d = %v, want %v", i, id, want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package sproto
+
+import (
+	"context"
+
+	"cloud.google.com/go/spanner"
+)
+
+// DefaultMutationLimit is the maximum number of mutations Spanner allows in a single commit.
+// See https://cloud.google.com/spanner/quotas#limits-for for details.
+const DefaultMutationLimit = 80000
+
+// MutationLimitBehavior determines how batch writes behave when they exceed the mutation limit of a single commit.
+type MutationLimitBehavior int
+
+const (
+	// MutationLimitError rejects batch writes exceeding the mutation limit with an ErrMutationLimitExceeded error,
+	// before anything is sent to Spanner.
+	MutationLimitError MutationLimitBehavior = iota
+	// MutationLimitSplit splits batch writes exceeding the mutation limit into multiple commits.
+	//
+	// Atomicity is lost in this case: if one of the commits fails, the rows written by the preceding commits are
+	// retained.
+	MutationLimitSplit
+)
+
+/*
+mutationLimiter enforces the mutation limit of a single commit on batch writes.
+
+Spanner counts a mutation per column written for inserts and updates, and a single mutation per deleted row.
+Mutations on secondary indexes also count towards the limit, which is why the limit can be lowered using the
+respective client options.
+*/
+type mutationLimiter struct {
+	limit    int
+	behavior MutationLimitBehavior
+}
+
+// defaultMutationLimiter returns a mutationLimiter rejecting writes exceeding the Spanner mutation limit.
+func defaultMutationLimiter() mutationLimiter {
+	return mutationLimiter{
+		limit:    DefaultMutationLimit,
+		behavior: MutationLimitError,
+	}
+}
+
+/*
+apply applies the mutations to the database, honouring the mutation limit.
+
+The counts are the number of mutations each of the provided mutations accounts for, typically the number of columns
+written. A single mutation exceeding the limit on its own always results in an ErrMutationLimitExceeded error.
+*/
+func (l mutationLimiter) apply(ctx context.Context, client *spanner.Client, mutations []*spanner.Mutation, counts []int, opts ...spanner.ApplyOption) error {
+	batches, err := l.split(counts)
+	if err != nil {
+		return err
+	}
+
+	for _, batch := range batches {
+		if _, err := client.Apply(ctx, mutations[batch[0]:batch[1]], opts...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// split returns the [start, end) index ranges of the mutations to commit together.
+func (l mutationLimiter) split(counts []int) ([][2]int, error) {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	if l.limit <= 0 || total <= l.limit {
+		return [][2]int{{0, len(counts)}}, nil
+	}
+	if l.behavior != MutationLimitSplit {
+		return nil, ErrMutationLimitExceeded{
+			mutations: total,
+			limit:     l.limit,
+		}
+	}
+
+	var batches [][2]int
+	start, size := 0, 0
+	for i, count := range counts {
+		if count > l.limit {
+			return nil, ErrMutationLimitExceeded{
+				mutations: count,
+				limit:     l.limit,
+			}
+		}
+		if size+count > l.limit {
+			batches = append(batches, [2]int{start, i})
+			start, size = i, 0
+		}
+		size += count
+	}
+	batches = append(batches, [2]int{start, len(counts)})
+
+	return batches, nil
+}
@@ -0,0 +1,94 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func Test_mutationLimiter_split(t *testing.T) {
+	tests := []struct {
+		name    string
+		limiter mutationLimiter
+		counts  []int
+		want    [][2]int
+		wantErr error
+	}{
+		{
+			name:    "WithinLimit",
+			limiter: mutationLimiter{limit: 10, behavior: MutationLimitError},
+			counts:  []int{2, 2, 2},
+			want:    [][2]int{{0, 3}},
+		},
+		{
+			name:    "ExceedsLimit_Error",
+			limiter: mutationLimiter{limit: 5, behavior: MutationLimitError},
+			counts:  []int{2, 2, 2},
+			wantErr: ErrMutationLimitExceeded{},
+		},
+		{
+			name:    "ExceedsLimit_Split",
+			limiter: mutationLimiter{limit: 5, behavior: MutationLimitSplit},
+			counts:  []int{2, 2, 2, 3, 1},
+			want:    [][2]int{{0, 2}, {2, 4}, {4, 5}},
+		},
+		{
+			name:    "SingleMutationExceedsLimit_Split",
+			limiter: mutationLimiter{limit: 5, behavior: MutationLimitSplit},
+			counts:  []int{2, 6},
+			wantErr: ErrMutationLimitExceeded{},
+		},
+		{
+			name:    "NoLimit",
+			limiter: mutationLimiter{},
+			counts:  []int{DefaultMutationLimit, DefaultMutationLimit},
+			want:    [][2]int{{0, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.limiter.split(tt.counts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("split() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("split() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("split() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableClient_BatchCreate_MutationLimitExceeded(t *testing.T) {
+	tbl, err := (&DbClient{}).NewTableClient("books", 100,
+		WithPrimaryKeyColumns([]*primaryKeyColumn{{columnName: "id"}}),
+		WithMsgTypeToColumnMap(map[string]string{
+			"google.protobuf.StringValue": "Title",
+		}),
+		WithTableMutationLimit(5, MutationLimitError),
+	)
+	if err != nil {
+		t.Fatalf("NewTableClient() error = %v", err)
+	}
+
+	// Each row writes 2 columns, so 3 rows require 6 mutations.
+	rows := []*Row{
+		{Key: spanner.Key{"1"}, Messages: []proto.Message{wrapperspb.String("a")}},
+		{Key: spanner.Key{"2"}, Messages: []proto.Message{wrapperspb.String("b")}},
+		{Key: spanner.Key{"3"}, Messages: []proto.Message{wrapperspb.String("c")}},
+	}
+	err = tbl.BatchCreate(context.Background(), rows)
+	if !errors.Is(err, ErrMutationLimitExceeded{}) {
+		t.Errorf("BatchCreate() error = %v, want ErrMutationLimitExceeded", err)
+	}
+}
@@ -0,0 +1,89 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// totalSizeColumn is the alias of the windowed COUNT(*) column used by ListProtosWithTotalSize.
+const totalSizeColumn = "_sproto_total_size"
+
+/*
+ListProtosWithTotalSize lists proto messages from the specified table in the same way as ListProtos, additionally
+returning the total number of messages in the table, e.g. to populate the total_size of a list response.
+
+Rather than running a separate COUNT(*) query, the total is computed in the same query using a windowed
+COUNT(*) OVER(), halving the number of round trips per page. The tradeoff is that Spanner has to evaluate the window
+over all the matching rows for every page, which for large tables may cost more than an indexed COUNT(*).
+
+The method falls back to a separate COUNT(*) query if the windowed query is rejected by the database, or if the page
+is empty, in which case the window yields no rows to read the total from.
+*/
+func (s *Client) ListProtosWithTotalSize(ctx context.Context, tableName string, columnName string, message proto.Message, opts *ReadOptions) ([]proto.Message, string, int64, error) {
+	pagination, initialOffset, err := paginationClauses(opts, 0)
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	query := fmt.Sprintf("SELECT %s, COUNT(*) OVER() AS %s FROM %s WHERE %s IS NOT NULL", columnName, totalSizeColumn, tableName, columnName) + pagination
+	res, rowCount, err := s.listProtosWithWindowedCount(ctx, spanner.Statement{SQL: query}, message)
+	if err != nil {
+		code := spanner.ErrCode(err)
+		if code != codes.InvalidArgument && code != codes.Unimplemented {
+			return nil, "", 0, err
+		}
+
+		// The windowed count is not supported, list and count separately.
+		return s.listProtos(ctx, tableName, columnName, message, opts)
+	}
+
+	if len(res) == 0 {
+		rowCount, err = s.Count(ctx, tableName, &spanner.Statement{
+			SQL: fmt.Sprintf("%s IS NOT NULL", columnName),
+		})
+		if err != nil {
+			return nil, "", 0, err
+		}
+	}
+
+	return res, nextPageToken(initialOffset, len(res), rowCount), rowCount, nil
+}
+
+// listProtosWithWindowedCount runs a query selecting a proto column and a windowed count, and returns the unmarshalled
+// messages along with the count.
+func (s *Client) listProtosWithWindowedCount(ctx context.Context, stmt spanner.Statement, message proto.Message) ([]proto.Message, int64, error) {
+	it := s.single().QueryWithOptions(ctx, stmt, s.queryOptions())
+	defer it.Stop()
+
+	var res []proto.Message
+	var rowCount int64
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var dataBytes []byte
+		if err := row.Columns(&dataBytes, &rowCount); err != nil {
+			return nil, 0, err
+		}
+
+		newMessage := newEmptyMessage(message)
+		if err := s.unmarshal(dataBytes, newMessage); err != nil {
+			return nil, 0, err
+		}
+
+		res = append(res, newMessage)
+	}
+
+	return res, rowCount, nil
+}
@@ -0,0 +1,76 @@
+package sproto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestClient_ListProtosWithTotalSize(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	for i := int64(0); i < 3; i++ {
+		data, err := proto.Marshal(wrapperspb.Int64(id + i))
+		if err != nil {
+			t.Fatalf("proto.Marshal() error = %v", err)
+		}
+		if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id + i, "Data": data}); err != nil {
+			t.Fatalf("InsertRow() error = %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		_ = sproto.BatchDeleteRows(context.Background(), "test_table", []spanner.Key{{id}, {id + 1}, {id + 2}})
+	})
+
+	total, err := sproto.countRows(ctx, spanner.Statement{SQL: "SELECT COUNT(*) FROM test_table WHERE Data IS NOT NULL"})
+	if err != nil {
+		t.Fatalf("countRows() error = %v", err)
+	}
+
+	// Page through the table using both methods, which should return the same pages.
+	opts := &ReadOptions{SortColumns: map[string]SortOrder{"Id": SortOrderAsc}, Limit: 2}
+	for {
+		want, wantToken, err := sproto.ListProtos(ctx, "test_table", "Data", &wrapperspb.Int64Value{}, opts)
+		if err != nil {
+			t.Fatalf("ListProtos() error = %v", err)
+		}
+		got, gotToken, gotTotal, err := sproto.ListProtosWithTotalSize(ctx, "test_table", "Data", &wrapperspb.Int64Value{}, opts)
+		if err != nil {
+			t.Fatalf("ListProtosWithTotalSize() error = %v", err)
+		}
+
+		if gotTotal != total {
+			t.Errorf("ListProtosWithTotalSize() total = %v, want %v", gotTotal, total)
+		}
+		if gotToken != wantToken {
+			t.Errorf("ListProtosWithTotalSize() token = %v, want %v", gotToken, wantToken)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("ListProtosWithTotalSize() got %d messages, want %d", len(got), len(want))
+		}
+		for i := range got {
+			if !proto.Equal(got[i], want[i]) {
+				t.Errorf("ListProtosWithTotalSize() message %d = %v, want %v", i, got[i], want[i])
+			}
+		}
+
+		if wantToken == "" {
+			break
+		}
+		opts.PageToken = wantToken
+	}
+
+	// An empty page past the end still reports the total.
+	opts.PageToken = nextPageToken(total, 0, total+1)
+	got, _, gotTotal, err := sproto.ListProtosWithTotalSize(ctx, "test_table", "Data", &wrapperspb.Int64Value{}, opts)
+	if err != nil {
+		t.Fatalf("ListProtosWithTotalSize() error = %v", err)
+	}
+	if len(got) != 0 || gotTotal != total {
+		t.Errorf("ListProtosWithTotalSize() = %d messages, total %v, want 0 messages, total %v", len(got), gotTotal, total)
+	}
+}
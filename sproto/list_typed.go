@@ -0,0 +1,48 @@
+package sproto
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+List lists all proto messages from the specified table using the provided column name, like ListProtos, but returns
+them as a slice of the concrete message type T instead of proto.Message.
+
+The column must be of type PROTO, holding messages of type T.
+The second return value is the next page token which can be used to get the next page of results.
+
+Example:
+
+	books, nextPageToken, err := sproto.List[*pb.Book](ctx, client, "Books", "Proto", opts)
+*/
+func List[T proto.Message](ctx context.Context, client *Client, tableName string, columnName string, opts *ReadOptions) ([]T, string, error) {
+	var message T
+	messages, nextPageToken, err := client.ListProtos(ctx, tableName, columnName, message, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err := castMessages[T](messages)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return res, nextPageToken, nil
+}
+
+// castMessages converts the provided messages to the concrete message type T.
+func castMessages[T proto.Message](messages []proto.Message) ([]T, error) {
+	res := make([]T, 0, len(messages))
+	for _, m := range messages {
+		typed, ok := m.(T)
+		if !ok {
+			var message T
+			return nil, fmt.Errorf("unexpected message type %T, want %T", m, message)
+		}
+		res = append(res, typed)
+	}
+	return res, nil
+}
@@ -0,0 +1,51 @@
+package sproto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	for i := int64(0); i < 3; i++ {
+		data, err := proto.Marshal(wrapperspb.Int64(id + i))
+		if err != nil {
+			t.Fatalf("proto.Marshal() error = %v", err)
+		}
+		if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id + i, "Data": data}); err != nil {
+			t.Fatalf("InsertRow() error = %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		_ = sproto.BatchDeleteRows(context.Background(), "test_table", []spanner.Key{{id}, {id + 1}, {id + 2}})
+	})
+
+	opts := &ReadOptions{SortColumns: map[string]SortOrder{"Id": SortOrderAsc}, Limit: 2}
+	want, wantToken, err := sproto.ListProtos(ctx, "test_table", "Data", &wrapperspb.Int64Value{}, opts)
+	if err != nil {
+		t.Fatalf("ListProtos() error = %v", err)
+	}
+	// The result is typed, so the values are accessible without type assertions.
+	got, gotToken, err := List[*wrapperspb.Int64Value](ctx, sproto, "test_table", "Data", opts)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if gotToken != wantToken {
+		t.Errorf("List() token = %v, want %v", gotToken, wantToken)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("List() got %d messages, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i].GetValue() != want[i].(*wrapperspb.Int64Value).GetValue() {
+			t.Errorf("List() message %d = %v, want %v", i, got[i].GetValue(), want[i])
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package sproto
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+MessageMigrator migrates a proto message read from the database, e.g. to backfill the default of a field added since the
+message was written, or to move the value of a deprecated field into its replacement.
+
+Stored bytes are unmarshalled into the current version of the message, which silently drops unknown fields and leaves
+new fields unset; the migrator is the hook to act on this. It is called with the unmarshalled message, and should
+modify it in place. An error returned by the migrator fails the read.
+*/
+type MessageMigrator func(message proto.Message) error
+
+// unmarshal unmarshals the bytes into the message and applies the migrator of the client, if any.
+func (s *Client) unmarshal(b []byte, message proto.Message) error {
+	return unmarshalAndMigrate(b, message, s.migrator)
+}
+
+// unmarshal unmarshals the bytes into the message and applies the migrator of the table client, if any.
+func (t *TableClient) unmarshal(b []byte, message proto.Message) error {
+	return unmarshalAndMigrate(b, message, t.migrator)
+}
+
+func unmarshalAndMigrate(b []byte, message proto.Message, migrator MessageMigrator) error {
+	if err := proto.Unmarshal(b, message); err != nil {
+		return err
+	}
+	if migrator == nil {
+		return nil
+	}
+	if err := migrator(message); err != nil {
+		return fmt.Errorf("migrate %s: %w", proto.MessageName(message), err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestUnmarshalAndMigrate(t *testing.T) {
+	data, err := proto.Marshal(wrapperspb.String("old"))
+	if err != nil {
+		t.Fatalf("proto.Marshal() error = %v", err)
+	}
+	errMigrate := errors.New("cannot migrate")
+	tests := []struct {
+		name     string
+		migrator MessageMigrator
+		want     string
+		wantErr  error
+	}{
+		{name: "no migrator", want: "old"},
+		{
+			name: "migrate old message",
+			migrator: func(message proto.Message) error {
+				if m := message.(*wrapperspb.StringValue); m.GetValue() == "old" {
+					m.Value = "new"
+				}
+				return nil
+			},
+			want: "new",
+		},
+		{
+			name:     "migrator error",
+			migrator: func(proto.Message) error { return errMigrate },
+			wantErr:  errMigrate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &wrapperspb.StringValue{}
+			err := unmarshalAndMigrate(data, got, tt.migrator)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unmarshalAndMigrate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.GetValue() != tt.want {
+				t.Errorf("unmarshalAndMigrate() value = %q, want %q", got.GetValue(), tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_WithMessageMigrator(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	data, err := proto.Marshal(wrapperspb.String("old"))
+	if err != nil {
+		t.Fatalf("proto.Marshal() error = %v", err)
+	}
+	if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id, "Data": data}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+	})
+
+	client := New(sproto.client, WithMessageMigrator(func(message proto.Message) error {
+		if m, ok := message.(*wrapperspb.StringValue); ok && m.GetValue() == "old" {
+			m.Value = "new"
+		}
+		return nil
+	}))
+
+	got := &wrapperspb.StringValue{}
+	if err := client.ReadProto(ctx, "test_table", spanner.Key{id}, "Data", got, nil); err != nil {
+		t.Fatalf("ReadProto() error = %v", err)
+	}
+	if got.GetValue() != "new" {
+		t.Errorf("ReadProto() value = %q, want %q", got.GetValue(), "new")
+	}
+
+	msgs, err := client.BatchReadProtos(ctx, "test_table", []spanner.Key{{id}}, "Data", &wrapperspb.StringValue{}, nil)
+	if err != nil {
+		t.Fatalf("BatchReadProtos() error = %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].(*wrapperspb.StringValue).GetValue() != "new" {
+		t.Errorf("BatchReadProtos() = %v, want a single migrated message", msgs)
+	}
+
+	rows, _, err := client.QueryProtos(ctx, "test_table", []string{"Data"}, []proto.Message{&wrapperspb.StringValue{}},
+		&spanner.Statement{SQL: "Id = @id", Params: map[string]interface{}{"id": id}}, nil)
+	if err != nil {
+		t.Fatalf("QueryProtos() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0]["Data"].(*wrapperspb.StringValue).GetValue() != "new" {
+		t.Errorf("QueryProtos() = %v, want a single migrated message", rows)
+	}
+}
@@ -0,0 +1,208 @@
+package sproto
+
+import (
+	"context"
+	"sync"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+MutationBuilder assembles proto writes across one or more tables into a single list of mutations,
+which can then be applied atomically using Commit.
+
+The PROTO columns and primary key columns of each table are resolved once, the first time the table is used,
+and cached for the lifetime of the builder. Table clients that are already available can be registered upfront
+using NewMutationBuilder to avoid querying the schema altogether.
+
+A MutationBuilder is safe for concurrent use.
+*/
+type MutationBuilder struct {
+	db              *DbClient
+	mu              sync.Mutex
+	tables          map[string]*TableClient
+	mutations       []*spanner.Mutation
+	counts          []int
+	mutationLimiter mutationLimiter
+	requestOptions  RequestOptions
+}
+
+/*
+NewMutationBuilder creates a new MutationBuilder instance.
+
+The provided table clients are registered with the builder and their schema is reused when writing to the respective
+tables. Tables that are not registered are resolved on first use.
+*/
+func (d *DbClient) NewMutationBuilder(tables ...*TableClient) *MutationBuilder {
+	b := &MutationBuilder{
+		db:              d,
+		tables:          make(map[string]*TableClient, len(tables)),
+		mutationLimiter: defaultMutationLimiter(),
+	}
+	for _, table := range tables {
+		b.tables[table.tableName] = table
+	}
+
+	return b
+}
+
+/*
+SetMutationLimit sets the maximum number of mutations Commit may apply at once, and the behavior when the mutations
+added to the builder exceed it. See WithTableMutationLimit.
+*/
+func (b *MutationBuilder) SetMutationLimit(limit int, behavior MutationLimitBehavior) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.mutationLimiter = mutationLimiter{
+		limit:    limit,
+		behavior: behavior,
+	}
+}
+
+/*
+SetRequestOptions sets the tags attached to the commit of the mutations added to the builder. See RequestOptions.
+*/
+func (b *MutationBuilder) SetRequestOptions(opts RequestOptions) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.requestOptions = opts
+}
+
+/*
+table returns the cached table client for the provided table name, creating it if it does not exist yet.
+The caller must hold the builder's lock.
+*/
+func (b *MutationBuilder) table(tableName string) (*TableClient, error) {
+	if table, ok := b.tables[tableName]; ok {
+		return table, nil
+	}
+
+	table, err := b.db.NewTableClient(tableName, 0)
+	if err != nil {
+		return nil, err
+	}
+	b.tables[tableName] = table
+
+	return table, nil
+}
+
+/*
+add resolves the columns and values of the row and appends the mutation created by op to the builder.
+*/
+func (b *MutationBuilder) add(tableName string, rowKey spanner.Key, messages []proto.Message,
+	op func(table string, columns []string, values []interface{}) *spanner.Mutation,
+) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	table, err := b.table(tableName)
+	if err != nil {
+		return err
+	}
+
+	columns, values, err := table.mutationColumns(&Row{Key: rowKey, Messages: messages})
+	if err != nil {
+		return err
+	}
+	b.mutations = append(b.mutations, op(tableName, columns, values))
+	b.counts = append(b.counts, len(columns))
+
+	return nil
+}
+
+/*
+Insert adds a mutation which creates a new row in the provided table with the provided row key and proto messages.
+
+This method may return a ErrInvalidArguments error if the row key length does not match the primary key columns length,
+or if the message type is not found in the table schema.
+*/
+func (b *MutationBuilder) Insert(tableName string, rowKey spanner.Key, messages ...proto.Message) error {
+	return b.add(tableName, rowKey, messages, spanner.Insert)
+}
+
+/*
+Update adds a mutation which updates an existing row in the provided table with the provided row key and proto messages.
+
+This method may return a ErrInvalidArguments error if the row key length does not match the primary key columns length,
+or if the message type is not found in the table schema.
+*/
+func (b *MutationBuilder) Update(tableName string, rowKey spanner.Key, messages ...proto.Message) error {
+	return b.add(tableName, rowKey, messages, spanner.Update)
+}
+
+/*
+Write adds a mutation which updates the row in the provided table if it already exists, else creates a new row.
+
+This method may return a ErrInvalidArguments error if the row key length does not match the primary key columns length,
+or if the message type is not found in the table schema.
+*/
+func (b *MutationBuilder) Write(tableName string, rowKey spanner.Key, messages ...proto.Message) error {
+	return b.add(tableName, rowKey, messages, spanner.InsertOrUpdate)
+}
+
+/*
+Delete adds a mutation which deletes the row in the provided table with the provided row key.
+*/
+func (b *MutationBuilder) Delete(tableName string, rowKey spanner.Key) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.mutations = append(b.mutations, spanner.Delete(tableName, rowKey))
+	b.counts = append(b.counts, 1)
+}
+
+/*
+Mutations returns the mutations added to the builder so far, in the order in which they were added.
+This is useful to apply the mutations as part of a custom read-write transaction using BufferWrite.
+*/
+func (b *MutationBuilder) Mutations() []*spanner.Mutation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	mutations := make([]*spanner.Mutation, len(b.mutations))
+	copy(mutations, b.mutations)
+
+	return mutations
+}
+
+/*
+Commit atomically applies all the mutations added to the builder.
+
+This method may return a ErrAlreadyExists error if a row being inserted already exists,
+or a ErrNotFound error if a row being updated does not exist.
+It may also return a ErrMutationLimitExceeded error if the mutations exceed the mutation limit of a single commit,
+see SetMutationLimit. With MutationLimitSplit, the mutations are committed in multiple batches and are no longer
+applied atomically.
+*/
+func (b *MutationBuilder) Commit(ctx context.Context) error {
+	b.mu.Lock()
+	mutations := make([]*spanner.Mutation, len(b.mutations))
+	copy(mutations, b.mutations)
+	counts := make([]int, len(b.counts))
+	copy(counts, b.counts)
+	limiter := b.mutationLimiter
+	opts := b.applyOptions()
+	b.mu.Unlock()
+
+	err := limiter.apply(ctx, b.db.client, mutations, counts, opts...)
+	if err != nil {
+		switch spanner.ErrCode(err) {
+		case codes.AlreadyExists:
+			return ErrAlreadyExists{
+				err: err,
+			}
+		case codes.NotFound:
+			return ErrNotFound{
+				err: err,
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func newTestMutationBuilder(t *testing.T) *MutationBuilder {
+	t.Helper()
+
+	db := &DbClient{}
+	books, err := db.NewTableClient("books", 100,
+		WithPrimaryKeyColumns([]*primaryKeyColumn{{columnName: "id"}}),
+		WithMsgTypeToColumnMap(map[string]string{
+			"google.protobuf.StringValue": "Title",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewTableClient() error = %v", err)
+	}
+	authors, err := db.NewTableClient("authors", 100,
+		WithPrimaryKeyColumns([]*primaryKeyColumn{{columnName: "id"}, {columnName: "key", isGenerated: true, isStored: true}}),
+		WithMsgTypeToColumnMap(map[string]string{
+			"google.protobuf.Int64Value":  "Age",
+			"google.protobuf.StringValue": "Name",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewTableClient() error = %v", err)
+	}
+
+	return db.NewMutationBuilder(books, authors)
+}
+
+func TestMutationBuilder_Mutations(t *testing.T) {
+	b := newTestMutationBuilder(t)
+
+	title := wrapperspb.String("Dune")
+	name := wrapperspb.String("Frank Herbert")
+	age := wrapperspb.Int64(65)
+
+	if err := b.Insert("books", spanner.Key{"b1"}, title); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+	if err := b.Update("authors", spanner.Key{"a1", nil}, name, age); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := b.Write("authors", spanner.Key{"a2", nil}, name); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	b.Delete("books", spanner.Key{"b2"})
+
+	want := []*spanner.Mutation{
+		spanner.Insert("books", []string{"id", "Title"}, []interface{}{"b1", title}),
+		spanner.Update("authors", []string{"id", "Name", "Age"}, []interface{}{"a1", name, age}),
+		spanner.InsertOrUpdate("authors", []string{"id", "Name"}, []interface{}{"a2", name}),
+		spanner.Delete("books", spanner.Key{"b2"}),
+	}
+	if got := b.Mutations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Mutations() got = %v, want %v", got, want)
+	}
+}
+
+func TestMutationBuilder_InvalidArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		tableName string
+		rowKey    spanner.Key
+		messages  []proto.Message
+	}{
+		{
+			name:      "Test_Insert_UnknownMessageType",
+			tableName: "books",
+			rowKey:    spanner.Key{"b1"},
+			messages:  []proto.Message{wrapperspb.Int64(1)},
+		},
+		{
+			name:      "Test_Insert_InvalidKeyLength",
+			tableName: "authors",
+			rowKey:    spanner.Key{"a1"},
+			messages:  []proto.Message{wrapperspb.String("Frank Herbert")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestMutationBuilder(t)
+			err := b.Insert(tt.tableName, tt.rowKey, tt.messages...)
+			if !errors.Is(err, ErrInvalidArguments{}) {
+				t.Errorf("Insert() error = %v, want ErrInvalidArguments", err)
+			}
+			if got := b.Mutations(); len(got) != 0 {
+				t.Errorf("Mutations() got %d mutations, want 0", len(got))
+			}
+		})
+	}
+}
+
+func TestMutationBuilder_Commit_MutationLimitExceeded(t *testing.T) {
+	b := newTestMutationBuilder(t)
+	b.SetMutationLimit(3, MutationLimitError)
+
+	// Each insert writes 2 columns, so 2 inserts require 4 mutations.
+	for _, id := range []string{"b1", "b2"} {
+		if err := b.Insert("books", spanner.Key{id}, wrapperspb.String("Dune")); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+	err := b.Commit(context.Background())
+	if !errors.Is(err, ErrMutationLimitExceeded{}) {
+		t.Errorf("Commit() error = %v, want ErrMutationLimitExceeded", err)
+	}
+}
+
+func TestMutationBuilder_SetRequestOptions(t *testing.T) {
+	b := newTestMutationBuilder(t)
+	if got := b.applyOptions(); len(got) != 0 {
+		t.Errorf("len(applyOptions()) = %d, want 0", len(got))
+	}
+
+	b.SetRequestOptions(RequestOptions{TransactionTag: "tenant=acme"})
+	if got := b.applyOptions(); len(got) != 1 {
+		t.Errorf("len(applyOptions()) = %d, want 1", len(got))
+	}
+}
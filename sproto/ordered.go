@@ -0,0 +1,100 @@
+package sproto
+
+import (
+	"context"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/grpc/codes"
+)
+
+// ColumnValue holds the name and value of a single column in an OrderedRow.
+type ColumnValue struct {
+	// The name of the column.
+	Name string
+	// The value of the column, decoded in the same way as the values returned by ReadRow.
+	Value interface{}
+}
+
+// OrderedRow is a row whose columns are kept in the order in which they were requested.
+type OrderedRow []ColumnValue
+
+// Names returns the column names in the order in which they appear in the row.
+func (r OrderedRow) Names() []string {
+	names := make([]string, len(r))
+	for i, c := range r {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Values returns the column values in the order in which they appear in the row.
+func (r OrderedRow) Values() []interface{} {
+	values := make([]interface{}, len(r))
+	for i, c := range r {
+		values[i] = c.Value
+	}
+	return values
+}
+
+// Get returns the value of the named column and whether the column is present in the row.
+func (r OrderedRow) Get(name string) (interface{}, bool) {
+	for _, c := range r {
+		if c.Name == name {
+			return c.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Map returns the row as a map of column names and their respective values, as returned by ReadRow.
+func (r OrderedRow) Map() map[string]interface{} {
+	res := make(map[string]interface{}, len(r))
+	for _, c := range r {
+		res[c.Name] = c.Value
+	}
+	return res
+}
+
+/*
+ReadRowOrdered reads a row from the specified table using the provided row key and column names.
+
+Unlike ReadRow, the columns are returned in the same order as the requested columns, which provides deterministic output
+when, for example, exporting rows to CSV.
+*/
+func (s *Client) ReadRowOrdered(ctx context.Context, tableName string, rowKey spanner.Key, columns []string, opts *spanner.ReadOptions) (OrderedRow, error) {
+	row, err := s.single().ReadRowWithOptions(ctx, tableName, rowKey, columns, s.readOptions(opts))
+	if err != nil {
+		if spanner.ErrCode(err) == codes.NotFound {
+			return nil, ErrNotFound{
+				RowKey: rowKey.String(),
+				err:    err,
+			}
+		}
+
+		return nil, err
+	}
+
+	return rowToOrdered(row)
+}
+
+/*
+QueryRowsOrdered reads multiple rows from the specified table using the provided column names and filtering condition.
+
+It behaves like QueryRows, except that the columns of each row are returned in the same order as the requested columns.
+*/
+func (s *Client) QueryRowsOrdered(ctx context.Context, tableName string, columns []string, filter *spanner.Statement, opts *ReadOptions) ([]OrderedRow, string, error) {
+	return queryRows(ctx, s, tableName, columns, filter, opts, rowToOrdered)
+}
+
+// rowToOrdered converts a spanner row into an OrderedRow, keeping the order of the columns in the row.
+func rowToOrdered(row *spanner.Row) (OrderedRow, error) {
+	res := make(OrderedRow, 0, row.Size())
+	for i, columnName := range row.ColumnNames() {
+		columnValue, err := parseColumnValue(row, i)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ColumnValue{Name: columnName, Value: columnValue})
+	}
+	return res, nil
+}
@@ -0,0 +1,53 @@
+package sproto
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+func TestClient_ReadRowOrdered(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	t.Cleanup(func() {
+		_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+	})
+
+	if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{
+		"Id":     id,
+		"Tags":   []string{"a"},
+		"Scores": []int{1},
+	}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+
+	columns := []string{"Scores", "Id", "Tags"}
+	row, err := sproto.ReadRowOrdered(ctx, "test_table", spanner.Key{id}, columns, nil)
+	if err != nil {
+		t.Fatalf("ReadRowOrdered() error = %v", err)
+	}
+	if got := row.Names(); !reflect.DeepEqual(got, columns) {
+		t.Errorf("ReadRowOrdered() columns = %v, want %v", got, columns)
+	}
+	if got, want := row.Values(), []interface{}{[]int64{1}, id, []string{"a"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadRowOrdered() values = %#v, want %#v", got, want)
+	}
+
+	columns = []string{"Tags", "Scores", "Id"}
+	rows, _, err := sproto.QueryRowsOrdered(ctx, "test_table", columns, &spanner.Statement{
+		SQL:    "Id = @id",
+		Params: map[string]interface{}{"id": id},
+	}, nil)
+	if err != nil {
+		t.Fatalf("QueryRowsOrdered() error = %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("QueryRowsOrdered() got %d rows, want 1", len(rows))
+	}
+	if got := rows[0].Names(); !reflect.DeepEqual(got, columns) {
+		t.Errorf("QueryRowsOrdered() columns = %v, want %v", got, columns)
+	}
+}
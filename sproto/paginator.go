@@ -0,0 +1,110 @@
+package sproto
+
+import (
+	"context"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+Paginator iterates over all the pages of a paginated query, managing the page token internally.
+
+Example:
+
+	paginator := tableClient.QueryPaginator(messages, filter, &sproto.QueryOptions{Limit: 100})
+	for paginator.HasNext() {
+		rows, err := paginator.Next(ctx)
+		if err != nil {
+			return err
+		}
+		// Process the page of rows
+	}
+*/
+type Paginator[T any] struct {
+	fetch     func(ctx context.Context, pageToken string) ([]T, string, error)
+	pageToken string
+	done      bool
+}
+
+/*
+NewPaginator creates a new Paginator using the provided function to fetch a single page.
+
+The fetch function receives the page token of the page to fetch, which is empty for the first page, and returns the
+items of the page along with the next page token. An empty next page token indicates that there are no more pages.
+*/
+func NewPaginator[T any](fetch func(ctx context.Context, pageToken string) ([]T, string, error)) *Paginator[T] {
+	return &Paginator[T]{
+		fetch: fetch,
+	}
+}
+
+/*
+HasNext returns true if there are more pages to fetch.
+*/
+func (p *Paginator[T]) HasNext() bool {
+	return !p.done
+}
+
+/*
+Next fetches the next page and advances the page token.
+
+This method returns iterator.Done if there are no more pages.
+If an error occurs, the page token is not advanced and the same page may be fetched again by calling Next.
+*/
+func (p *Paginator[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, iterator.Done
+	}
+
+	items, nextPageToken, err := p.fetch(ctx, p.pageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	p.pageToken = nextPageToken
+	p.done = nextPageToken == ""
+
+	return items, nil
+}
+
+/*
+QueryPaginator returns a Paginator over all the pages of Query.
+
+The PageToken of the provided options, if any, is used as the starting point. The options are not modified.
+*/
+func (t *TableClient) QueryPaginator(messages []proto.Message, filter *spanner.Statement, opts *QueryOptions) *Paginator[*Row] {
+	p := NewPaginator(func(ctx context.Context, pageToken string) ([]*Row, string, error) {
+		pageOpts := &QueryOptions{}
+		if opts != nil {
+			*pageOpts = *opts
+		}
+		pageOpts.PageToken = pageToken
+		return t.Query(ctx, messages, filter, pageOpts)
+	})
+	if opts != nil {
+		p.pageToken = opts.PageToken
+	}
+	return p
+}
+
+/*
+QueryProtosPaginator returns a Paginator over all the pages of QueryProtos.
+
+The PageToken of the provided options, if any, is used as the starting point. The options are not modified.
+*/
+func (s *Client) QueryProtosPaginator(tableName string, columnNames []string, messages []proto.Message, filter *spanner.Statement, opts *ReadOptions) *Paginator[map[string]proto.Message] {
+	p := NewPaginator(func(ctx context.Context, pageToken string) ([]map[string]proto.Message, string, error) {
+		pageOpts := &ReadOptions{}
+		if opts != nil {
+			*pageOpts = *opts
+		}
+		pageOpts.PageToken = pageToken
+		return s.QueryProtos(ctx, tableName, columnNames, messages, filter, pageOpts)
+	})
+	if opts != nil {
+		p.pageToken = opts.PageToken
+	}
+	return p
+}
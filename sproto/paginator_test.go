@@ -0,0 +1,79 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/iterator"
+)
+
+func TestPaginator(t *testing.T) {
+	pages := map[string]struct {
+		items         []int
+		nextPageToken string
+	}{
+		"":   {items: []int{1, 2}, nextPageToken: "p2"},
+		"p2": {items: []int{3, 4}, nextPageToken: "p3"},
+		"p3": {items: []int{5}, nextPageToken: ""},
+	}
+	var tokens []string
+	paginator := NewPaginator(func(ctx context.Context, pageToken string) ([]int, string, error) {
+		tokens = append(tokens, pageToken)
+		page := pages[pageToken]
+		return page.items, page.nextPageToken, nil
+	})
+
+	var got []int
+	for paginator.HasNext() {
+		items, err := paginator.Next(context.Background())
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		got = append(got, items...)
+	}
+
+	if want := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+	if want := []string{"", "p2", "p3"}; !reflect.DeepEqual(tokens, want) {
+		t.Errorf("page tokens = %v, want %v", tokens, want)
+	}
+	if _, err := paginator.Next(context.Background()); !errors.Is(err, iterator.Done) {
+		t.Errorf("Next() error = %v, want iterator.Done", err)
+	}
+}
+
+func TestPaginator_ErrorDoesNotAdvance(t *testing.T) {
+	fail := true
+	var tokens []string
+	paginator := NewPaginator(func(ctx context.Context, pageToken string) ([]int, string, error) {
+		tokens = append(tokens, pageToken)
+		if pageToken == "p2" && fail {
+			fail = false
+			return nil, "", errors.New("transient")
+		}
+		if pageToken == "" {
+			return []int{1}, "p2", nil
+		}
+		return []int{2}, "", nil
+	})
+
+	ctx := context.Background()
+	if _, err := paginator.Next(ctx); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if _, err := paginator.Next(ctx); err == nil {
+		t.Fatalf("Next() error = nil, want error")
+	}
+	if !paginator.HasNext() {
+		t.Fatalf("HasNext() = false after error, want true")
+	}
+	if items, err := paginator.Next(ctx); err != nil || !reflect.DeepEqual(items, []int{2}) {
+		t.Errorf("Next() = %v, %v, want [2], nil", items, err)
+	}
+	if want := []string{"", "p2", "p2"}; !reflect.DeepEqual(tokens, want) {
+		t.Errorf("page tokens = %v, want %v", tokens, want)
+	}
+}
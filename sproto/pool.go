@@ -0,0 +1,78 @@
+package sproto
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/spanner"
+)
+
+// sessionPoolOptions holds the session pool and channel tuning of the underlying spanner.Client.
+type sessionPoolOptions struct {
+	minOpened   uint64
+	maxOpened   uint64
+	numChannels int
+}
+
+/*
+WithSessionPool sets the minimum and maximum number of sessions kept open by the underlying spanner.Client, when
+created by NewClient or NewDbClient.
+
+The Spanner defaults are spanner.DefaultSessionPoolConfig, i.e. a minimum of 100 and a maximum of 400 sessions.
+Raise the maximum for high-throughput services which otherwise block on, or fail with, session exhaustion.
+The minimum may not exceed the maximum, and the maximum must be positive.
+*/
+func WithSessionPool(minOpened, maxOpened uint64) ClientOption {
+	return func(o *ClientOptions) {
+		o.sessionPool.minOpened = minOpened
+		o.sessionPool.maxOpened = maxOpened
+	}
+}
+
+/*
+WithNumChannels sets the number of gRPC channels used by the underlying spanner.Client, when created by NewClient or
+NewDbClient.
+
+The Spanner default is 4 channels. Each channel multiplexes up to 100 concurrent streams, so services with many
+concurrent requests may require more. The number must be positive.
+*/
+func WithNumChannels(n int) ClientOption {
+	return func(o *ClientOptions) {
+		o.sessionPool.numChannels = n
+	}
+}
+
+// clientConfig returns the spanner.ClientConfig for the provided database role and options.
+func clientConfig(databaseRole string, options *ClientOptions) (spanner.ClientConfig, error) {
+	config := spanner.ClientConfig{
+		DisableNativeMetrics: true,
+		DatabaseRole:         databaseRole,
+	}
+
+	pool := options.sessionPool
+	if pool.minOpened != 0 || pool.maxOpened != 0 {
+		if pool.maxOpened == 0 {
+			return spanner.ClientConfig{}, ErrInvalidArguments{
+				err:    fmt.Errorf("the maximum number of sessions must be positive"),
+				fields: []string{"maxOpened"},
+			}
+		}
+		if pool.minOpened > pool.maxOpened {
+			return spanner.ClientConfig{}, ErrInvalidArguments{
+				err:    fmt.Errorf("the minimum number of sessions (%d) exceeds the maximum (%d)", pool.minOpened, pool.maxOpened),
+				fields: []string{"minOpened", "maxOpened"},
+			}
+		}
+		config.SessionPoolConfig = spanner.DefaultSessionPoolConfig
+		config.SessionPoolConfig.MinOpened = pool.minOpened
+		config.SessionPoolConfig.MaxOpened = pool.maxOpened
+	}
+	if pool.numChannels < 0 {
+		return spanner.ClientConfig{}, ErrInvalidArguments{
+			err:    fmt.Errorf("the number of channels must be positive, got %d", pool.numChannels),
+			fields: []string{"numChannels"},
+		}
+	}
+	config.NumChannels = pool.numChannels
+
+	return config, nil
+}
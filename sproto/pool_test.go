@@ -0,0 +1,64 @@
+package sproto
+
+import (
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+)
+
+func Test_clientConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		opts            []ClientOption
+		wantMinOpened   uint64
+		wantMaxOpened   uint64
+		wantNumChannels int
+		wantErr         bool
+	}{
+		{name: "defaults", opts: nil},
+		{
+			name:            "session pool and channels",
+			opts:            []ClientOption{WithSessionPool(50, 1000), WithNumChannels(8)},
+			wantMinOpened:   50,
+			wantMaxOpened:   1000,
+			wantNumChannels: 8,
+		},
+		{name: "minimum exceeds maximum", opts: []ClientOption{WithSessionPool(500, 100)}, wantErr: true},
+		{name: "no maximum", opts: []ClientOption{WithSessionPool(10, 0)}, wantErr: true},
+		{name: "negative channels", opts: []ClientOption{WithNumChannels(-1)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := &ClientOptions{}
+			for _, opt := range tt.opts {
+				opt(options)
+			}
+			got, err := clientConfig("reader", options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("clientConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidArguments{}) {
+					t.Errorf("clientConfig() error = %v, want ErrInvalidArguments", err)
+				}
+				return
+			}
+
+			if got.DatabaseRole != "reader" || !got.DisableNativeMetrics {
+				t.Errorf("clientConfig() = %+v, want database role and native metrics disabled", got)
+			}
+			if got.SessionPoolConfig.MinOpened != tt.wantMinOpened || got.SessionPoolConfig.MaxOpened != tt.wantMaxOpened {
+				t.Errorf("clientConfig() sessions = [%d, %d], want [%d, %d]", got.SessionPoolConfig.MinOpened,
+					got.SessionPoolConfig.MaxOpened, tt.wantMinOpened, tt.wantMaxOpened)
+			}
+			if got.NumChannels != tt.wantNumChannels {
+				t.Errorf("clientConfig() channels = %d, want %d", got.NumChannels, tt.wantNumChannels)
+			}
+			// The remaining session pool settings keep the Spanner defaults.
+			if tt.wantMaxOpened != 0 && got.SessionPoolConfig.MaxBurst != spanner.DefaultSessionPoolConfig.MaxBurst {
+				t.Errorf("clientConfig() max burst = %d, want the default", got.SessionPoolConfig.MaxBurst)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package sproto
+
+import (
+	"context"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+ReadProtos reads the proto messages of an ARRAY<PROTO> column from the specified table using the provided row key.
+
+The row key is a tuple of the row's primary keys values and is used to identify the row to read.
+The column must be of type ARRAY<PROTO>, holding messages of the same type as the provided message, which is only used
+to determine the type of the returned messages.
+
+A NULL column is read as an empty slice, and NULL elements as empty messages, so that the messages keep their
+position in the array.
+*/
+func (s *Client) ReadProtos(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message) ([]proto.Message, error) {
+	row, err := s.single().ReadRowWithOptions(ctx, tableName, rowKey, []string{columnName}, s.readOptions(nil))
+	if err != nil {
+		if spanner.ErrCode(err) == codes.NotFound {
+			return nil, ErrNotFound{
+				RowKey: rowKey.String(),
+				err:    err,
+			}
+		}
+
+		return nil, err
+	}
+
+	// Get the elements of the column as bytes
+	var elements [][]byte
+	if err := row.Column(0, &elements); err != nil {
+		return nil, err
+	}
+
+	// Unmarshal each element into a new message of the provided type
+	res := make([]proto.Message, 0, len(elements))
+	for _, element := range elements {
+		newMessage := newEmptyMessage(message)
+		if err := s.unmarshal(element, newMessage); err != nil {
+			return nil, err
+		}
+		res = append(res, newMessage)
+	}
+
+	return res, nil
+}
+
+/*
+WriteProtos writes the provided proto messages to an ARRAY<PROTO> column of the provided table, replacing the current
+messages of the column, if any.
+
+The row key is a tuple of the row's primary keys values and is used to identify the row to write, in the same way as
+with WriteProto. The column's element type must match the full message name of the messages including the proto
+package.
+*/
+func (s *Client) WriteProtos(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, messages []proto.Message) error {
+	// Mutations carry the wire encoding of the messages, the column type determines how they are stored
+	elements := make([][]byte, len(messages))
+	for i, message := range messages {
+		b, err := proto.Marshal(message)
+		if err != nil {
+			return err
+		}
+		elements[i] = b
+	}
+
+	mutation, err := s.writeColumnMutation(ctx, tableName, rowKey, columnName, elements, nil)
+	if err != nil {
+		return err
+	}
+
+	// Apply the mutation
+	_, err = s.client.Apply(ctx, []*spanner.Mutation{mutation}, s.applyOptions()...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package sproto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestClient_ReadProtos(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+	})
+
+	// A NULL column is read as an empty slice.
+	got, err := sproto.ReadProtos(ctx, "test_table", spanner.Key{id}, "Blobs", &wrapperspb.StringValue{})
+	if err != nil {
+		t.Fatalf("ReadProtos() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("ReadProtos() = %v, want no messages", got)
+	}
+
+	want := []proto.Message{wrapperspb.String("a"), wrapperspb.String("b")}
+	if err := sproto.WriteProtos(ctx, "test_table", spanner.Key{id}, "Blobs", want); err != nil {
+		t.Fatalf("WriteProtos() error = %v", err)
+	}
+	got, err = sproto.ReadProtos(ctx, "test_table", spanner.Key{id}, "Blobs", &wrapperspb.StringValue{})
+	if err != nil {
+		t.Fatalf("ReadProtos() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ReadProtos() got %d messages, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if !proto.Equal(got[i], want[i]) {
+			t.Errorf("ReadProtos() message %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := sproto.ReadProtos(ctx, "test_table", spanner.Key{id + 1}, "Blobs", &wrapperspb.StringValue{}); err == nil {
+		t.Errorf("ReadProtos() error = nil, want ErrNotFound for a missing row")
+	}
+}
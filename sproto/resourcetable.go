@@ -413,7 +413,6 @@ func (rt *ResourceClient) Stream(ctx context.Context, parent string, opts *Query
 
 	res := NewStreamResponse[ResourceRow]()
 	go func() {
-		ctx := context.WithoutCancel(ctx)
 		it, err := rt.tbl.Stream(ctx, msgs, &spannerStatement, tblOpts)
 		if err != nil {
 			res.setError(err)
@@ -438,7 +437,9 @@ func (rt *ResourceClient) Stream(ctx context.Context, parent string, opts *Query
 				resourceRow.Policy = row.Messages[1].(*iampb.Policy)
 			}
 
-			res.addItem(resourceRow)
+			if !res.addItem(ctx, resourceRow) {
+				return
+			}
 		}
 
 		// Wait for wg
@@ -0,0 +1,84 @@
+package sproto
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+schemaCache caches the primary key columns of the tables read and written by a Client, so that the schema is only
+queried once per table. The cache is held by pointer, so that the copies of a Client, e.g. returned by
+WithRequestOptions or passed to RunInTransaction, share it.
+*/
+type schemaCache struct {
+	mu     sync.RWMutex
+	tables map[string][]*primaryKeyColumn
+}
+
+// newSchemaCache returns an empty schemaCache.
+func newSchemaCache() *schemaCache {
+	return &schemaCache{
+		tables: map[string][]*primaryKeyColumn{},
+	}
+}
+
+// get returns the cached primary key columns of the table, if any.
+func (c *schemaCache) get(tableName string) ([]*primaryKeyColumn, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	columns, ok := c.tables[tableName]
+	return columns, ok
+}
+
+// set caches the primary key columns of the table.
+func (c *schemaCache) set(tableName string, columns []*primaryKeyColumn) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tables[tableName] = columns
+}
+
+// forget discards the cached primary key columns of the table.
+func (c *schemaCache) forget(tableName string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.tables, tableName)
+}
+
+/*
+primaryKeyColumns returns the primary key columns of the table, see getPrimaryKeyColumns, querying the schema only if
+they are not cached yet. Tables without primary key columns, typically because they do not exist, are not cached.
+*/
+func (s *Client) primaryKeyColumns(ctx context.Context, tableName string) ([]*primaryKeyColumn, error) {
+	if columns, ok := s.schemas.get(tableName); ok {
+		return columns, nil
+	}
+
+	columns, err := getPrimaryKeyColumns(ctx, s.client, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) > 0 {
+		s.schemas.set(tableName, columns)
+	}
+
+	return columns, nil
+}
+
+/*
+RefreshSchema discards the cached schema of the provided table, so that it is queried again on its next read or write.
+
+The primary key columns of each table are cached for the lifetime of the client, which is only an issue if the
+primary key of a table is changed at runtime, e.g. by dropping and recreating the table.
+*/
+func (s *Client) RefreshSchema(tableName string) {
+	s.schemas.forget(tableName)
+}
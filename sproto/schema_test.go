@@ -0,0 +1,32 @@
+package sproto
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClient_primaryKeyColumns(t *testing.T) {
+	// The client has no spanner.Client, so any query of the schema would panic.
+	c := &Client{schemas: newSchemaCache()}
+	want := []*primaryKeyColumn{{columnName: "Id"}}
+	c.schemas.set("cached_table", want)
+
+	got, err := c.WithRequestOptions(RequestOptions{RequestTag: "copy"}).primaryKeyColumns(context.Background(), "cached_table")
+	if err != nil {
+		t.Fatalf("primaryKeyColumns() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != want[0] {
+		t.Errorf("primaryKeyColumns() = %v, want the cached columns %v", got, want)
+	}
+
+	// The cache is shared with the copies of the client, but not with other clients of the same database.
+	other := &Client{client: c.client, schemas: newSchemaCache()}
+	if _, ok := other.schemas.get("cached_table"); ok {
+		t.Errorf("schemas.get() found the table in the cache of another client")
+	}
+
+	c.WithRequestOptions(RequestOptions{RequestTag: "copy"}).RefreshSchema("cached_table")
+	if _, ok := c.schemas.get("cached_table"); ok {
+		t.Errorf("RefreshSchema() left the table cached")
+	}
+}
@@ -43,6 +43,12 @@ type ReadOptions struct {
 	// This is typically retrieved from a previous response's next page token.
 	// It's a base64 encoded string(base64.StdEncoding.EncodeToString(offset)) of the offset of the last row(s) read.
 	PageToken string
+	// SnakeCaseColumns lets QueryRows and QueryRowsOrdered resolve the snake_case names of the columns to read and of
+	// the sort columns against the table schema, for example portfolio_name to a PortfolioName column.
+	//
+	// The rows are keyed by the actual column names. Names which do not match any column are rejected with an
+	// ErrInvalidArguments error. Discovering the columns costs an additional query on the information schema.
+	SnakeCaseColumns bool
 }
 
 /*
@@ -51,35 +57,98 @@ Client provides methods to easily read and write proto messages with Google Clou
 It also provides methods to easily perform CRUD operations on tables in Google Cloud Spanner.
 */
 type Client struct {
-	client *spanner.Client
+	client          *spanner.Client
+	mutationLimiter mutationLimiter
+	// roTxn, if set, is used for all reads instead of a single-use read-only transaction.
+	roTxn *spanner.ReadOnlyTransaction
+	// rwTxn, if set, is used for all reads instead of a single-use read-only transaction.
+	rwTxn *spanner.ReadWriteTransaction
+	// migrator, if set, is applied to every proto message read.
+	migrator MessageMigrator
+	// requestOptions holds the tags attached to the requests made by the client.
+	requestOptions RequestOptions
+	// timestampBound, if set, is the timestamp bound of the reads instead of a strong read.
+	timestampBound *spanner.TimestampBound
+	// schemas caches the primary key columns of the tables, it is shared by the copies of the client.
+	schemas *schemaCache
+}
+
+type ClientOptions struct {
+	mutationLimiter mutationLimiter
+	migrator        MessageMigrator
+	requestOptions  RequestOptions
+	sessionPool     sessionPoolOptions
+}
+
+// ClientOption is a functional option for the New and NewClient methods.
+type ClientOption func(*ClientOptions)
+
+/*
+WithMutationLimit sets the maximum number of mutations a batch write may commit at once, and the behavior when a batch
+write exceeds it.
+
+The limit defaults to DefaultMutationLimit and the behavior to MutationLimitError.
+Lower the limit if the table has secondary indexes, since mutations on indexes also count towards the Spanner limit.
+*/
+func WithMutationLimit(limit int, behavior MutationLimitBehavior) ClientOption {
+	return func(o *ClientOptions) {
+		o.mutationLimiter = mutationLimiter{
+			limit:    limit,
+			behavior: behavior,
+		}
+	}
+}
+
+/*
+WithMessageMigrator sets a function which is applied to every proto message read, right after it is unmarshalled and
+before any read mask is applied. See MessageMigrator.
+*/
+func WithMessageMigrator(migrator MessageMigrator) ClientOption {
+	return func(o *ClientOptions) {
+		o.migrator = migrator
+	}
 }
 
 /*
 New creates a new Client instance with the provided spanner.Client instance.
 */
-func New(client *spanner.Client) *Client {
+func New(client *spanner.Client, opts ...ClientOption) *Client {
+	options := &ClientOptions{
+		mutationLimiter: defaultMutationLimiter(),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	return &Client{
-		client: client,
+		client:          client,
+		mutationLimiter: options.mutationLimiter,
+		migrator:        options.migrator,
+		requestOptions:  options.requestOptions,
+		schemas:         newSchemaCache(),
 	}
 }
 
 /*
 NewClient creates a new Client instance with the provided Google Cloud Spanner configuration.
 Leave databaseRole empty if you are not using fine grained roles on the database.
+Use WithSessionPool and WithNumChannels to tune the underlying spanner.Client.
 */
-func NewClient(ctx context.Context, googleProject, spannerInstance, databaseName, databaseRole string) (*Client, error) {
-	clientConfig := spanner.ClientConfig{
-		DisableNativeMetrics: true,
+func NewClient(ctx context.Context, googleProject, spannerInstance, databaseName, databaseRole string, opts ...ClientOption) (*Client, error) {
+	options := &ClientOptions{}
+	for _, opt := range opts {
+		opt(options)
 	}
-	if databaseRole != "" {
-		clientConfig.DatabaseRole = databaseRole
+	config, err := clientConfig(databaseRole, options)
+	if err != nil {
+		return nil, err
 	}
-	spannerClient, err := spanner.NewClientWithConfig(ctx, fmt.Sprintf("projects/%s/instances/%s/databases/%s", googleProject, spannerInstance, databaseName), clientConfig)
+	spannerClient, err := spanner.NewClientWithConfig(ctx, fmt.Sprintf("projects/%s/instances/%s/databases/%s", googleProject, spannerInstance, databaseName), config)
 	if err != nil {
 		return nil, err
 	}
 
-	return New(spannerClient), nil
+	return New(spannerClient, opts...), nil
 }
 
 /*
@@ -108,7 +177,7 @@ The column name is used to specify the column where the proto message is stored.
 */
 func (s *Client) ReadProto(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message, readMask *fieldmaskpb.FieldMask) error {
 	// Read the proto message from the specified table
-	row, err := s.client.Single().ReadRow(ctx, tableName, rowKey, []string{columnName})
+	row, err := s.single().ReadRowWithOptions(ctx, tableName, rowKey, []string{columnName}, s.readOptions(nil))
 	if err != nil {
 		if spanner.ErrCode(err) == codes.NotFound {
 			return ErrNotFound{
@@ -128,7 +197,7 @@ func (s *Client) ReadProto(ctx context.Context, tableName string, rowKey spanner
 	}
 
 	// Unmarshal the bytes into the provided proto message
-	err = proto.Unmarshal(dataBytes, message)
+	err = s.unmarshal(dataBytes, message)
 	if err != nil {
 		return err
 	}
@@ -161,7 +230,7 @@ The method returns a slice of proto messages.
 */
 func (s *Client) BatchReadProtos(ctx context.Context, tableName string, rowKeys []spanner.Key, columnName string, message proto.Message, readMask *fieldmaskpb.FieldMask) ([]proto.Message, error) {
 	// Get the primary key columns
-	primaryKeyColumns, err := getPrimaryKeyColumns(ctx, s.client, tableName)
+	primaryKeyColumns, err := s.primaryKeyColumns(ctx, tableName)
 	if err != nil {
 		return nil, err
 	}
@@ -203,7 +272,7 @@ func (s *Client) BatchReadProtos(ctx context.Context, tableName string, rowKeys
 	columns = append(columns, columnName)
 
 	// Read the rows from the specified table
-	it := s.client.Single().Read(ctx, tableName, spanner.KeySets(keySets...), columns)
+	it := s.single().ReadWithOptions(ctx, tableName, spanner.KeySets(keySets...), columns, s.readOptions(nil))
 	defer it.Stop()
 
 	// Iterate over the rows and construct the result
@@ -233,7 +302,7 @@ func (s *Client) BatchReadProtos(ctx context.Context, tableName string, rowKeys
 
 		// Unmarshal the bytes into the provided proto message
 		newMessage := newEmptyMessage(message)
-		err = proto.Unmarshal(dataBytes, newMessage)
+		err = s.unmarshal(dataBytes, newMessage)
 		if err != nil {
 			return nil, err
 		}
@@ -270,19 +339,61 @@ The column's type must match the full message name including the proto package.
 See https://cloud.google.com/spanner/docs/reference/standard-sql/protocol-buffers
 */
 func (s *Client) WriteProto(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message) error {
-	// Get the primary key columns
-	primaryKeyColumns, err := getPrimaryKeyColumns(ctx, s.client, tableName)
+	mutation, err := s.writeColumnMutation(ctx, tableName, rowKey, columnName, message, nil)
 	if err != nil {
 		return err
 	}
 
+	// Apply the mutation
+	_, err = s.client.Apply(ctx, []*spanner.Mutation{mutation}, s.applyOptions()...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/*
+WriteProtoWithColumns writes a provided proto message to the provided table in the same way as WriteProto, along with
+the provided additional columns, e.g. an UpdateTime or TenantId column, in the same mutation. The proto message and
+the additional columns are therefore written atomically, in a single round trip.
+
+The additional columns are a map of column names and their respective values, and must neither include the primary key
+columns nor the proto column. Otherwise an ErrInvalidArguments error is returned.
+*/
+func (s *Client) WriteProtoWithColumns(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message, extra map[string]interface{}) error {
+	mutation, err := s.writeColumnMutation(ctx, tableName, rowKey, columnName, message, extra)
+	if err != nil {
+		return err
+	}
+
+	// Apply the mutation
+	_, err = s.client.Apply(ctx, []*spanner.Mutation{mutation}, s.applyOptions()...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/*
+writeColumnMutation returns the mutation writing the provided value, typically a proto message, and extra columns to
+the row identified by the row key, see WriteProtoWithColumns.
+*/
+func (s *Client) writeColumnMutation(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, value interface{}, extra map[string]interface{}) (*spanner.Mutation, error) {
+	// Get the primary key columns
+	primaryKeyColumns, err := s.primaryKeyColumns(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the row key values using the length
 	primaryKeyValues := make([]interface{}, len(rowKey))
 	copy(primaryKeyValues, rowKey)
 
 	// Ensure the length of the row key matches the length of the primary key columns
 	if len(primaryKeyColumns) != len(primaryKeyValues) {
-		return ErrInvalidArguments{
+		return nil, ErrInvalidArguments{
 			err:    fmt.Errorf("row key length does not match the primary key columns length"),
 			fields: []string{"rowKey"},
 		}
@@ -297,27 +408,37 @@ func (s *Client) WriteProto(ctx context.Context, tableName string, rowKey spanne
 		row[column.columnName] = primaryKeyValues[i]
 	}
 
-	// Add the message to the row
+	// Add the value to the row
 	// This will overwrite the existing value if it exists
-	row[columnName] = message
+	row[columnName] = value
+
+	// Ensure the extra columns do not overwrite the primary key or the message
+	for column := range extra {
+		isPrimaryKey := false
+		for _, primaryKeyColumn := range primaryKeyColumns {
+			isPrimaryKey = isPrimaryKey || primaryKeyColumn.columnName == column
+		}
+		if isPrimaryKey || column == columnName {
+			return nil, ErrInvalidArguments{
+				err:    fmt.Errorf("extra column %s is a primary key column or the proto column", column),
+				fields: []string{"extra"},
+			}
+		}
+	}
 
 	// Construct columns and values from the provided row
-	columns := make([]string, 0, len(row))
-	values := make([]interface{}, 0, len(row))
+	columns := make([]string, 0, len(row)+len(extra))
+	values := make([]interface{}, 0, len(row)+len(extra))
 	for column, value := range row {
 		columns = append(columns, column)
 		values = append(values, value)
 	}
-
-	// Apply the mutation
-	_, err = s.client.Apply(ctx, []*spanner.Mutation{
-		spanner.InsertOrUpdate(tableName, columns, values),
-	})
-	if err != nil {
-		return err
+	for column, value := range extra {
+		columns = append(columns, column)
+		values = append(values, encodeColumnValue(value))
 	}
 
-	return nil
+	return spanner.InsertOrUpdate(tableName, columns, values), nil
 }
 
 /*
@@ -330,45 +451,31 @@ The method returns a slice of proto messages.
 The second return value is the next page token which can be used to get the next page of results.
 */
 func (s *Client) ListProtos(ctx context.Context, tableName string, columnName string, message proto.Message, opts *ReadOptions) ([]proto.Message, string, error) {
-	// Read the proto messages from the specified table
-	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s IS NOT NULL", columnName, tableName, columnName)
-	// Add sorting conditions if provided
-	if opts != nil && opts.SortColumns != nil && len(opts.SortColumns) > 0 {
-		query += " ORDER BY "
+	res, token, _, err := s.listProtos(ctx, tableName, columnName, message, opts)
+	return res, token, err
+}
 
-		sortColumns := make([]string, 0, len(opts.SortColumns))
-		for column, order := range opts.SortColumns {
-			sortColumns = append(sortColumns, fmt.Sprintf("%s %s", column, order.String()))
-		}
+/*
+listProtos implements ListProtos, additionally returning the total number of messages in the table.
 
-		query += strings.Join(sortColumns, ", ")
-	}
-	// Add limit if provided
-	if opts != nil && opts.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT %v", opts.Limit)
-	}
-	// Add offset if next page token is provided
-	var initialOffset int64
-	if opts != nil && opts.PageToken != "" {
-		offsetBytes, err := base64.StdEncoding.DecodeString(opts.PageToken)
-		if err != nil {
-			return nil, "", ErrInvalidPageToken{
-				pageToken: opts.PageToken,
-			}
-		}
+The page and the total are read from the same snapshot, so that a concurrent write cannot make the next page token skip
+or repeat messages.
+*/
+func (s *Client) listProtos(ctx context.Context, tableName string, columnName string, message proto.Message, opts *ReadOptions) ([]proto.Message, string, int64, error) {
+	s, release := s.snapshot()
+	defer release()
 
-		offset, err := strconv.ParseInt(string(offsetBytes), 10, 64)
-		if err != nil {
-			return nil, "", ErrInvalidPageToken{
-				pageToken: opts.PageToken,
-			}
-		}
-		initialOffset = offset
-		query += fmt.Sprintf(" OFFSET %v", offset)
+	// Read the proto messages from the specified table
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s IS NOT NULL", columnName, tableName, columnName)
+	// Add sorting, limit and offset conditions if provided
+	pagination, initialOffset, err := paginationClauses(opts, 0)
+	if err != nil {
+		return nil, "", 0, err
 	}
-	it := s.client.Single().Query(ctx, spanner.Statement{
+	query += pagination
+	it := s.single().QueryWithOptions(ctx, spanner.Statement{
 		SQL: query,
-	})
+	}, s.queryOptions())
 	defer it.Stop()
 
 	// Iterate over the rows and construct the result
@@ -379,57 +486,176 @@ func (s *Client) ListProtos(ctx context.Context, tableName string, columnName st
 			break
 		}
 		if err != nil {
-			return nil, "", err
+			return nil, "", 0, err
 		}
 
 		// Get the column value as bytes
 		var dataBytes []byte
 		err = row.Columns(&dataBytes)
 		if err != nil {
-			return nil, "", err
+			return nil, "", 0, err
 		}
 
 		// Unmarshal the bytes into the provided proto message
 		newMessage := newEmptyMessage(message)
-		err = proto.Unmarshal(dataBytes, newMessage)
+		err = s.unmarshal(dataBytes, newMessage)
 		if err != nil {
-			return nil, "", err
+			return nil, "", 0, err
 		}
 
 		res = append(res, newMessage)
 	}
 
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s IS NOT NULL", tableName, columnName)
-	itCount := s.client.Single().Query(ctx, spanner.Statement{
-		SQL: countQuery,
+	rowCount, err := s.Count(ctx, tableName, &spanner.Statement{
+		SQL: fmt.Sprintf("%s IS NOT NULL", columnName),
 	})
-	defer itCount.Stop()
-	var rowCount int64
-	for {
-		row, err := itCount.Next()
-		if errors.Is(err, iterator.Done) {
-			break
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	return res, nextPageToken(initialOffset, len(res), rowCount), rowCount, nil
+}
+
+/*
+paginationClauses returns the ORDER BY, LIMIT and OFFSET clauses for the provided options, along with the offset
+decoded from the page token. The lookahead is the number of rows read beyond the limit, e.g. 1 to determine whether
+there is a next page.
+*/
+func paginationClauses(opts *ReadOptions, lookahead int64) (string, int64, error) {
+	var clauses string
+	// Add sorting conditions if provided
+	if opts != nil && opts.SortColumns != nil && len(opts.SortColumns) > 0 {
+		clauses += " ORDER BY "
+
+		sortColumns := make([]string, 0, len(opts.SortColumns))
+		for column, order := range opts.SortColumns {
+			sortColumns = append(sortColumns, fmt.Sprintf("%s %s", column, order.String()))
 		}
+
+		clauses += strings.Join(sortColumns, ", ")
+	}
+	// Add limit if provided
+	if opts != nil && opts.Limit > 0 {
+		clauses += fmt.Sprintf(" LIMIT %v", int64(opts.Limit)+lookahead)
+	}
+	// Add offset if next page token is provided
+	var offset int64
+	if opts != nil && opts.PageToken != "" {
+		offsetBytes, err := base64.StdEncoding.DecodeString(opts.PageToken)
 		if err != nil {
-			return nil, "", err
+			return "", 0, ErrInvalidPageToken{
+				pageToken: opts.PageToken,
+			}
 		}
 
-		if err := row.Column(0, &rowCount); err != nil {
-			return nil, "", err
+		offset, err = strconv.ParseInt(string(offsetBytes), 10, 64)
+		if err != nil {
+			return "", 0, ErrInvalidPageToken{
+				pageToken: opts.PageToken,
+			}
 		}
+		clauses += fmt.Sprintf(" OFFSET %v", offset)
+	}
+
+	return clauses, offset, nil
+}
 
-		break
+/*
+Count returns the number of rows of the specified table matching the provided filter, without reading the rows.
+
+The filter is a SQL statement used in the same way as with QueryRows, i.e. without the WHERE keyword, and can include
+parameters. A nil filter counts all the rows of the table.
+*/
+func (s *Client) Count(ctx context.Context, tableName string, filter *spanner.Statement) (int64, error) {
+	stmt := spanner.Statement{
+		SQL: fmt.Sprintf("SELECT COUNT(*) FROM %s", tableName),
+	}
+	// Add filtering condition if provided
+	if filter != nil && filter.SQL != "" {
+		stmt.SQL += " WHERE " + filter.SQL
+		stmt.Params = filter.Params
 	}
 
-	// Compare total row count and results + offset to determine if there are more results
-	// and if so, return the next page token
-	var nextPageToken string
-	if (initialOffset + int64(len(res))) < rowCount {
-		offsetStr := fmt.Sprintf("%v", initialOffset+int64(len(res)))
-		nextPageToken = base64.StdEncoding.EncodeToString([]byte(offsetStr))
+	return s.countRows(ctx, stmt)
+}
+
+/*
+Exists reports whether the specified table has a row with the provided row key. Only the primary key columns of the
+row are read.
+*/
+func (s *Client) Exists(ctx context.Context, tableName string, rowKey spanner.Key) (bool, error) {
+	primaryKeyColumns, err := s.primaryKeyColumns(ctx, tableName)
+	if err != nil {
+		return false, err
+	}
+	if len(primaryKeyColumns) == 0 {
+		return false, ErrNotFound{
+			err: fmt.Errorf("table %s", tableName),
+		}
+	}
+	columns := make([]string, len(primaryKeyColumns))
+	for i, column := range primaryKeyColumns {
+		columns[i] = column.columnName
 	}
 
-	return res, nextPageToken, nil
+	_, err = s.single().ReadRowWithOptions(ctx, tableName, rowKey, columns, s.readOptions(nil))
+	if err != nil {
+		if spanner.ErrCode(err) == codes.NotFound {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// countRows runs the provided COUNT(*) statement and returns the resulting count.
+func (s *Client) countRows(ctx context.Context, stmt spanner.Statement) (int64, error) {
+	it := s.single().QueryWithOptions(ctx, stmt, s.queryOptions())
+	defer it.Stop()
+
+	var rowCount int64
+	row, err := it.Next()
+	if errors.Is(err, iterator.Done) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := row.Column(0, &rowCount); err != nil {
+		return 0, err
+	}
+
+	return rowCount, nil
+}
+
+/*
+nextPageToken compares the total row count with the number of results read from the offset to determine if there are
+more results, and if so, returns the token of the next page.
+*/
+func nextPageToken(offset int64, results int, rowCount int64) string {
+	if (offset + int64(results)) < rowCount {
+		return encodePageToken(offset + int64(results))
+	}
+	return ""
+}
+
+/*
+trimPage drops the extra row read beyond the limit of the provided options, which indicates that there are more
+results, and if so, returns the token of the next page along with the trimmed results.
+*/
+func trimPage[T any](res []T, offset int64, opts *ReadOptions) ([]T, string) {
+	if opts == nil || opts.Limit <= 0 || len(res) <= int(opts.Limit) {
+		return res, ""
+	}
+	res = res[:opts.Limit]
+	return res, encodePageToken(offset + int64(len(res)))
+}
+
+// encodePageToken returns the page token of the provided offset.
+func encodePageToken(offset int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.FormatInt(offset, 10)))
 }
 
 /*
@@ -448,7 +674,7 @@ func (s *Client) StreamProtos(ctx context.Context, tableName string, columnName
 
 	go func() {
 		// Read the proto message from the specified table
-		it := s.client.Single().ReadWithOptions(ctx, tableName, spanner.AllKeys(), []string{columnName}, opts)
+		it := s.single().ReadWithOptions(ctx, tableName, spanner.AllKeys(), []string{columnName}, s.readOptions(opts))
 		defer it.Stop()
 
 		for {
@@ -471,13 +697,15 @@ func (s *Client) StreamProtos(ctx context.Context, tableName string, columnName
 
 			// Unmarshal the bytes into the provided proto message
 			newMessage := newEmptyMessage(message)
-			err = proto.Unmarshal(dataBytes, newMessage)
+			err = s.unmarshal(dataBytes, newMessage)
 			if err != nil {
 				res.setError(err)
 				return
 			}
 
-			res.addItem(&newMessage)
+			if !res.addItem(ctx, &newMessage) {
+				return
+			}
 		}
 
 		// Wait for wg
@@ -526,40 +754,12 @@ func (s *Client) QueryProtos(ctx context.Context, tableName string, columnNames
 			params = filter.Params
 		}
 	}
-	// Add sorting conditions if provided
-	if opts != nil && opts.SortColumns != nil && len(opts.SortColumns) > 0 {
-		query += " ORDER BY "
-
-		sortColumns := make([]string, 0, len(opts.SortColumns))
-		for column, order := range opts.SortColumns {
-			sortColumns = append(sortColumns, fmt.Sprintf("%s %s", column, order.String()))
-		}
-
-		query += strings.Join(sortColumns, ", ")
-	}
-	// Add limit if provided
-	if opts != nil && opts.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT %v", opts.Limit)
-	}
-	// Add offset if page token is provided
-	var initialOffset int64
-	if opts != nil && opts.PageToken != "" {
-		offsetBytes, err := base64.StdEncoding.DecodeString(opts.PageToken)
-		if err != nil {
-			return nil, "", ErrInvalidPageToken{
-				pageToken: opts.PageToken,
-			}
-		}
-
-		offset, err := strconv.ParseInt(string(offsetBytes), 10, 64)
-		if err != nil {
-			return nil, "", ErrInvalidPageToken{
-				pageToken: opts.PageToken,
-			}
-		}
-		initialOffset = offset
-		query += fmt.Sprintf(" OFFSET %v", offset)
+	// Add sorting, limit and offset conditions if provided, reading one more row to determine whether there is a next page
+	pagination, initialOffset, err := paginationClauses(opts, 1)
+	if err != nil {
+		return nil, "", err
 	}
+	query += pagination
 
 	// Create a map of column names and their respective proto messages
 	columnToMessage := make(map[string]proto.Message)
@@ -572,7 +772,7 @@ func (s *Client) QueryProtos(ctx context.Context, tableName string, columnNames
 		Params: params,
 	}
 
-	it := s.client.Single().Query(ctx, stmt)
+	it := s.single().QueryWithOptions(ctx, stmt, s.queryOptions())
 	defer it.Stop()
 
 	// Iterate over the rows and construct the result
@@ -595,7 +795,7 @@ func (s *Client) QueryProtos(ctx context.Context, tableName string, columnNames
 
 			// Unmarshal the bytes into the provided proto message
 			newMessage := newEmptyMessage(columnToMessage[columnName])
-			if err := proto.Unmarshal(dataBytes, newMessage); err != nil {
+			if err := s.unmarshal(dataBytes, newMessage); err != nil {
 				return nil, "", err
 			}
 
@@ -605,44 +805,8 @@ func (s *Client) QueryProtos(ctx context.Context, tableName string, columnNames
 		res = append(res, rowMap)
 	}
 
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s", tableName)
-	countQueryParams := map[string]interface{}{}
-	// Add filtering condition if provided
-	if filter != nil && filter.SQL != "" {
-		countQuery += " WHERE " + filter.SQL
-		if filter.Params != nil && len(filter.Params) > 0 {
-			countQueryParams = filter.Params
-		}
-	}
-	itCount := s.client.Single().Query(ctx, spanner.Statement{
-		SQL:    countQuery,
-		Params: countQueryParams,
-	})
-	defer itCount.Stop()
-	var rowCount int64
-	for {
-		row, err := itCount.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			return nil, "", err
-		}
-
-		if err := row.Column(0, &rowCount); err != nil {
-			return nil, "", err
-		}
-
-		break
-	}
-
-	// Compare total row count and results + offset to determine if there are more results
-	// and if so, return the next page token
-	var nextPageToken string
-	if (initialOffset + int64(len(res))) < rowCount {
-		offsetStr := fmt.Sprintf("%v", initialOffset+int64(len(res)))
-		nextPageToken = base64.StdEncoding.EncodeToString([]byte(offsetStr))
-	}
+	// The extra row read, if any, indicates that there is a next page
+	res, nextPageToken := trimPage(res, initialOffset, opts)
 
 	return res, nextPageToken, nil
 }
@@ -688,42 +852,14 @@ func (s *Client) StreamQueryProtos(ctx context.Context, tableName string, column
 			params = filter.Params
 		}
 	}
-	// Add sorting conditions if provided
-	if opts != nil && opts.SortColumns != nil && len(opts.SortColumns) > 0 {
-		query += " ORDER BY "
-
-		sortColumns := make([]string, 0, len(opts.SortColumns))
-		for column, order := range opts.SortColumns {
-			sortColumns = append(sortColumns, fmt.Sprintf("%s %s", column, order.String()))
-		}
-
-		query += strings.Join(sortColumns, ", ")
-	}
-	// Add limit if provided
-	if opts != nil && opts.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT %v", opts.Limit)
-	}
-	// Add offset if page token is provided
-	if opts != nil && opts.PageToken != "" {
-		offsetBytes, err := base64.StdEncoding.DecodeString(opts.PageToken)
-		if err != nil {
-			res := NewStreamResponse[map[string]proto.Message]()
-			res.setError(ErrInvalidPageToken{
-				pageToken: opts.PageToken,
-			})
-			return res
-		}
-
-		offset, err := strconv.ParseInt(string(offsetBytes), 10, 64)
-		if err != nil {
-			res := NewStreamResponse[map[string]proto.Message]()
-			res.setError(ErrInvalidPageToken{
-				pageToken: opts.PageToken,
-			})
-			return res
-		}
-		query += fmt.Sprintf(" OFFSET %v", offset)
+	// Add sorting, limit and offset conditions if provided
+	pagination, _, err := paginationClauses(opts, 0)
+	if err != nil {
+		res := NewStreamResponse[map[string]proto.Message]()
+		res.setError(err)
+		return res
 	}
+	query += pagination
 
 	// Create a map of column names and their respective proto messages
 	columnToMessage := make(map[string]proto.Message)
@@ -738,7 +874,7 @@ func (s *Client) StreamQueryProtos(ctx context.Context, tableName string, column
 
 	res := NewStreamResponse[map[string]proto.Message]()
 	go func() {
-		it := s.client.Single().Query(ctx, stmt)
+		it := s.single().QueryWithOptions(ctx, stmt, s.queryOptions())
 		defer it.Stop()
 
 		for {
@@ -761,7 +897,7 @@ func (s *Client) StreamQueryProtos(ctx context.Context, tableName string, column
 
 				// Unmarshal the bytes into the provided proto message
 				newMessage := newEmptyMessage(columnToMessage[columnName])
-				if err := proto.Unmarshal(dataBytes, newMessage); err != nil {
+				if err := s.unmarshal(dataBytes, newMessage); err != nil {
 					res.setError(err)
 					return
 				}
@@ -769,7 +905,9 @@ func (s *Client) StreamQueryProtos(ctx context.Context, tableName string, column
 				rowMap[columnName] = newMessage
 			}
 
-			res.addItem(&rowMap)
+			if !res.addItem(ctx, &rowMap) {
+				return
+			}
 		}
 
 		// Wait for wg
@@ -794,6 +932,9 @@ The column names must match the length of the messages and are a 1-to-1 mapping.
 
 The proto messages will be serialized to bytes and stored in the specified columns.
 The columns must be of type PROTO.
+
+This method may return a ErrMutationLimitExceeded error if the batch exceeds the mutation limit of a single commit.
+See WithMutationLimit to split such batches into multiple commits instead.
 */
 func (s *Client) BatchWriteProtos(ctx context.Context, tableName string, rowKeys []spanner.Key, columnNames []string, messages []proto.Message) error {
 	// Ensure the length of the row keys matches the length of the messages
@@ -812,12 +953,13 @@ func (s *Client) BatchWriteProtos(ctx context.Context, tableName string, rowKeys
 	}
 
 	// Get the primary key columns
-	primaryKeyColumns, err := getPrimaryKeyColumns(ctx, s.client, tableName)
+	primaryKeyColumns, err := s.primaryKeyColumns(ctx, tableName)
 	if err != nil {
 		return err
 	}
 
 	var mutations []*spanner.Mutation
+	var counts []int
 	for i, rowKey := range rowKeys {
 		// Get the row key values using the length
 		primaryKeyValues := make([]interface{}, len(rowKey))
@@ -855,10 +997,11 @@ func (s *Client) BatchWriteProtos(ctx context.Context, tableName string, rowKeys
 		}
 
 		mutations = append(mutations, spanner.InsertOrUpdate(tableName, columns, values))
+		counts = append(counts, len(columns))
 	}
 
 	// Apply the mutations
-	_, err = s.client.Apply(ctx, mutations)
+	err = s.mutationLimiter.apply(ctx, s.client, mutations, counts, s.applyOptions()...)
 	if err != nil {
 		return err
 	}
@@ -875,28 +1018,19 @@ For example if the primary key is (id, name), the row key must be spanner.Key{{i
 
 The column name is used to specify the column where the proto message will be stored.
 This is still required even if it is included in the row key.
-*/
-func (s *Client) UpdateProto(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message, updateMask *fieldmaskpb.FieldMask) error {
-	// Retrieve the current resource from the database
-	currentMessage := newEmptyMessage(message)
-	err := s.ReadProto(ctx, tableName, rowKey, columnName, currentMessage, nil)
-	if err != nil {
-		return err
-	}
 
-	// Merge the updates into currentMessage
-	err = mergeUpdates(currentMessage, message, updateMask)
-	if err != nil {
-		return err
-	}
+With an update mask, each masked field is overwritten with its value in the message, even if that is the zero value,
+which allows clearing fields, e.g. the path "address.city" clears the city if it is not set in the message.
+Fields outside of the update mask are merged as without an update mask, i.e. they are only set if not already set in
+the stored message.
 
-	// Write the updated message to the database
-	err = s.WriteProto(ctx, tableName, rowKey, columnName, currentMessage)
-	if err != nil {
-		return err
-	}
-
-	return nil
+The message is read, merged and written within a single read-write transaction, so that concurrent updates of the same
+row cannot overwrite each other's changes.
+*/
+func (s *Client) UpdateProto(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message, updateMask *fieldmaskpb.FieldMask) error {
+	return s.RunInTransaction(ctx, func(ctx context.Context, tx *Tx) error {
+		return tx.UpdateProto(ctx, tableName, rowKey, columnName, message, updateMask)
+	})
 }
 
 /*
@@ -904,7 +1038,7 @@ BatchWriteMutations writes the provided mutations to the database.
 This method provides a convenient way to write custom mutations to the database.
 */
 func (s *Client) BatchWriteMutations(ctx context.Context, mutations []*spanner.Mutation) error {
-	_, err := s.client.Apply(ctx, mutations)
+	_, err := s.client.Apply(ctx, mutations, s.applyOptions()...)
 	if err != nil {
 		return err
 	}
@@ -922,9 +1056,10 @@ For example if the primary key is (id, name), the row key must be spanner.Key{{i
 The column names are used to specify which columns to read. The order of the columns does not matter.
 
 The method returns a map of column names and their respective values.
+ARRAY columns are returned as typed Go slices, e.g. []string or []int64.
 */
 func (s *Client) ReadRow(ctx context.Context, tableName string, rowKey spanner.Key, columns []string, opts *spanner.ReadOptions) (map[string]interface{}, error) {
-	row, err := s.client.Single().ReadRowWithOptions(ctx, tableName, rowKey, columns, opts)
+	row, err := s.single().ReadRowWithOptions(ctx, tableName, rowKey, columns, s.readOptions(opts))
 	if err != nil {
 		if spanner.ErrCode(err) == codes.NotFound {
 			return nil, ErrNotFound{
@@ -936,12 +1071,7 @@ func (s *Client) ReadRow(ctx context.Context, tableName string, rowKey spanner.K
 		return nil, err
 	}
 
-	res := make(map[string]interface{})
-	for i, columnName := range row.ColumnNames() {
-		columnValue := row.ColumnValue(i)
-		res[columnName] = parseStructPbValue(columnValue)
-	}
-	return res, nil
+	return rowToMap(row)
 }
 
 /*
@@ -961,6 +1091,15 @@ The method returns a slice of maps where each map represents a row. The maps con
 The second return value is the next page token which can be used to get the next page of results.
 */
 func (s *Client) QueryRows(ctx context.Context, tableName string, columns []string, filter *spanner.Statement, opts *ReadOptions) ([]map[string]interface{}, string, error) {
+	return queryRows(ctx, s, tableName, columns, filter, opts, rowToMap)
+}
+
+// queryRows implements QueryRows and QueryRowsOrdered, decoding each row using the provided decode function.
+func queryRows[T any](ctx context.Context, s *Client, tableName string, columns []string, filter *spanner.Statement, opts *ReadOptions, decode func(row *spanner.Row) (T, error)) ([]T, string, error) {
+	columns, opts, err := resolveSnakeCaseColumns(ctx, s, tableName, columns, opts)
+	if err != nil {
+		return nil, "", err
+	}
 	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), tableName)
 	params := map[string]interface{}{}
 	// Add filtering condition if provided
@@ -970,51 +1109,23 @@ func (s *Client) QueryRows(ctx context.Context, tableName string, columns []stri
 			params = filter.Params
 		}
 	}
-	// Add sorting conditions if provided
-	if opts != nil && opts.SortColumns != nil && len(opts.SortColumns) > 0 {
-		query += " ORDER BY "
-
-		sortColumns := make([]string, 0, len(opts.SortColumns))
-		for column, order := range opts.SortColumns {
-			sortColumns = append(sortColumns, fmt.Sprintf("%s %s", column, order.String()))
-		}
-
-		query += strings.Join(sortColumns, ", ")
-	}
-	// Add limit if provided
-	if opts != nil && opts.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT %v", opts.Limit)
-	}
-	// Add offset if page token is provided
-	var initialOffset int64
-	if opts != nil && opts.PageToken != "" {
-		offsetBytes, err := base64.StdEncoding.DecodeString(opts.PageToken)
-		if err != nil {
-			return nil, "", ErrInvalidPageToken{
-				pageToken: opts.PageToken,
-			}
-		}
-
-		offset, err := strconv.ParseInt(string(offsetBytes), 10, 64)
-		if err != nil {
-			return nil, "", ErrInvalidPageToken{
-				pageToken: opts.PageToken,
-			}
-		}
-		initialOffset = offset
-		query += fmt.Sprintf(" OFFSET %v", offset)
+	// Add sorting, limit and offset conditions if provided, reading one more row to determine whether there is a next page
+	pagination, initialOffset, err := paginationClauses(opts, 1)
+	if err != nil {
+		return nil, "", err
 	}
+	query += pagination
 
 	stmt := spanner.Statement{
 		SQL:    query,
 		Params: params,
 	}
 
-	it := s.client.Single().Query(ctx, stmt)
+	it := s.single().QueryWithOptions(ctx, stmt, s.queryOptions())
 	defer it.Stop()
 
 	// Iterate over the rows and construct the result
-	var res []map[string]interface{}
+	var res []T
 	for {
 		row, err := it.Next()
 		if errors.Is(err, iterator.Done) {
@@ -1024,53 +1135,16 @@ func (s *Client) QueryRows(ctx context.Context, tableName string, columns []stri
 			return nil, "", err
 		}
 
-		rowMap := make(map[string]interface{})
-		for i, columnName := range row.ColumnNames() {
-			columnValue := row.ColumnValue(i)
-			rowMap[columnName] = parseStructPbValue(columnValue)
-		}
-
-		res = append(res, rowMap)
-	}
-
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s", tableName)
-	countQueryParams := map[string]interface{}{}
-	// Add filtering condition if provided
-	if filter != nil && filter.SQL != "" {
-		countQuery += " WHERE " + filter.SQL
-		if filter.Params != nil && len(filter.Params) > 0 {
-			countQueryParams = filter.Params
-		}
-	}
-	itCount := s.client.Single().Query(ctx, spanner.Statement{
-		SQL:    countQuery,
-		Params: countQueryParams,
-	})
-	defer itCount.Stop()
-	var rowCount int64
-	for {
-		row, err := itCount.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
+		decoded, err := decode(row)
 		if err != nil {
 			return nil, "", err
 		}
 
-		if err := row.Column(0, &rowCount); err != nil {
-			return nil, "", err
-		}
-
-		break
+		res = append(res, decoded)
 	}
 
-	// Compare total row count and results + offset to determine if there are more results
-	// and if so, return the next page token
-	var nextPageToken string
-	if (initialOffset + int64(len(res))) < rowCount {
-		offsetStr := fmt.Sprintf("%v", initialOffset+int64(len(res)))
-		nextPageToken = base64.StdEncoding.EncodeToString([]byte(offsetStr))
-	}
+	// The extra row read, if any, indicates that there is a next page
+	res, nextPageToken := trimPage(res, initialOffset, opts)
 
 	return res, nextPageToken, nil
 }
@@ -1095,7 +1169,7 @@ func (s *Client) BatchReadRows(ctx context.Context, tableName string, rowKeys []
 	}
 
 	// Read the rows from the specified table
-	it := s.client.Single().ReadWithOptions(ctx, tableName, spanner.KeySets(keySets...), columns, opts)
+	it := s.single().ReadWithOptions(ctx, tableName, spanner.KeySets(keySets...), columns, s.readOptions(opts))
 	defer it.Stop()
 
 	// Iterate over the rows and construct the result
@@ -1109,10 +1183,9 @@ func (s *Client) BatchReadRows(ctx context.Context, tableName string, rowKeys []
 			return nil, err
 		}
 
-		rowMap := make(map[string]interface{})
-		for i, columnName := range row.ColumnNames() {
-			columnValue := row.ColumnValue(i)
-			rowMap[columnName] = parseStructPbValue(columnValue)
+		rowMap, err := rowToMap(row)
+		if err != nil {
+			return nil, err
 		}
 
 		res = append(res, rowMap)
@@ -1130,7 +1203,7 @@ The method returns a slice of maps where each map represents a row. The maps con
 */
 func (s *Client) ListRows(ctx context.Context, tableName string, columns []string, opts *spanner.ReadOptions) ([]map[string]interface{}, error) {
 	// Read the rows from the specified table
-	it := s.client.Single().ReadWithOptions(ctx, tableName, spanner.AllKeys(), columns, opts)
+	it := s.single().ReadWithOptions(ctx, tableName, spanner.AllKeys(), columns, s.readOptions(opts))
 	defer it.Stop()
 
 	// Iterate over the rows and construct the result
@@ -1144,10 +1217,9 @@ func (s *Client) ListRows(ctx context.Context, tableName string, columns []strin
 			return nil, err
 		}
 
-		rowMap := make(map[string]interface{})
-		for i, columnName := range row.ColumnNames() {
-			columnValue := row.ColumnValue(i)
-			rowMap[columnName] = parseStructPbValue(columnValue)
+		rowMap, err := rowToMap(row)
+		if err != nil {
+			return nil, err
 		}
 
 		res = append(res, rowMap)
@@ -1163,6 +1235,7 @@ The primary key value(s) must be included in the row.
 
 The row is represented as a map where the key is the column name and the value is the column value.
 The value types must match the column types in the table schema.
+ARRAY columns accept Go slices of the element type, e.g. []string or []int64.
 */
 func (s *Client) InsertRow(ctx context.Context, tableName string, row map[string]interface{}) error {
 	// Construct columns and values from the provided row
@@ -1170,12 +1243,12 @@ func (s *Client) InsertRow(ctx context.Context, tableName string, row map[string
 	values := make([]interface{}, 0, len(row))
 	for column, value := range row {
 		columns = append(columns, column)
-		values = append(values, value)
+		values = append(values, encodeColumnValue(value))
 	}
 
 	_, err := s.client.Apply(ctx, []*spanner.Mutation{
 		spanner.Insert(tableName, columns, values),
-	})
+	}, s.applyOptions()...)
 	if err != nil {
 		switch spanner.ErrCode(err) {
 		case codes.AlreadyExists:
@@ -1198,6 +1271,7 @@ The primary key value(s) must be included in the rows.
 The rows are represented as a slice of maps where each map represents a row.
 Each map contains column names and their respective values.
 The value types must match the column types in the table schema.
+ARRAY columns accept Go slices of the element type, e.g. []string or []int64.
 */
 func (s *Client) BatchInsertRows(ctx context.Context, tableName string, rows []map[string]interface{}) error {
 	// Construct mutations for each row
@@ -1208,13 +1282,13 @@ func (s *Client) BatchInsertRows(ctx context.Context, tableName string, rows []m
 		values := make([]interface{}, 0, len(row))
 		for column, value := range row {
 			columns = append(columns, column)
-			values = append(values, value)
+			values = append(values, encodeColumnValue(value))
 		}
 
 		mutations = append(mutations, spanner.Insert(tableName, columns, values))
 	}
 
-	_, err := s.client.Apply(ctx, mutations)
+	_, err := s.client.Apply(ctx, mutations, s.applyOptions()...)
 	if err != nil {
 		switch spanner.ErrCode(err) {
 		case codes.AlreadyExists:
@@ -1238,6 +1312,7 @@ The primary key value(s) must be included in the row.
 
 The row is represented as a map where the key is the column name and the value is the column value.
 The value types must match the column types in the table schema.
+ARRAY columns accept Go slices of the element type, e.g. []string or []int64.
 */
 func (s *Client) UpsertRow(ctx context.Context, tableName string, row map[string]interface{}) error {
 	// Construct columns and values
@@ -1245,13 +1320,13 @@ func (s *Client) UpsertRow(ctx context.Context, tableName string, row map[string
 	values := make([]interface{}, 0, len(row))
 	for column, value := range row {
 		columns = append(columns, column)
-		values = append(values, value)
+		values = append(values, encodeColumnValue(value))
 	}
 
 	// Apply the mutation
 	_, err := s.client.Apply(ctx, []*spanner.Mutation{
 		spanner.InsertOrUpdate(tableName, columns, values),
-	})
+	}, s.applyOptions()...)
 	if err != nil {
 		return err
 	}
@@ -1269,6 +1344,7 @@ The primary key value(s) must be included in each row.
 The rows are represented as a slice of maps where each map represents a row.
 Each map contains column names and their respective values.
 The value types must match the column types in the table schema.
+ARRAY columns accept Go slices of the element type, e.g. []string or []int64.
 */
 func (s *Client) BatchUpsertRows(ctx context.Context, tableName string, rows []map[string]interface{}) error {
 	// Construct mutations
@@ -1279,14 +1355,14 @@ func (s *Client) BatchUpsertRows(ctx context.Context, tableName string, rows []m
 		values := make([]interface{}, 0, len(row))
 		for column, value := range row {
 			columns = append(columns, column)
-			values = append(values, value)
+			values = append(values, encodeColumnValue(value))
 		}
 
 		mutations = append(mutations, spanner.InsertOrUpdate(tableName, columns, values))
 	}
 
 	// Apply the mutations
-	_, err := s.client.Apply(ctx, mutations)
+	_, err := s.client.Apply(ctx, mutations, s.applyOptions()...)
 	if err != nil {
 		return err
 	}
@@ -1302,6 +1378,7 @@ The primary key value(s) must be included in the row.
 
 The row is represented as a map where the key is the column name and the value is the column value.
 The value types must match the column types in the table schema.
+ARRAY columns accept Go slices of the element type, e.g. []string or []int64.
 */
 func (s *Client) UpdateRow(ctx context.Context, tableName string, row map[string]interface{}) error {
 	// Construct columns and values
@@ -1309,13 +1386,13 @@ func (s *Client) UpdateRow(ctx context.Context, tableName string, row map[string
 	values := make([]interface{}, 0, len(row))
 	for column, value := range row {
 		columns = append(columns, column)
-		values = append(values, value)
+		values = append(values, encodeColumnValue(value))
 	}
 
 	// Apply the mutation
 	_, err := s.client.Apply(ctx, []*spanner.Mutation{
 		spanner.Update(tableName, columns, values),
-	})
+	}, s.applyOptions()...)
 	if err != nil {
 		switch spanner.ErrCode(err) {
 		case codes.NotFound:
@@ -1339,6 +1416,7 @@ The primary key value(s) must be included in each row.
 The rows are represented as a slice of maps where each map represents a row.
 Each map contains column names and their respective values.
 The value types must match the column types in the table schema.
+ARRAY columns accept Go slices of the element type, e.g. []string or []int64.
 */
 func (s *Client) BatchUpdateRows(ctx context.Context, tableName string, rows []map[string]interface{}) error {
 	var mutations []*spanner.Mutation
@@ -1348,13 +1426,13 @@ func (s *Client) BatchUpdateRows(ctx context.Context, tableName string, rows []m
 		values := make([]interface{}, 0, len(row))
 		for column, value := range row {
 			columns = append(columns, column)
-			values = append(values, value)
+			values = append(values, encodeColumnValue(value))
 		}
 
 		mutations = append(mutations, spanner.Update(tableName, columns, values))
 	}
 
-	_, err := s.client.Apply(ctx, mutations)
+	_, err := s.client.Apply(ctx, mutations, s.applyOptions()...)
 	if err != nil {
 		switch spanner.ErrCode(err) {
 		case codes.NotFound:
@@ -1387,59 +1465,15 @@ Call Next() on the StreamResponse to get the next item from the stream.
 Remember to check for io.EOF to determine when the stream is closed.
 */
 func (s *Client) StreamRows(ctx context.Context, tableName string, columns []string, filter *spanner.Statement, opts *ReadOptions) (*StreamResponse[map[string]interface{}], error) {
-	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), tableName)
-	params := map[string]interface{}{}
-	// Add filtering condition if provided
-	if filter != nil && filter.SQL != "" {
-		query += " WHERE " + filter.SQL
-		if filter.Params != nil && len(filter.Params) > 0 {
-			params = filter.Params
-		}
-	}
-	// Add sorting conditions if provided
-	if opts != nil && opts.SortColumns != nil && len(opts.SortColumns) > 0 {
-		query += " ORDER BY "
-
-		sortColumns := make([]string, 0, len(opts.SortColumns))
-		for column, order := range opts.SortColumns {
-			sortColumns = append(sortColumns, fmt.Sprintf("%s %s", column, order.String()))
-		}
-
-		query += strings.Join(sortColumns, ", ")
-	}
-	// Add limit if provided
-	if opts != nil && opts.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT %v", opts.Limit)
-	}
-	// Add offset if page token is provided
-	if opts != nil && opts.PageToken != "" {
-		offsetBytes, err := base64.StdEncoding.DecodeString(opts.PageToken)
-		if err != nil {
-			return nil, ErrInvalidPageToken{
-				pageToken: opts.PageToken,
-			}
-		}
-
-		offset, err := strconv.ParseInt(string(offsetBytes), 10, 64)
-		if err != nil {
-			return nil, ErrInvalidPageToken{
-				pageToken: opts.PageToken,
-			}
-		}
-		query += fmt.Sprintf(" OFFSET %v", offset)
-	}
-
-	stmt := spanner.Statement{
-		SQL:    query,
-		Params: params,
+	stmt, err := streamRowsStatement(tableName, columns, filter, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	res := NewStreamResponse[map[string]interface{}]()
 
 	go func() {
-		ctx := context.Background()
-
-		it := s.client.Single().Query(ctx, stmt)
+		it := s.single().QueryWithOptions(ctx, stmt, s.queryOptions())
 		defer it.Stop()
 
 		// Iterate over the rows and construct the result
@@ -1453,13 +1487,15 @@ func (s *Client) StreamRows(ctx context.Context, tableName string, columns []str
 				return
 			}
 
-			rowMap := make(map[string]interface{})
-			for i, columnName := range row.ColumnNames() {
-				columnValue := row.ColumnValue(i)
-				rowMap[columnName] = parseStructPbValue(columnValue)
+			rowMap, err := rowToMap(row)
+			if err != nil {
+				res.setError(err)
+				return
 			}
 
-			res.addItem(&rowMap)
+			if !res.addItem(ctx, &rowMap) {
+				return
+			}
 		}
 
 		// Wait for wg
@@ -1471,13 +1507,37 @@ func (s *Client) StreamRows(ctx context.Context, tableName string, columns []str
 	return res, nil
 }
 
+// streamRowsStatement builds the statement used by StreamRows and ExportQuery.
+func streamRowsStatement(tableName string, columns []string, filter *spanner.Statement, opts *ReadOptions) (spanner.Statement, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), tableName)
+	params := map[string]interface{}{}
+	// Add filtering condition if provided
+	if filter != nil && filter.SQL != "" {
+		query += " WHERE " + filter.SQL
+		if filter.Params != nil && len(filter.Params) > 0 {
+			params = filter.Params
+		}
+	}
+	// Add sorting, limit and offset conditions if provided
+	pagination, _, err := paginationClauses(opts, 0)
+	if err != nil {
+		return spanner.Statement{}, err
+	}
+	query += pagination
+
+	return spanner.Statement{
+		SQL:    query,
+		Params: params,
+	}, nil
+}
+
 /*
 DeleteRow deletes a row from the specified table using the provided row key.
 */
 func (s *Client) DeleteRow(ctx context.Context, tableName string, rowKey spanner.Key) error {
 	_, err := s.client.Apply(ctx, []*spanner.Mutation{
 		spanner.Delete(tableName, rowKey),
-	})
+	}, s.applyOptions()...)
 	if err != nil {
 		return err
 	}
@@ -1494,7 +1554,7 @@ func (s *Client) BatchDeleteRows(ctx context.Context, tableName string, rowKeys
 		mutations = append(mutations, spanner.Delete(tableName, rowKey))
 	}
 
-	_, err := s.client.Apply(ctx, mutations)
+	_, err := s.client.Apply(ctx, mutations, s.applyOptions()...)
 	if err != nil {
 		return err
 	}
@@ -1508,7 +1568,7 @@ PurgeRows deletes all rows from the specified table.
 func (s *Client) PurgeRows(ctx context.Context, tableName string) error {
 	_, err := s.client.Apply(ctx, []*spanner.Mutation{
 		spanner.Delete(tableName, spanner.AllKeys()),
-	})
+	}, s.applyOptions()...)
 	if err != nil {
 		return err
 	}
@@ -5,10 +5,12 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/spanner"
 	spannerAdmin "cloud.google.com/go/spanner/admin/database/apiv1"
@@ -80,7 +82,10 @@ func setup() error {
 	    IsActive BOOL,
 	    CreatedAt TIMESTAMP,
 	    Metadata JSON,
-	    Data BYTES(MAX)
+	    Data BYTES(MAX),
+	    Tags ARRAY<STRING(MAX)>,
+	    Scores ARRAY<INT64>,
+	    Blobs ARRAY<BYTES(MAX)>
 	) PRIMARY KEY (Id)
 	`
 
@@ -154,7 +159,13 @@ func TestNew(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := New(tt.args.client); !reflect.DeepEqual(got, tt.want) {
+			got := New(tt.args.client)
+			if got.schemas == nil {
+				t.Errorf("New() has no schema cache")
+			}
+			// The schema cache of the shared client is populated by the other tests.
+			got.schemas = tt.want.schemas
+			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("New() = %v, want %v", got, tt.want)
 			}
 		})
@@ -228,6 +239,55 @@ func TestSortOrder_String(t *testing.T) {
 	}
 }
 
+func Test_trimPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		res       []int
+		offset    int64
+		opts      *ReadOptions
+		want      []int
+		wantToken string
+	}{
+		{
+			name: "Test_trimPage_NoOptions",
+			res:  []int{1, 2, 3},
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "Test_trimPage_ExactlyLimit",
+			res:  []int{1, 2},
+			opts: &ReadOptions{Limit: 2},
+			want: []int{1, 2},
+		},
+		{
+			name:      "Test_trimPage_MoreThanLimit",
+			res:       []int{1, 2, 3},
+			opts:      &ReadOptions{Limit: 2},
+			want:      []int{1, 2},
+			wantToken: base64.StdEncoding.EncodeToString([]byte("2")),
+		},
+		{
+			name:      "Test_trimPage_MoreThanLimitWithOffset",
+			res:       []int{1, 2, 3},
+			offset:    4,
+			opts:      &ReadOptions{Limit: 2},
+			want:      []int{1, 2},
+			wantToken: base64.StdEncoding.EncodeToString([]byte("6")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotToken := trimPage(tt.res, tt.offset, tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("trimPage() got = %v, want %v", got, tt.want)
+			}
+			if gotToken != tt.wantToken {
+				t.Errorf("trimPage() token = %v, want %v", gotToken, tt.wantToken)
+			}
+		})
+	}
+}
+
 func TestSproto_BatchInsertRows(t *testing.T) {
 	type fields struct {
 		client *spanner.Client
@@ -974,3 +1034,127 @@ func TestClient_BatchWriteProtos(t *testing.T) {
 //        INDEX_COLUMNS.COLUMN_NAME = COLUMNS.COLUMN_NAME AND TABLES.TABLE_NAME = COLUMNS.TABLE_NAME
 //				WHERE TABLES.TABLE_TYPE = 'BASE TABLE' AND INDEX_COLUMNS.INDEX_NAME = 'PRIMARY_KEY'
 //				ORDER BY TABLE_NAME ASC, INDEX_COLUMNS.ORDINAL_POSITION ASC
+
+func TestClient_WriteProtoWithColumns(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+
+	for _, column := range []string{"Id", "Data"} {
+		err := sproto.WriteProtoWithColumns(ctx, "test_table", spanner.Key{id}, "Data", &spannerAdminPb.Database{}, map[string]interface{}{column: nil})
+		if !errors.Is(err, ErrInvalidArguments{}) {
+			t.Errorf("WriteProtoWithColumns() with extra column %s error = %v, want ErrInvalidArguments", column, err)
+		}
+	}
+}
+
+func TestClient_Exists(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+	})
+
+	tests := []struct {
+		name   string
+		rowKey spanner.Key
+		want   bool
+	}{
+		{name: "Test_Exists_Existing", rowKey: spanner.Key{id}, want: true},
+		{name: "Test_Exists_Missing", rowKey: spanner.Key{id + 1}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sproto.Exists(ctx, "test_table", tt.rowKey)
+			if err != nil {
+				t.Fatalf("Exists() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Exists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Count(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	keys := []spanner.Key{{id}, {id + 1}, {id + 2}}
+	for i := range keys {
+		if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id + int64(i), "Name": "count"}); err != nil {
+			t.Fatalf("InsertRow() error = %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		_ = sproto.BatchDeleteRows(context.Background(), "test_table", keys)
+	})
+
+	got, err := sproto.Count(ctx, "test_table", &spanner.Statement{
+		SQL:    "Id >= @from AND Name = @name",
+		Params: map[string]interface{}{"from": id + 1, "name": "count"},
+	})
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("Count() = %v, want %v", got, 2)
+	}
+
+	all, err := sproto.Count(ctx, "test_table", nil)
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if all < 3 {
+		t.Errorf("Count() without filter = %v, want at least %v", all, 3)
+	}
+}
+
+func TestClient_StreamRows_Cancel(t *testing.T) {
+	id := time.Now().UnixNano()
+	keys := []spanner.Key{{id}, {id + 1}, {id + 2}}
+	for i := range keys {
+		if err := sproto.InsertRow(context.Background(), "test_table", map[string]interface{}{"Id": id + int64(i)}); err != nil {
+			t.Fatalf("InsertRow() error = %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		_ = sproto.BatchDeleteRows(context.Background(), "test_table", keys)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := sproto.StreamRows(ctx, "test_table", []string{"Id"}, &spanner.Statement{
+		SQL:    "Id >= @from",
+		Params: map[string]interface{}{"from": id},
+	}, nil)
+	if err != nil {
+		t.Fatalf("StreamRows() error = %v", err)
+	}
+	if _, err := stream.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	// Cancelling mid-stream stops the read, so the stream ends with an error rather than io.EOF. The stream is not read
+	// for a moment, so that the reading goroutine observes the cancellation while waiting for the next item to be read.
+	cancel()
+	time.Sleep(time.Second)
+	done := make(chan error, 1)
+	go func() {
+		for {
+			if _, err := stream.Next(); err != nil {
+				done <- err
+				return
+			}
+		}
+	}()
+	select {
+	case err := <-done:
+		if errors.Is(err, io.EOF) {
+			t.Errorf("Next() error = %v, want the cancellation error", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("the stream was not closed after the context was cancelled")
+	}
+}
@@ -0,0 +1,27 @@
+package sproto
+
+import (
+	"cloud.google.com/go/spanner"
+)
+
+/*
+WithTimestampBound returns a copy of the client whose reads use the provided timestamp bound instead of a strong read,
+for example to serve low-latency reads from the nearest replica, or to read the data as of a point in time:
+
+	// Read data at most 15 seconds old, from the nearest replica able to serve it
+	err := client.WithTimestampBound(spanner.MaxStaleness(15*time.Second)).ReadProto(ctx, ...)
+
+	// Read the data as it was at the provided time
+	rows, _, err := client.WithTimestampBound(spanner.ReadTimestamp(t)).QueryRows(ctx, ...)
+
+The bound applies to all the reads of the copy, i.e. ReadProto, ListProtos, QueryRows and the other read methods,
+while writes are unaffected. Use spanner.StrongRead() to get back to the default. The copy shares the underlying
+spanner.Client, so it is cheap to create one per request.
+
+Bounded staleness, i.e. spanner.MaxStaleness and spanner.MinReadTimestamp, is not supported by ReadOnlyTransaction.
+*/
+func (s *Client) WithTimestampBound(bound spanner.TimestampBound) *Client {
+	bounded := *s
+	bounded.timestampBound = &bound
+	return &bounded
+}
@@ -0,0 +1,48 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+func TestClient_WithTimestampBound(t *testing.T) {
+	c := &Client{}
+	bounded := c.WithTimestampBound(spanner.MaxStaleness(15 * time.Second))
+
+	if bounded == c {
+		t.Fatalf("WithTimestampBound() returned the same client, want a copy")
+	}
+	if bounded.timestampBound == nil || bounded.timestampBound.String() != spanner.MaxStaleness(15*time.Second).String() {
+		t.Errorf("WithTimestampBound() bound = %v, want the provided bound", bounded.timestampBound)
+	}
+	if c.timestampBound != nil {
+		t.Errorf("original timestampBound = %v, want nil", c.timestampBound)
+	}
+	if got, _ := bounded.snapshot(); got != bounded {
+		t.Errorf("snapshot() returned a new client, want the bounded client as is")
+	}
+}
+
+func TestClient_ReadProto_ReadTimestamp(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	before := time.Now()
+	t.Cleanup(func() {
+		_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+	})
+
+	if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+
+	if _, err := sproto.WithTimestampBound(spanner.ReadTimestamp(before)).ReadRow(ctx, "test_table", spanner.Key{id}, []string{"Id"}, nil); !errors.Is(err, ErrNotFound{}) {
+		t.Errorf("ReadRow() before the insert error = %v, want ErrNotFound", err)
+	}
+	if _, err := sproto.WithTimestampBound(spanner.StrongRead()).ReadRow(ctx, "test_table", spanner.Key{id}, []string{"Id"}, nil); err != nil {
+		t.Errorf("ReadRow() with a strong read error = %v", err)
+	}
+}
@@ -28,6 +28,11 @@ type TableClient struct {
 	msgTypeToColumn   map[string]string
 	primaryKeyColumns []*primaryKeyColumn
 	defaultLimit      int
+	mutationLimiter   mutationLimiter
+	migrator          MessageMigrator
+	requestOptions    RequestOptions
+	// columns, if set, resolves the snake_case names of the sort columns to the column names.
+	columns *columnResolver
 }
 
 /*
@@ -68,16 +73,19 @@ type StreamOptions struct {
 /*
 NewClient creates a new Database Client instance with the provided Google Cloud Spanner configuration.
 Leave databaseRole empty if you are not using fine grained roles on the database.
+Only the WithSessionPool and WithNumChannels options apply, use TableClientOption to configure the table clients.
 */
-func NewDbClient(googleProject, spannerInstance, databaseName, databaseRole string) (*DbClient, error) {
+func NewDbClient(googleProject, spannerInstance, databaseName, databaseRole string, opts ...ClientOption) (*DbClient, error) {
 	ctx := context.Background()
-	clientConfig := spanner.ClientConfig{
-		DisableNativeMetrics: true,
+	options := &ClientOptions{}
+	for _, opt := range opts {
+		opt(options)
 	}
-	if databaseRole != "" {
-		clientConfig.DatabaseRole = databaseRole
+	config, err := clientConfig(databaseRole, options)
+	if err != nil {
+		return nil, err
 	}
-	spannerClient, err := spanner.NewClientWithConfig(ctx, fmt.Sprintf("projects/%s/instances/%s/databases/%s", googleProject, spannerInstance, databaseName), clientConfig)
+	spannerClient, err := spanner.NewClientWithConfig(ctx, fmt.Sprintf("projects/%s/instances/%s/databases/%s", googleProject, spannerInstance, databaseName), config)
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +98,10 @@ func NewDbClient(googleProject, spannerInstance, databaseName, databaseRole stri
 type TableClientOptions struct {
 	primaryKeyColumns []*primaryKeyColumn
 	msgTypeToColumn   map[string]string
+	mutationLimiter   mutationLimiter
+	migrator          MessageMigrator
+	requestOptions    RequestOptions
+	snakeCaseColumns  bool
 }
 
 type TableClientOption func(*TableClientOptions)
@@ -106,12 +118,63 @@ func WithMsgTypeToColumnMap(msgTypeToColumn map[string]string) TableClientOption
 	}
 }
 
+/*
+WithTableMutationLimit sets the maximum number of mutations the batch methods of the table client may commit at once,
+and the behavior when a batch exceeds it.
+
+The limit defaults to DefaultMutationLimit and the behavior to MutationLimitError.
+Lower the limit if the table has secondary indexes, since mutations on indexes also count towards the Spanner limit.
+*/
+func WithTableMutationLimit(limit int, behavior MutationLimitBehavior) TableClientOption {
+	return func(o *TableClientOptions) {
+		o.mutationLimiter = mutationLimiter{
+			limit:    limit,
+			behavior: behavior,
+		}
+	}
+}
+
+/*
+WithTableMessageMigrator sets a function which the read methods of the table client apply to every proto message read,
+right after it is unmarshalled and before any read mask is applied. See MessageMigrator.
+*/
+func WithTableMessageMigrator(migrator MessageMigrator) TableClientOption {
+	return func(o *TableClientOptions) {
+		o.migrator = migrator
+	}
+}
+
+/*
+WithTableRequestOptions sets the tags attached to all the requests made by the table client. See RequestOptions.
+*/
+func WithTableRequestOptions(opts RequestOptions) TableClientOption {
+	return func(o *TableClientOptions) {
+		o.requestOptions = opts
+	}
+}
+
+/*
+WithSnakeCaseColumns lets the callers of the table client refer to the columns by their snake_case names, for example
+portfolio_name for a PortfolioName column, so that the API field names can be used as is.
+
+The column names are discovered from the table schema when creating the table client, and the sort columns of the
+query options are resolved against them. Names which do not match any column are rejected with an ErrInvalidArguments
+error. Use ColumnName to resolve the column names used in filters.
+*/
+func WithSnakeCaseColumns() TableClientOption {
+	return func(o *TableClientOptions) {
+		o.snakeCaseColumns = true
+	}
+}
+
 // NewTableClient creates a new Table Client instance with the provided table name.
 // During setup, it queries the table to get the primary key columns and the mapping of proto message types to columns.
 // The defaultQueryRowLimit is used as the default limit for queries if not provided in the QueryOptions.
 func (d *DbClient) NewTableClient(tableName string, defaultQueryRowLimit int, tableClientOptions ...TableClientOption) (*TableClient, error) {
 	ctx := context.Background()
-	opts := &TableClientOptions{}
+	opts := &TableClientOptions{
+		mutationLimiter: defaultMutationLimiter(),
+	}
 	for _, opt := range tableClientOptions {
 		opt(opts)
 	}
@@ -119,8 +182,9 @@ func (d *DbClient) NewTableClient(tableName string, defaultQueryRowLimit int, ta
 	// use go routines
 	pkCols := opts.primaryKeyColumns
 	msgTypeToColumn := opts.msgTypeToColumn
+	var columns *columnResolver
 	wg := sync.WaitGroup{}
-	errChannel := make(chan error, 2)
+	errChannel := make(chan error, 3)
 	if opts.primaryKeyColumns == nil {
 		wg.Add(1)
 		go func() {
@@ -143,6 +207,18 @@ func (d *DbClient) NewTableClient(tableName string, defaultQueryRowLimit int, ta
 			}
 		}()
 	}
+	if opts.snakeCaseColumns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			columnNames, err := getColumnNames(ctx, d.client, tableName)
+			if err != nil {
+				errChannel <- fmt.Errorf("Error getting column names for table %s: %v", tableName, err)
+				return
+			}
+			columns = newColumnResolver(tableName, columnNames)
+		}()
+	}
 	wg.Wait()
 	close(errChannel)
 	for err := range errChannel {
@@ -155,6 +231,10 @@ func (d *DbClient) NewTableClient(tableName string, defaultQueryRowLimit int, ta
 		primaryKeyColumns: pkCols,
 		msgTypeToColumn:   msgTypeToColumn,
 		defaultLimit:      defaultQueryRowLimit,
+		mutationLimiter:   opts.mutationLimiter,
+		migrator:          opts.migrator,
+		requestOptions:    opts.requestOptions,
+		columns:           columns,
 	}, nil
 }
 
@@ -173,6 +253,66 @@ func (t *TableClient) getColNames(messages []proto.Message) ([]string, error) {
 	return colNames, nil
 }
 
+/*
+mutationColumns resolves the columns and values to write for the provided row.
+
+Generated and stored primary key columns are skipped, as are columns with a default value, e.g. keys populated from a
+sequence, for which no value is provided. Each message is mapped to its PROTO column.
+This method may return a ErrInvalidArguments error if the row key length does not match the primary key columns length,
+or if the message type is not found in the table schema.
+*/
+func (t *TableClient) mutationColumns(row *Row) ([]string, []interface{}, error) {
+	// Get the row key values using the length
+	keyValues := make([]interface{}, len(row.Key))
+	copy(keyValues, row.Key)
+	if len(t.primaryKeyColumns) != len(keyValues) {
+		return nil, nil, ErrInvalidArguments{
+			err:    fmt.Errorf("row key length does not match the primary key columns length"),
+			fields: []string{"rowKey"},
+		}
+	}
+
+	// Construct columns and values from the provided row
+	maxNrValues := len(keyValues) + len(row.Messages)
+	columns := make([]string, 0, maxNrValues)
+	values := make([]interface{}, 0, maxNrValues)
+	for i, keyCol := range t.primaryKeyColumns {
+		if keyCol.isGenerated || keyCol.isStored {
+			continue
+		}
+		if keyCol.hasDefault && keyValues[i] == nil {
+			continue
+		}
+		columns = append(columns, keyCol.columnName)
+		values = append(values, keyValues[i])
+	}
+
+	for _, message := range row.Messages {
+		columnName, ok := t.msgTypeToColumn[string(proto.MessageName(message))]
+		if !ok {
+			return nil, nil, ErrInvalidArguments{
+				err:    fmt.Errorf("message type %s not found in table %s", proto.MessageName(message), t.tableName),
+				fields: []string{"messages"},
+			}
+		}
+		columns = append(columns, columnName)
+		values = append(values, message)
+	}
+
+	return columns, values, nil
+}
+
+/*
+ColumnName returns the column matching the provided name, which may be the snake_case form of the column name if the
+table client was created with WithSnakeCaseColumns, for example when building filters from API field names.
+An ErrInvalidArguments error is returned if the name does not match any column.
+
+Without WithSnakeCaseColumns, the name is returned unchanged.
+*/
+func (t *TableClient) ColumnName(name string) (string, error) {
+	return t.columns.resolve(name)
+}
+
 /*
 Client returns the underlying spanner.Client instance.
 This client can be used to perform custom queries and mutations.
@@ -207,59 +347,131 @@ BatchCreate creates multiple rows in the table with the provided row keys and pr
 This method may return a ErrInvalidArguments error if the row key length does not match the primary key columns length,
 or if the message type is not found in the table schema.
 It may also return a ErrAlreadyExists error if any of the rows already exist in the table.
+It may also return a ErrMutationLimitExceeded error if the rows exceed the mutation limit of a single commit, see WithTableMutationLimit.
 */
 func (t *TableClient) BatchCreate(ctx context.Context, rows []*Row) error {
 	mutations := make([]*spanner.Mutation, len(rows))
+	counts := make([]int, len(rows))
 	for i, row := range rows {
-		keyValues := make([]interface{}, len(row.Key))
-		copy(keyValues, row.Key)
-		if len(t.primaryKeyColumns) != len(keyValues) {
-			return ErrInvalidArguments{
-				err:    fmt.Errorf("row key length does not match the primary key columns length"),
-				fields: []string{"rowKey"},
-			}
+		columns, values, err := t.mutationColumns(row)
+		if err != nil {
+			return err
 		}
 
-		// Construct columns and values from the provided row
-		maxNrValues := len(keyValues) + len(row.Messages)
-		columns := make([]string, 0, maxNrValues)
-		values := make([]interface{}, 0, maxNrValues)
-		for i, keyCol := range t.primaryKeyColumns {
-			if keyCol.isGenerated || keyCol.isStored {
-				continue
+		mutations[i] = spanner.Insert(t.tableName, columns, values)
+		counts[i] = len(columns)
+	}
+
+	err := t.mutationLimiter.apply(ctx, t.db.client, mutations, counts, t.applyOptions()...)
+	if err != nil {
+		switch spanner.ErrCode(err) {
+		case codes.AlreadyExists:
+			return ErrAlreadyExists{
+				err: err,
 			}
-			columns = append(columns, keyCol.columnName)
-			values = append(values, keyValues[i])
 		}
 
-		for _, message := range row.Messages {
-			columnName, ok := t.msgTypeToColumn[string(proto.MessageName(message))]
-			if !ok {
-				return ErrInvalidArguments{
-					err:    fmt.Errorf("message type %s not found in table %s", proto.MessageName(message), t.tableName),
-					fields: []string{"messages"},
-				}
-			}
-			columns = append(columns, columnName)
-			values = append(values, message)
+		return err
+	}
+
+	return nil
+}
+
+/*
+CreateReturningKey creates a new row in the table with the provided row key and proto messages and returns the
+primary key that was assigned to the row.
+
+This is useful for tables where one or more of the primary key columns are generated by Spanner, in which case the
+caller cannot know the final key before the row is inserted. The row is inserted with a DML statement and the
+generated key is read back within the same read-write transaction.
+
+The row key must still match the length of the primary key columns, but values for generated columns are ignored
+and can be left nil. Values for key columns with a default, e.g. GET_NEXT_SEQUENCE_VALUE, can also be left nil to
+have Spanner assign them. If the table has no generated primary key columns, the provided row key is returned as is.
+
+This method may return a ErrInvalidArguments error if the row key length does not match the primary key columns length,
+or if the message type is not found in the table schema.
+It may also return a ErrAlreadyExists error if the row already exists in the table.
+*/
+func (t *TableClient) CreateReturningKey(ctx context.Context, rowKey spanner.Key, messages ...proto.Message) (spanner.Key, error) {
+	// Fall back to a regular create if none of the primary key columns are generated
+	if !t.hasGeneratedPrimaryKey() {
+		if err := t.Create(ctx, rowKey, messages...); err != nil {
+			return nil, err
 		}
+		return rowKey, nil
+	}
 
-		mutations[i] = spanner.Insert(t.tableName, columns, values)
+	columns, values, err := t.mutationColumns(&Row{Key: rowKey, Messages: messages})
+	if err != nil {
+		return nil, err
 	}
 
-	_, err := t.db.client.Apply(ctx, mutations)
+	// Construct the INSERT ... THEN RETURN statement
+	params := make(map[string]interface{}, len(values))
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		paramName := fmt.Sprintf("p%d", i)
+		params[paramName] = value
+		placeholders[i] = "@" + paramName
+	}
+	wrappedColumns := utils.Transform(columns, func(colName string) string {
+		return fmt.Sprintf("`%s`", colName)
+	})
+	wrappedKeyColumns := utils.Transform(t.primaryKeyColumns, func(col *primaryKeyColumn) string {
+		return fmt.Sprintf("`%s`", col.columnName)
+	})
+	stmt := spanner.Statement{
+		SQL: fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) THEN RETURN %s",
+			t.tableName, strings.Join(wrappedColumns, ", "), strings.Join(placeholders, ", "), strings.Join(wrappedKeyColumns, ", ")),
+		Params: params,
+	}
+
+	var generatedKey spanner.Key
+	_, err = t.db.client.ReadWriteTransactionWithOptions(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		it := txn.QueryWithOptions(ctx, stmt, t.queryOptions())
+		defer it.Stop()
+
+		row, err := it.Next()
+		if err != nil {
+			return err
+		}
+
+		key := make(spanner.Key, len(t.primaryKeyColumns))
+		for i := range t.primaryKeyColumns {
+			value, err := decodeKeyColumn(row, i)
+			if err != nil {
+				return err
+			}
+			key[i] = value
+		}
+		generatedKey = key
+
+		return nil
+	}, t.transactionOptions())
 	if err != nil {
 		switch spanner.ErrCode(err) {
 		case codes.AlreadyExists:
-			return ErrAlreadyExists{
+			return nil, ErrAlreadyExists{
 				err: err,
 			}
 		}
 
-		return err
+		return nil, err
 	}
 
-	return nil
+	return generatedKey, nil
+}
+
+// hasGeneratedPrimaryKey returns true if any of the primary key columns of the table are generated by Spanner, including
+// keys populated from a sequence or another default value.
+func (t *TableClient) hasGeneratedPrimaryKey() bool {
+	for _, col := range t.primaryKeyColumns {
+		if col.isGenerated || col.hasDefault {
+			return true
+		}
+	}
+	return false
 }
 
 /*
@@ -286,47 +498,22 @@ BatchUpdate updates multiple rows in the table with the provided row keys and pr
 This method may return a ErrInvalidArguments error if the row key length does not match the primary key columns length,
 or if the message type is not found in the table schema.
 It may also return a ErrNotFound error if any of the rows do not exist in the table.
+It may also return a ErrMutationLimitExceeded error if the rows exceed the mutation limit of a single commit, see WithTableMutationLimit.
 */
 func (t *TableClient) BatchUpdate(ctx context.Context, rows []*Row) error {
 	mutations := make([]*spanner.Mutation, len(rows))
+	counts := make([]int, len(rows))
 	for i, row := range rows {
-		keyValues := make([]interface{}, len(row.Key))
-		copy(keyValues, row.Key)
-		if len(t.primaryKeyColumns) != len(keyValues) {
-			return ErrInvalidArguments{
-				err:    fmt.Errorf("row key length does not match the primary key columns length"),
-				fields: []string{"rowKey"},
-			}
-		}
-
-		// Construct columns and values from the provided row
-		maxNrValues := len(keyValues) + len(row.Messages)
-		columns := make([]string, 0, maxNrValues)
-		values := make([]interface{}, 0, maxNrValues)
-		for i, keyCol := range t.primaryKeyColumns {
-			if keyCol.isGenerated || keyCol.isStored {
-				continue
-			}
-			columns = append(columns, keyCol.columnName)
-			values = append(values, keyValues[i])
-		}
-
-		for _, message := range row.Messages {
-			columnName, ok := t.msgTypeToColumn[string(proto.MessageName(message))]
-			if !ok {
-				return ErrInvalidArguments{
-					err:    fmt.Errorf("message type %s not found in table %s", proto.MessageName(message), t.tableName),
-					fields: []string{"messages"},
-				}
-			}
-			columns = append(columns, columnName)
-			values = append(values, message)
+		columns, values, err := t.mutationColumns(row)
+		if err != nil {
+			return err
 		}
 
 		mutations[i] = spanner.Update(t.tableName, columns, values)
+		counts[i] = len(columns)
 	}
 
-	_, err := t.db.client.Apply(ctx, mutations)
+	err := t.mutationLimiter.apply(ctx, t.db.client, mutations, counts, t.applyOptions()...)
 	if err != nil {
 		switch spanner.ErrCode(err) {
 		case codes.NotFound:
@@ -367,50 +554,23 @@ The main difference between BatchWrite and BatchCreate is that BatchWrite will u
 
 This method may return a ErrInvalidArguments error if the row key length does not match the primary key columns length,
 or if the message type is not found in the table schema.
+It may also return a ErrMutationLimitExceeded error if the rows exceed the mutation limit of a single commit, see WithTableMutationLimit.
 */
 func (t *TableClient) BatchWrite(ctx context.Context, rows []*Row) error {
 	var mutations []*spanner.Mutation
+	var counts []int
 	for _, row := range rows {
-
-		// Get the row key values using the length
-		keyValues := make([]interface{}, len(row.Key))
-		copy(keyValues, row.Key)
-		if len(t.primaryKeyColumns) != len(keyValues) {
-			return ErrInvalidArguments{
-				err:    fmt.Errorf("row key length does not match the primary key columns length"),
-				fields: []string{"rowKey"},
-			}
-		}
-
-		// Construct columns and values from the provided row
-		maxNrValues := len(keyValues) + len(row.Messages)
-		columns := make([]string, 0, maxNrValues)
-		values := make([]interface{}, 0, maxNrValues)
-		for i, keyCol := range t.primaryKeyColumns {
-			if keyCol.isGenerated || keyCol.isStored {
-				continue
-			}
-			columns = append(columns, keyCol.columnName)
-			values = append(values, keyValues[i])
-		}
-
-		for _, message := range row.Messages {
-			columnName, ok := t.msgTypeToColumn[string(proto.MessageName(message))]
-			if !ok {
-				return ErrInvalidArguments{
-					err:    fmt.Errorf("message type %s not found in table %s", proto.MessageName(message), t.tableName),
-					fields: []string{"messages"},
-				}
-			}
-			columns = append(columns, columnName)
-			values = append(values, message)
+		columns, values, err := t.mutationColumns(row)
+		if err != nil {
+			return err
 		}
 
 		mutations = append(mutations, spanner.InsertOrUpdate(t.tableName, columns, values))
+		counts = append(counts, len(columns))
 	}
 
 	// Apply the mutations
-	_, err := t.db.client.Apply(ctx, mutations)
+	err := t.mutationLimiter.apply(ctx, t.db.client, mutations, counts, t.applyOptions()...)
 	if err != nil {
 		switch spanner.ErrCode(err) {
 		case codes.AlreadyExists:
@@ -454,6 +614,16 @@ This method may return a ErrNotFound error if the row does not exist in the tabl
 It may also return a ErrInvalidFieldMask if an invalid field mask is provided
 */
 func (t *TableClient) ReadWithFieldMask(ctx context.Context, rowKey spanner.Key, messages []proto.Message, readMasks []*fieldmaskpb.FieldMask) error {
+	return t.readWithFieldMask(ctx, t.db.client.Single(), rowKey, messages, readMasks)
+}
+
+// rowReader is implemented by both read-only and read-write transactions.
+type rowReader interface {
+	ReadRowWithOptions(ctx context.Context, table string, key spanner.Key, columns []string, opts *spanner.ReadOptions) (*spanner.Row, error)
+}
+
+// readWithFieldMask reads a single row using the provided transaction. See ReadWithFieldMask for details.
+func (t *TableClient) readWithFieldMask(ctx context.Context, txn rowReader, rowKey spanner.Key, messages []proto.Message, readMasks []*fieldmaskpb.FieldMask) error {
 	// Get columns
 	colNames, err := t.getColNames(messages)
 	if err != nil {
@@ -461,7 +631,7 @@ func (t *TableClient) ReadWithFieldMask(ctx context.Context, rowKey spanner.Key,
 	}
 
 	// Read the proto message from the specified table
-	row, err := t.db.client.Single().ReadRow(ctx, t.tableName, rowKey, colNames)
+	row, err := txn.ReadRowWithOptions(ctx, t.tableName, rowKey, colNames, t.readOptions())
 	if err != nil {
 		if spanner.ErrCode(err) == codes.NotFound {
 			return ErrNotFound{
@@ -480,7 +650,7 @@ func (t *TableClient) ReadWithFieldMask(ctx context.Context, rowKey spanner.Key,
 		if err != nil {
 			return err
 		}
-		err = proto.Unmarshal(bytes, message)
+		err = t.unmarshal(bytes, message)
 		if err != nil {
 			return err
 		}
@@ -559,7 +729,7 @@ func (t *TableClient) BatchReadWithFieldMask(ctx context.Context, rowKeys []span
 	columns = append(columns, cols...)
 
 	// Read the rows from the specified table
-	it := t.db.client.Single().Read(ctx, t.tableName, spanner.KeySets(keySets...), columns)
+	it := t.db.client.Single().ReadWithOptions(ctx, t.tableName, spanner.KeySets(keySets...), columns, t.readOptions())
 	defer it.Stop()
 
 	// Iterate over the rows and construct the result
@@ -592,7 +762,7 @@ func (t *TableClient) BatchReadWithFieldMask(ctx context.Context, rowKeys []span
 
 			// Unmarshal the bytes into the provided proto message
 			newMessage := newEmptyMessage(messages[i])
-			err = proto.Unmarshal(dataBytes, newMessage)
+			err = t.unmarshal(dataBytes, newMessage)
 			if err != nil {
 				return nil, err
 			}
@@ -642,7 +812,7 @@ func (t *TableClient) BatchDelete(ctx context.Context, rowKeys []spanner.Key) er
 		mutations[i] = spanner.Delete(t.tableName, key)
 	}
 
-	_, err := t.db.client.Apply(ctx, mutations)
+	_, err := t.db.client.Apply(ctx, mutations, t.applyOptions()...)
 	if err != nil {
 		return err
 	}
@@ -680,8 +850,12 @@ func (t *TableClient) Query(ctx context.Context, messages []proto.Message, filte
 	if opts != nil && opts.SortColumns != nil && len(opts.SortColumns) > 0 {
 		query += " ORDER BY "
 
-		sortColumns := make([]string, 0, len(opts.SortColumns))
-		for column, order := range opts.SortColumns {
+		resolvedSortColumns, err := t.columns.resolveSortColumns(opts.SortColumns)
+		if err != nil {
+			return nil, "", err
+		}
+		sortColumns := make([]string, 0, len(resolvedSortColumns))
+		for column, order := range resolvedSortColumns {
 			sortColumns = append(sortColumns, fmt.Sprintf("%s %s", column, order.String()))
 		}
 
@@ -728,7 +902,7 @@ func (t *TableClient) Query(ctx context.Context, messages []proto.Message, filte
 		Params: params,
 	}
 
-	it := t.db.client.Single().Query(ctx, stmt)
+	it := t.db.client.Single().QueryWithOptions(ctx, stmt, t.queryOptions())
 	defer it.Stop()
 
 	// Iterate over the rows and construct the result
@@ -752,7 +926,7 @@ func (t *TableClient) Query(ctx context.Context, messages []proto.Message, filte
 
 			// Unmarshal the bytes into the provided proto message
 			newMessage := newEmptyMessage(messages[i])
-			err = proto.Unmarshal(dataBytes, newMessage)
+			err = t.unmarshal(dataBytes, newMessage)
 			if err != nil {
 				return nil, "", err
 			}
@@ -821,8 +995,12 @@ func (t *TableClient) Stream(ctx context.Context, messages []proto.Message, filt
 	if opts != nil && opts.SortColumns != nil && len(opts.SortColumns) > 0 {
 		query += " ORDER BY "
 
-		sortColumns := make([]string, 0, len(opts.SortColumns))
-		for column, order := range opts.SortColumns {
+		resolvedSortColumns, err := t.columns.resolveSortColumns(opts.SortColumns)
+		if err != nil {
+			return nil, err
+		}
+		sortColumns := make([]string, 0, len(resolvedSortColumns))
+		for column, order := range resolvedSortColumns {
 			sortColumns = append(sortColumns, fmt.Sprintf("%s %s", column, order.String()))
 		}
 
@@ -852,7 +1030,7 @@ func (t *TableClient) Stream(ctx context.Context, messages []proto.Message, filt
 
 	res := NewStreamResponse[Row]()
 	go func() {
-		it := t.db.client.Single().Query(ctx, stmt)
+		it := t.db.client.Single().QueryWithOptions(ctx, stmt, t.queryOptions())
 		defer it.Stop()
 
 		// Iterate over the rows and send the results
@@ -877,7 +1055,7 @@ func (t *TableClient) Stream(ctx context.Context, messages []proto.Message, filt
 
 				// Unmarshal the bytes into the provided proto message
 				newMessage := newEmptyMessage(messages[i])
-				err = proto.Unmarshal(dataBytes, newMessage)
+				err = t.unmarshal(dataBytes, newMessage)
 				if err != nil {
 					res.setError(err)
 					return
@@ -900,7 +1078,9 @@ func (t *TableClient) Stream(ctx context.Context, messages []proto.Message, filt
 				r.Messages[i] = newMessage
 			}
 
-			res.addItem(r)
+			if !res.addItem(ctx, r) {
+				return
+			}
 		}
 
 		// Wait for wg
@@ -0,0 +1,132 @@
+package sproto
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// The tests in this file expect the following tables to exist in the test database:
+//
+//	CREATE PROTO BUNDLE (google.protobuf.StringValue);
+//
+//	CREATE TABLE test_generated_key (
+//	    key STRING(MAX) AS (StringValue.value) STORED,
+//	    StringValue google.protobuf.StringValue,
+//	) PRIMARY KEY (key);
+//
+//	CREATE SEQUENCE test_sequence_key_seq OPTIONS (sequence_kind = 'bit_reversed_positive');
+//
+//	CREATE TABLE test_sequence_key (
+//	    id INT64 DEFAULT (GET_NEXT_SEQUENCE_VALUE(SEQUENCE test_sequence_key_seq)),
+//	    StringValue google.protobuf.StringValue,
+//	) PRIMARY KEY (id);
+func newTestDbClient() *DbClient {
+	return &DbClient{
+		client: sproto.client,
+	}
+}
+
+func TestTableClient_CreateReturningKey(t *testing.T) {
+	tbl, err := newTestDbClient().NewTableClient("test_generated_key", 100)
+	if err != nil {
+		t.Fatalf("NewTableClient() error = %v", err)
+	}
+
+	value := fmt.Sprintf("generated-%d", time.Now().UnixNano())
+
+	type args struct {
+		ctx      context.Context
+		rowKey   spanner.Key
+		messages []proto.Message
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    spanner.Key
+		wantErr bool
+	}{
+		{
+			name: "Test_CreateReturningKey_GeneratedKey",
+			args: args{
+				ctx:      context.Background(),
+				rowKey:   spanner.Key{nil},
+				messages: []proto.Message{wrapperspb.String(value)},
+			},
+			want:    spanner.Key{value},
+			wantErr: false,
+		},
+		{
+			name: "Test_CreateReturningKey_AlreadyExists",
+			args: args{
+				ctx:      context.Background(),
+				rowKey:   spanner.Key{nil},
+				messages: []proto.Message{wrapperspb.String(value)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test_CreateReturningKey_InvalidKeyLength",
+			args: args{
+				ctx:      context.Background(),
+				rowKey:   spanner.Key{nil, nil},
+				messages: []proto.Message{wrapperspb.String(value)},
+			},
+			wantErr: true,
+		},
+	}
+	t.Cleanup(func() {
+		_ = tbl.Delete(context.Background(), spanner.Key{value})
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tbl.CreateReturningKey(tt.args.ctx, tt.args.rowKey, tt.args.messages...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateReturningKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateReturningKey() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableClient_CreateReturningKey_Sequence(t *testing.T) {
+	tbl, err := newTestDbClient().NewTableClient("test_sequence_key", 100)
+	if err != nil {
+		t.Fatalf("NewTableClient() error = %v", err)
+	}
+	if !tbl.hasGeneratedPrimaryKey() {
+		t.Fatalf("hasGeneratedPrimaryKey() = false, want true for a key populated from a sequence")
+	}
+
+	got, err := tbl.CreateReturningKey(context.Background(), spanner.Key{nil}, wrapperspb.String("sequence"))
+	if err != nil {
+		t.Fatalf("CreateReturningKey() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("CreateReturningKey() got = %v, want a single key column", got)
+	}
+	id, ok := got[0].(int64)
+	if !ok || id <= 0 {
+		t.Fatalf("CreateReturningKey() got = %v, want a positive INT64 from the sequence", got)
+	}
+	t.Cleanup(func() {
+		_ = tbl.Delete(context.Background(), got)
+	})
+
+	value := &wrapperspb.StringValue{}
+	if err := tbl.Read(context.Background(), got, value); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if value.GetValue() != "sequence" {
+		t.Errorf("Read() got = %v, want %v", value.GetValue(), "sequence")
+	}
+}
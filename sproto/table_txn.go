@@ -0,0 +1,137 @@
+package sproto
+
+import (
+	"context"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+/*
+TxTableClient provides read and write methods on a table which all participate in the same read-write transaction.
+
+Reads observe the writes committed before the transaction, but not the writes made using the TxTableClient itself,
+since these are buffered and only applied when the transaction commits.
+
+A TxTableClient is only valid within the function passed to TableClient.RunInTransaction.
+*/
+type TxTableClient struct {
+	table *TableClient
+	txn   *spanner.ReadWriteTransaction
+}
+
+/*
+RunInTransaction runs fn within a single read-write transaction, so that the reads and writes made using the provided
+TxTableClient are applied atomically. This allows a row to be read and conditionally written, without another writer
+changing it in between:
+
+	err := tbl.RunInTransaction(ctx, func(ctx context.Context, tx *sproto.TxTableClient) error {
+		book := &pb.Book{}
+		if err := tx.Read(ctx, spanner.Key{id}, book); err != nil {
+			return err
+		}
+		if book.GetEtag() != etag {
+			return status.Error(codes.FailedPrecondition, "etag mismatch")
+		}
+		book.Etag = newEtag()
+		return tx.Update(ctx, spanner.Key{id}, book)
+	})
+
+The transaction is retried if it is aborted by Spanner, fn may therefore be called multiple times and should not have
+any side effects other than the ones made using the TxTableClient. The sproto errors returned by fn, such as
+ErrNotFound, are returned as is, and any error returned by fn rolls back the transaction.
+
+This method may return a ErrAlreadyExists or ErrNotFound error if a row written using Create or Update respectively
+already exists or does not exist when the transaction commits.
+*/
+func (t *TableClient) RunInTransaction(ctx context.Context, fn func(ctx context.Context, tx *TxTableClient) error) error {
+	_, err := t.db.client.ReadWriteTransactionWithOptions(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		return fn(ctx, &TxTableClient{
+			table: t,
+			txn:   txn,
+		})
+	}, t.transactionOptions())
+	if err != nil {
+		return transactionError(err)
+	}
+
+	return nil
+}
+
+/*
+Read reads a single row within the transaction. See TableClient.Read for details.
+*/
+func (tx *TxTableClient) Read(ctx context.Context, rowKey spanner.Key, messages ...proto.Message) error {
+	return tx.table.readWithFieldMask(ctx, tx.txn, rowKey, messages, nil)
+}
+
+/*
+ReadWithFieldMask reads a single row within the transaction and applies the provided read masks.
+See TableClient.ReadWithFieldMask for details.
+*/
+func (tx *TxTableClient) ReadWithFieldMask(ctx context.Context, rowKey spanner.Key, messages []proto.Message, readMasks []*fieldmaskpb.FieldMask) error {
+	return tx.table.readWithFieldMask(ctx, tx.txn, rowKey, messages, readMasks)
+}
+
+/*
+Create buffers the creation of a new row, which is applied when the transaction commits. See TableClient.Create for
+details.
+
+This method may return a ErrInvalidArguments error if the row key length does not match the primary key columns length,
+or if the message type is not found in the table schema.
+*/
+func (tx *TxTableClient) Create(ctx context.Context, rowKey spanner.Key, messages ...proto.Message) error {
+	return tx.bufferWrite(spanner.Insert, rowKey, messages)
+}
+
+/*
+Update buffers the update of an existing row, which is applied when the transaction commits. See TableClient.Update
+for details.
+
+This method may return a ErrInvalidArguments error if the row key length does not match the primary key columns length,
+or if the message type is not found in the table schema.
+*/
+func (tx *TxTableClient) Update(ctx context.Context, rowKey spanner.Key, messages ...proto.Message) error {
+	return tx.bufferWrite(spanner.Update, rowKey, messages)
+}
+
+/*
+Write buffers the creation or update of a row, which is applied when the transaction commits. See TableClient.Write
+for details.
+
+This method may return a ErrInvalidArguments error if the row key length does not match the primary key columns length,
+or if the message type is not found in the table schema.
+*/
+func (tx *TxTableClient) Write(ctx context.Context, rowKey spanner.Key, messages ...proto.Message) error {
+	return tx.bufferWrite(spanner.InsertOrUpdate, rowKey, messages)
+}
+
+/*
+Delete buffers the deletion of a row, which is applied when the transaction commits. See TableClient.Delete for
+details.
+*/
+func (tx *TxTableClient) Delete(ctx context.Context, rowKey spanner.Key) error {
+	return tx.txn.BufferWrite([]*spanner.Mutation{spanner.Delete(tx.table.tableName, rowKey)})
+}
+
+/*
+BufferWrite buffers the provided mutations, which are applied when the transaction commits. This provides a convenient
+way to write columns which are not PROTO columns in the same transaction, e.g. alongside a row written using Create.
+*/
+func (tx *TxTableClient) BufferWrite(mutations ...*spanner.Mutation) error {
+	return tx.txn.BufferWrite(mutations)
+}
+
+// bufferWrite buffers a mutation of the provided row, created using the provided mutation function.
+func (tx *TxTableClient) bufferWrite(mutation func(table string, columns []string, values []interface{}) *spanner.Mutation, rowKey spanner.Key, messages []proto.Message) error {
+	columns, values, err := tx.table.mutationColumns(&Row{
+		Key:      rowKey,
+		Messages: messages,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.txn.BufferWrite([]*spanner.Mutation{mutation(tx.table.tableName, columns, values)})
+}
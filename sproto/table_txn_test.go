@@ -0,0 +1,86 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestTableClient_RunInTransaction(t *testing.T) {
+	ctx := context.Background()
+	tbl, err := newTestDbClient().NewTableClient("test_generated_key", 100)
+	if err != nil {
+		t.Fatalf("NewTableClient() error = %v", err)
+	}
+
+	value := fmt.Sprintf("txn-%d", time.Now().UnixNano())
+	key := spanner.Key{value}
+	t.Cleanup(func() {
+		_ = tbl.Delete(context.Background(), key)
+	})
+
+	// createIfAbsent reads the row and only creates it if it does not exist yet, reporting whether it was created.
+	createIfAbsent := func() (bool, error) {
+		created := false
+		err := tbl.RunInTransaction(ctx, func(ctx context.Context, tx *TxTableClient) error {
+			created = false
+			err := tx.Read(ctx, key, &wrapperspb.StringValue{})
+			if err == nil {
+				return nil
+			}
+			if !errors.Is(err, ErrNotFound{}) {
+				return err
+			}
+			created = true
+			return tx.Create(ctx, key, wrapperspb.String(value))
+		})
+		return created, err
+	}
+
+	created, err := createIfAbsent()
+	if err != nil {
+		t.Fatalf("RunInTransaction() error = %v", err)
+	}
+	if !created {
+		t.Errorf("RunInTransaction() created = false, want the absent row to be created")
+	}
+	created, err = createIfAbsent()
+	if err != nil {
+		t.Fatalf("RunInTransaction() error = %v", err)
+	}
+	if created {
+		t.Errorf("RunInTransaction() created = true, want the existing row to be left as is")
+	}
+
+	// An error returned by fn rolls back the buffered writes.
+	errRollback := errors.New("rollback")
+	err = tbl.RunInTransaction(ctx, func(ctx context.Context, tx *TxTableClient) error {
+		if err := tx.Delete(ctx, key); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Errorf("RunInTransaction() error = %v, want %v", err, errRollback)
+	}
+	got := &wrapperspb.StringValue{}
+	if err := tbl.Read(ctx, key, got); err != nil {
+		t.Fatalf("Read() error = %v, want the row to still exist", err)
+	}
+	if got.GetValue() != value {
+		t.Errorf("Read() = %v, want %v", got.GetValue(), value)
+	}
+
+	// Creating a row which already exists fails when the transaction commits.
+	err = tbl.RunInTransaction(ctx, func(ctx context.Context, tx *TxTableClient) error {
+		return tx.Create(ctx, key, wrapperspb.String(value))
+	})
+	if !errors.Is(err, ErrAlreadyExists{}) {
+		t.Errorf("RunInTransaction() error = %v, want ErrAlreadyExists", err)
+	}
+}
@@ -0,0 +1,103 @@
+package sproto
+
+import (
+	"cloud.google.com/go/spanner"
+)
+
+/*
+RequestOptions holds the tags attached to the requests made to Spanner, which are surfaced in Query Insights and the
+introspection tables, e.g. to attribute the cost of reads and writes to a feature or a tenant.
+
+See https://cloud.google.com/spanner/docs/introspection/troubleshooting-with-tags for the allowed characters.
+*/
+type RequestOptions struct {
+	// RequestTag is attached to every read and query.
+	RequestTag string
+	// TransactionTag is attached to every write, i.e. to the read-write transaction committing the mutations.
+	TransactionTag string
+}
+
+/*
+WithRequestOptions sets the tags attached to all the requests made by the client. See RequestOptions.
+
+Use Client.WithRequestOptions instead to tag the requests of a single feature or tenant.
+*/
+func WithRequestOptions(opts RequestOptions) ClientOption {
+	return func(o *ClientOptions) {
+		o.requestOptions = opts
+	}
+}
+
+/*
+WithRequestOptions returns a copy of the client which attaches the provided tags to all of its requests. The copy shares
+the underlying spanner.Client, so it is cheap to create one per feature or tenant, or even per request:
+
+	err := client.WithRequestOptions(sproto.RequestOptions{RequestTag: "feature=reports"}).ReadProto(ctx, ...)
+*/
+func (s *Client) WithRequestOptions(opts RequestOptions) *Client {
+	tagged := *s
+	tagged.requestOptions = opts
+	return &tagged
+}
+
+// queryOptions returns the options of the queries made by the client.
+func (s *Client) queryOptions() spanner.QueryOptions {
+	return spanner.QueryOptions{RequestTag: s.requestOptions.RequestTag}
+}
+
+// readOptions returns the options of the reads made by the client, based on the provided options, if any.
+// The request tag of the client is only set if the provided options do not already have one.
+func (s *Client) readOptions(opts *spanner.ReadOptions) *spanner.ReadOptions {
+	res := &spanner.ReadOptions{}
+	if opts != nil {
+		*res = *opts
+	}
+	if res.RequestTag == "" {
+		res.RequestTag = s.requestOptions.RequestTag
+	}
+	return res
+}
+
+// applyOptions returns the options of the writes made by the client.
+func (s *Client) applyOptions() []spanner.ApplyOption {
+	if s.requestOptions.TransactionTag == "" {
+		return nil
+	}
+	return []spanner.ApplyOption{spanner.TransactionTag(s.requestOptions.TransactionTag)}
+}
+
+// transactionOptions returns the options of the read-write transactions run by the client.
+func (s *Client) transactionOptions() spanner.TransactionOptions {
+	return spanner.TransactionOptions{TransactionTag: s.requestOptions.TransactionTag}
+}
+
+// queryOptions returns the options of the queries made by the table client.
+func (t *TableClient) queryOptions() spanner.QueryOptions {
+	return spanner.QueryOptions{RequestTag: t.requestOptions.RequestTag}
+}
+
+// readOptions returns the options of the reads made by the table client.
+func (t *TableClient) readOptions() *spanner.ReadOptions {
+	return &spanner.ReadOptions{RequestTag: t.requestOptions.RequestTag}
+}
+
+// applyOptions returns the options of the writes made by the table client.
+func (t *TableClient) applyOptions() []spanner.ApplyOption {
+	if t.requestOptions.TransactionTag == "" {
+		return nil
+	}
+	return []spanner.ApplyOption{spanner.TransactionTag(t.requestOptions.TransactionTag)}
+}
+
+// applyOptions returns the options of the commit of the mutation builder. The caller must hold the builder's lock.
+func (b *MutationBuilder) applyOptions() []spanner.ApplyOption {
+	if b.requestOptions.TransactionTag == "" {
+		return nil
+	}
+	return []spanner.ApplyOption{spanner.TransactionTag(b.requestOptions.TransactionTag)}
+}
+
+// transactionOptions returns the options of the read-write transactions run by the table client.
+func (t *TableClient) transactionOptions() spanner.TransactionOptions {
+	return spanner.TransactionOptions{TransactionTag: t.requestOptions.TransactionTag}
+}
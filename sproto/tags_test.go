@@ -0,0 +1,74 @@
+package sproto
+
+import (
+	"testing"
+
+	"cloud.google.com/go/spanner"
+)
+
+func TestClient_RequestOptions(t *testing.T) {
+	c := &Client{requestOptions: RequestOptions{RequestTag: "feature=reports", TransactionTag: "tenant=acme"}}
+
+	if got := c.queryOptions().RequestTag; got != "feature=reports" {
+		t.Errorf("queryOptions().RequestTag = %q, want %q", got, "feature=reports")
+	}
+	if got := c.readOptions(nil).RequestTag; got != "feature=reports" {
+		t.Errorf("readOptions(nil).RequestTag = %q, want %q", got, "feature=reports")
+	}
+	explicit := &spanner.ReadOptions{Index: "ByName", RequestTag: "explicit"}
+	got := c.readOptions(explicit)
+	if got.RequestTag != "explicit" || got.Index != "ByName" {
+		t.Errorf("readOptions() = %+v, want the explicit options preserved", got)
+	}
+	if got == explicit {
+		t.Errorf("readOptions() returned the provided options, want a copy")
+	}
+	if got := c.applyOptions(); len(got) != 1 {
+		t.Errorf("len(applyOptions()) = %d, want 1", len(got))
+	}
+	if got := c.transactionOptions().TransactionTag; got != "tenant=acme" {
+		t.Errorf("transactionOptions().TransactionTag = %q, want %q", got, "tenant=acme")
+	}
+
+	untagged := &Client{}
+	if got := untagged.applyOptions(); got != nil {
+		t.Errorf("applyOptions() = %v, want nil without a transaction tag", got)
+	}
+	if got := untagged.readOptions(nil).RequestTag; got != "" {
+		t.Errorf("readOptions(nil).RequestTag = %q, want empty", got)
+	}
+}
+
+func TestClient_WithRequestOptions(t *testing.T) {
+	c := &Client{requestOptions: RequestOptions{RequestTag: "default"}}
+	tagged := c.WithRequestOptions(RequestOptions{RequestTag: "feature=reports"})
+
+	if tagged == c {
+		t.Fatalf("WithRequestOptions() returned the same client, want a copy")
+	}
+	if got := tagged.queryOptions().RequestTag; got != "feature=reports" {
+		t.Errorf("tagged queryOptions().RequestTag = %q, want %q", got, "feature=reports")
+	}
+	if got := c.queryOptions().RequestTag; got != "default" {
+		t.Errorf("original queryOptions().RequestTag = %q, want %q", got, "default")
+	}
+}
+
+func TestTableClient_RequestOptions(t *testing.T) {
+	opts := &TableClientOptions{}
+	WithTableRequestOptions(RequestOptions{RequestTag: "feature=reports", TransactionTag: "tenant=acme"})(opts)
+	tc := &TableClient{requestOptions: opts.requestOptions}
+
+	if got := tc.queryOptions().RequestTag; got != "feature=reports" {
+		t.Errorf("queryOptions().RequestTag = %q, want %q", got, "feature=reports")
+	}
+	if got := tc.readOptions().RequestTag; got != "feature=reports" {
+		t.Errorf("readOptions().RequestTag = %q, want %q", got, "feature=reports")
+	}
+	if got := tc.applyOptions(); len(got) != 1 {
+		t.Errorf("len(applyOptions()) = %d, want 1", len(got))
+	}
+	if got := tc.transactionOptions().TransactionTag; got != "tenant=acme" {
+		t.Errorf("transactionOptions().TransactionTag = %q, want %q", got, "tenant=acme")
+	}
+}
@@ -0,0 +1,230 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+/*
+Tx provides read and write methods which all participate in the same read-write transaction.
+
+Reads observe the writes committed before the transaction, but not the writes made using the Tx itself, since these
+are buffered and only applied when the transaction commits.
+
+A Tx is only valid within the function passed to RunInTransaction.
+*/
+type Tx struct {
+	client *Client
+	txn    *spanner.ReadWriteTransaction
+}
+
+/*
+RunInTransaction runs fn within a single read-write transaction, so that the reads and writes made using the provided
+Tx, across any number of rows and tables, are applied atomically:
+
+	err := client.RunInTransaction(ctx, func(ctx context.Context, tx *sproto.Tx) error {
+		from, to := &pb.Account{}, &pb.Account{}
+		if err := tx.ReadProto(ctx, "Accounts", spanner.Key{fromId}, "Account", from, nil); err != nil {
+			return err
+		}
+		if err := tx.ReadProto(ctx, "Accounts", spanner.Key{toId}, "Account", to, nil); err != nil {
+			return err
+		}
+		from.Balance -= amount
+		to.Balance += amount
+		if err := tx.WriteProto(ctx, "Accounts", spanner.Key{fromId}, "Account", from); err != nil {
+			return err
+		}
+		return tx.WriteProto(ctx, "Accounts", spanner.Key{toId}, "Account", to)
+	})
+
+The transaction is retried if it is aborted by Spanner, fn may therefore be called multiple times and should not have
+any side effects other than the ones made using the Tx. The sproto errors returned by fn, such as ErrNotFound, are
+returned as is, and any error returned by fn rolls back the transaction.
+
+This method may return a ErrAlreadyExists or ErrNotFound error if a row written using InsertRow or UpdateRow
+respectively already exists or does not exist when the transaction commits.
+*/
+func (s *Client) RunInTransaction(ctx context.Context, fn func(ctx context.Context, tx *Tx) error) error {
+	_, err := s.client.ReadWriteTransactionWithOptions(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		scoped := *s
+		scoped.roTxn = nil
+		scoped.rwTxn = txn
+		return fn(ctx, &Tx{
+			client: &scoped,
+			txn:    txn,
+		})
+	}, s.transactionOptions())
+	if err != nil {
+		return transactionError(err)
+	}
+
+	return nil
+}
+
+// transactionError maps the error returned by a read-write transaction onto the sproto errors. The sproto errors
+// returned by the transaction function are returned as is, and commit errors are mapped based on their code.
+func transactionError(err error) error {
+	var errSproto sprotoError
+	if errors.As(err, &errSproto) {
+		return errSproto
+	}
+	switch spanner.ErrCode(err) {
+	case codes.AlreadyExists:
+		return ErrAlreadyExists{
+			err: err,
+		}
+	case codes.NotFound:
+		return ErrNotFound{
+			err: err,
+		}
+	}
+
+	return err
+}
+
+/*
+ReadProto reads a proto message within the transaction. See Client.ReadProto for details.
+*/
+func (tx *Tx) ReadProto(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message, readMask *fieldmaskpb.FieldMask) error {
+	return tx.client.ReadProto(ctx, tableName, rowKey, columnName, message, readMask)
+}
+
+/*
+BatchReadProtos reads multiple proto messages within the transaction. See Client.BatchReadProtos for details.
+*/
+func (tx *Tx) BatchReadProtos(ctx context.Context, tableName string, rowKeys []spanner.Key, columnName string, message proto.Message, readMask *fieldmaskpb.FieldMask) ([]proto.Message, error) {
+	return tx.client.BatchReadProtos(ctx, tableName, rowKeys, columnName, message, readMask)
+}
+
+/*
+QueryProtos queries proto messages within the transaction. See Client.QueryProtos for details.
+*/
+func (tx *Tx) QueryProtos(ctx context.Context, tableName string, columnNames []string, messages []proto.Message, filter *spanner.Statement, opts *ReadOptions) ([]map[string]proto.Message, string, error) {
+	return tx.client.QueryProtos(ctx, tableName, columnNames, messages, filter, opts)
+}
+
+/*
+ReadRow reads a row within the transaction. See Client.ReadRow for details.
+*/
+func (tx *Tx) ReadRow(ctx context.Context, tableName string, rowKey spanner.Key, columns []string, opts *spanner.ReadOptions) (map[string]interface{}, error) {
+	return tx.client.ReadRow(ctx, tableName, rowKey, columns, opts)
+}
+
+/*
+QueryRows queries rows within the transaction. See Client.QueryRows for details.
+*/
+func (tx *Tx) QueryRows(ctx context.Context, tableName string, columns []string, filter *spanner.Statement, opts *ReadOptions) ([]map[string]interface{}, string, error) {
+	return tx.client.QueryRows(ctx, tableName, columns, filter, opts)
+}
+
+/*
+WriteProto buffers the write of a proto message, which is applied when the transaction commits. See Client.WriteProto
+for details.
+*/
+func (tx *Tx) WriteProto(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message) error {
+	return tx.WriteProtoWithColumns(ctx, tableName, rowKey, columnName, message, nil)
+}
+
+/*
+WriteProtoWithColumns buffers the write of a proto message along with additional columns, which is applied when the
+transaction commits. See Client.WriteProtoWithColumns for details.
+*/
+func (tx *Tx) WriteProtoWithColumns(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message, extra map[string]interface{}) error {
+	mutation, err := tx.client.writeColumnMutation(ctx, tableName, rowKey, columnName, message, extra)
+	if err != nil {
+		return err
+	}
+
+	return tx.txn.BufferWrite([]*spanner.Mutation{mutation})
+}
+
+/*
+UpdateProto reads the current proto message, merges the provided updates into it and buffers the write of the result,
+which is applied when the transaction commits. See Client.UpdateProto for details.
+*/
+func (tx *Tx) UpdateProto(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message, updateMask *fieldmaskpb.FieldMask) error {
+	return tx.updateProto(ctx, tableName, rowKey, columnName, message, updateMask, nil)
+}
+
+// updateProto implements UpdateProto and UpdateProtoIf, calling check, if set, on the current message before merging.
+func (tx *Tx) updateProto(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message, updateMask *fieldmaskpb.FieldMask, check func(current proto.Message) error) error {
+	// Retrieve the current resource from the database
+	currentMessage := newEmptyMessage(message)
+	err := tx.ReadProto(ctx, tableName, rowKey, columnName, currentMessage, nil)
+	if err != nil {
+		return err
+	}
+	if check != nil {
+		if err := check(currentMessage); err != nil {
+			return err
+		}
+	}
+
+	// Merge the updates into currentMessage
+	err = mergeUpdates(currentMessage, message, updateMask)
+	if err != nil {
+		return err
+	}
+
+	// Write the updated message to the database
+	return tx.WriteProto(ctx, tableName, rowKey, columnName, currentMessage)
+}
+
+/*
+InsertRow buffers the insertion of a row, which is applied when the transaction commits. See Client.InsertRow for
+details.
+*/
+func (tx *Tx) InsertRow(ctx context.Context, tableName string, row map[string]interface{}) error {
+	return tx.bufferRow(spanner.Insert, tableName, row)
+}
+
+/*
+UpdateRow buffers the update of a row, which is applied when the transaction commits. See Client.UpdateRow for
+details.
+*/
+func (tx *Tx) UpdateRow(ctx context.Context, tableName string, row map[string]interface{}) error {
+	return tx.bufferRow(spanner.Update, tableName, row)
+}
+
+/*
+UpsertRow buffers the insertion or update of a row, which is applied when the transaction commits. See
+Client.UpsertRow for details.
+*/
+func (tx *Tx) UpsertRow(ctx context.Context, tableName string, row map[string]interface{}) error {
+	return tx.bufferRow(spanner.InsertOrUpdate, tableName, row)
+}
+
+/*
+DeleteRow buffers the deletion of a row, which is applied when the transaction commits. See Client.DeleteRow for
+details.
+*/
+func (tx *Tx) DeleteRow(ctx context.Context, tableName string, rowKey spanner.Key) error {
+	return tx.txn.BufferWrite([]*spanner.Mutation{spanner.Delete(tableName, rowKey)})
+}
+
+/*
+BufferWrite buffers the provided mutations, which are applied when the transaction commits. This provides a convenient
+way to write custom mutations within the transaction.
+*/
+func (tx *Tx) BufferWrite(mutations ...*spanner.Mutation) error {
+	return tx.txn.BufferWrite(mutations)
+}
+
+// bufferRow buffers a mutation of the provided row, created using the provided mutation function.
+func (tx *Tx) bufferRow(mutation func(table string, columns []string, values []interface{}) *spanner.Mutation, tableName string, row map[string]interface{}) error {
+	// Construct columns and values from the provided row
+	columns := make([]string, 0, len(row))
+	values := make([]interface{}, 0, len(row))
+	for column, value := range row {
+		columns = append(columns, column)
+		values = append(values, encodeColumnValue(value))
+	}
+
+	return tx.txn.BufferWrite([]*spanner.Mutation{mutation(tableName, columns, values)})
+}
@@ -0,0 +1,77 @@
+package sproto
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+func TestClient_RunInTransaction(t *testing.T) {
+	ctx := context.Background()
+	from, to := time.Now().UnixNano(), time.Now().UnixNano()+1
+	for _, id := range []int64{from, to} {
+		if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id, "Name": "initial"}); err != nil {
+			t.Fatalf("InsertRow() error = %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		_ = sproto.BatchDeleteRows(context.Background(), "test_table", []spanner.Key{{from}, {to}})
+	})
+
+	// The writes made across rows are applied together.
+	err := sproto.RunInTransaction(ctx, func(ctx context.Context, tx *Tx) error {
+		row, err := tx.ReadRow(ctx, "test_table", spanner.Key{from}, []string{"Name"}, nil)
+		if err != nil {
+			return err
+		}
+		if err := tx.UpdateRow(ctx, "test_table", map[string]interface{}{"Id": to, "Name": row["Name"].(string) + " copy"}); err != nil {
+			return err
+		}
+		return tx.UpdateRow(ctx, "test_table", map[string]interface{}{"Id": from, "Name": "moved"})
+	})
+	if err != nil {
+		t.Fatalf("RunInTransaction() error = %v", err)
+	}
+	rows, err := sproto.BatchReadRows(ctx, "test_table", []spanner.Key{{from}, {to}}, []string{"Id", "Name"}, nil)
+	if err != nil {
+		t.Fatalf("BatchReadRows() error = %v", err)
+	}
+	for _, row := range rows {
+		want := map[int64]string{from: "moved", to: "initial copy"}[row["Id"].(int64)]
+		if row["Name"] != want {
+			t.Errorf("Name of %v = %v, want %v", row["Id"], row["Name"], want)
+		}
+	}
+
+	// An error returned by fn rolls back the buffered writes.
+	errRollback := errors.New("rollback")
+	err = sproto.RunInTransaction(ctx, func(ctx context.Context, tx *Tx) error {
+		if err := tx.UpdateRow(ctx, "test_table", map[string]interface{}{"Id": from, "Name": "rolled back"}); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Errorf("RunInTransaction() error = %v, want %v", err, errRollback)
+	}
+	row, err := sproto.ReadRow(ctx, "test_table", spanner.Key{from}, []string{"Name"}, nil)
+	if err != nil {
+		t.Fatalf("ReadRow() error = %v", err)
+	}
+	if row["Name"] != "moved" {
+		t.Errorf("Name after rollback = %v, want %v", row["Name"], "moved")
+	}
+
+	// The sproto errors returned by fn are returned as is.
+	err = sproto.RunInTransaction(ctx, func(ctx context.Context, tx *Tx) error {
+		_, err := tx.ReadRow(ctx, "test_table", spanner.Key{to + 1}, []string{"Name"}, nil)
+		return err
+	})
+	var errNotFound ErrNotFound
+	if !errors.As(err, &errNotFound) || errNotFound.RowKey == "" {
+		t.Errorf("RunInTransaction() error = %v, want ErrNotFound for the row key", err)
+	}
+}
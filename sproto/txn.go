@@ -0,0 +1,156 @@
+package sproto
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// reader is implemented by both read-only and read-write transactions.
+type reader interface {
+	rowReader
+	ReadWithOptions(ctx context.Context, table string, keys spanner.KeySet, columns []string, opts *spanner.ReadOptions) *spanner.RowIterator
+	QueryWithOptions(ctx context.Context, statement spanner.Statement, opts spanner.QueryOptions) *spanner.RowIterator
+}
+
+// single returns the transaction to use for a read.
+// This is a single-use transaction, unless the client is scoped to a read-only or read-write transaction.
+func (s *Client) single() reader {
+	if s.rwTxn != nil {
+		return s.rwTxn
+	}
+	if s.roTxn != nil {
+		return s.roTxn
+	}
+	if s.timestampBound != nil {
+		return s.client.Single().WithTimestampBound(*s.timestampBound)
+	}
+	return s.client.Single()
+}
+
+/*
+ROTx provides read methods which all run within the same read-only transaction, and therefore observe the same
+consistent snapshot of the database.
+
+A ROTx is only valid within the function passed to ReadOnlyTransaction.
+*/
+type ROTx struct {
+	client *Client
+	txn    *spanner.ReadOnlyTransaction
+}
+
+/*
+ReadOnlyTransaction runs fn within a single read-only transaction, so that all the reads made using the provided ROTx
+observe the same snapshot of the database. This is useful when reading several related rows to build a response.
+
+The transaction uses a strong timestamp bound, i.e. the snapshot includes all the writes committed before the
+first read of the transaction. Writes committed afterwards are not visible to any of the reads in fn. If the client was
+created using WithTimestampBound, the transaction uses its bound instead, which must then be an exact one, i.e.
+spanner.ReadTimestamp or spanner.ExactStaleness.
+
+Read-only transactions do not take locks and are never aborted, which makes them considerably cheaper than read-write
+transactions. Prefer them whenever no writes are required.
+
+The error returned by fn, if any, is returned as is.
+*/
+func (s *Client) ReadOnlyTransaction(ctx context.Context, fn func(ctx context.Context, tx *ROTx) error) error {
+	txn := s.client.ReadOnlyTransaction()
+	if s.timestampBound != nil {
+		txn = txn.WithTimestampBound(*s.timestampBound)
+	}
+	defer txn.Close()
+
+	return fn(ctx, &ROTx{
+		client: s.withReadOnlyTransaction(txn),
+		txn:    txn,
+	})
+}
+
+// withReadOnlyTransaction returns a copy of the client whose reads all use the provided read-only transaction.
+func (s *Client) withReadOnlyTransaction(txn *spanner.ReadOnlyTransaction) *Client {
+	scoped := *s
+	scoped.roTxn = txn
+	return &scoped
+}
+
+/*
+snapshot returns a client whose reads all observe the same snapshot of the database, along with the function
+releasing it once the reads are done. A client already scoped to a transaction is returned as is.
+
+A client created using WithTimestampBound is also returned as is, since bounded staleness is only supported by
+single-use transactions. Its reads observe the same snapshot if the bound is an exact one.
+*/
+func (s *Client) snapshot() (*Client, func()) {
+	if s.roTxn != nil || s.rwTxn != nil || s.timestampBound != nil {
+		return s, func() {}
+	}
+	txn := s.client.ReadOnlyTransaction()
+	return s.withReadOnlyTransaction(txn), txn.Close
+}
+
+/*
+Timestamp returns the timestamp of the snapshot read by the transaction.
+It is only available once at least one read has been made within the transaction.
+*/
+func (tx *ROTx) Timestamp() (time.Time, error) {
+	return tx.txn.Timestamp()
+}
+
+/*
+ReadProto reads a proto message within the transaction. See Client.ReadProto for details.
+*/
+func (tx *ROTx) ReadProto(ctx context.Context, tableName string, rowKey spanner.Key, columnName string, message proto.Message, readMask *fieldmaskpb.FieldMask) error {
+	return tx.client.ReadProto(ctx, tableName, rowKey, columnName, message, readMask)
+}
+
+/*
+BatchReadProtos reads multiple proto messages within the transaction. See Client.BatchReadProtos for details.
+*/
+func (tx *ROTx) BatchReadProtos(ctx context.Context, tableName string, rowKeys []spanner.Key, columnName string, message proto.Message, readMask *fieldmaskpb.FieldMask) ([]proto.Message, error) {
+	return tx.client.BatchReadProtos(ctx, tableName, rowKeys, columnName, message, readMask)
+}
+
+/*
+ListProtos lists proto messages within the transaction. See Client.ListProtos for details.
+*/
+func (tx *ROTx) ListProtos(ctx context.Context, tableName string, columnName string, message proto.Message, opts *ReadOptions) ([]proto.Message, string, error) {
+	return tx.client.ListProtos(ctx, tableName, columnName, message, opts)
+}
+
+/*
+QueryProtos queries proto messages within the transaction. See Client.QueryProtos for details.
+*/
+func (tx *ROTx) QueryProtos(ctx context.Context, tableName string, columnNames []string, messages []proto.Message, filter *spanner.Statement, opts *ReadOptions) ([]map[string]proto.Message, string, error) {
+	return tx.client.QueryProtos(ctx, tableName, columnNames, messages, filter, opts)
+}
+
+/*
+ReadRow reads a row within the transaction. See Client.ReadRow for details.
+*/
+func (tx *ROTx) ReadRow(ctx context.Context, tableName string, rowKey spanner.Key, columns []string, opts *spanner.ReadOptions) (map[string]interface{}, error) {
+	return tx.client.ReadRow(ctx, tableName, rowKey, columns, opts)
+}
+
+/*
+BatchReadRows reads multiple rows within the transaction. See Client.BatchReadRows for details.
+*/
+func (tx *ROTx) BatchReadRows(ctx context.Context, tableName string, rowKeys []spanner.Key, columns []string, opts *spanner.ReadOptions) ([]map[string]interface{}, error) {
+	return tx.client.BatchReadRows(ctx, tableName, rowKeys, columns, opts)
+}
+
+/*
+QueryRows queries rows within the transaction. See Client.QueryRows for details.
+*/
+func (tx *ROTx) QueryRows(ctx context.Context, tableName string, columns []string, filter *spanner.Statement, opts *ReadOptions) ([]map[string]interface{}, string, error) {
+	return tx.client.QueryRows(ctx, tableName, columns, filter, opts)
+}
+
+/*
+ListRows lists rows within the transaction. See Client.ListRows for details.
+*/
+func (tx *ROTx) ListRows(ctx context.Context, tableName string, columns []string, opts *spanner.ReadOptions) ([]map[string]interface{}, error) {
+	return tx.client.ListRows(ctx, tableName, columns, opts)
+}
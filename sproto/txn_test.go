@@ -0,0 +1,65 @@
+package sproto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+func TestClient_ReadOnlyTransaction(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id, "Name": "before"}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sproto.DeleteRow(context.Background(), "test_table", spanner.Key{id})
+	})
+
+	err := sproto.ReadOnlyTransaction(ctx, func(ctx context.Context, tx *ROTx) error {
+		first, err := tx.ReadRow(ctx, "test_table", spanner.Key{id}, []string{"Name"}, nil)
+		if err != nil {
+			return err
+		}
+		firstTimestamp, err := tx.Timestamp()
+		if err != nil {
+			return err
+		}
+
+		// Writes committed after the first read must not be visible within the transaction.
+		if err := sproto.UpdateRow(ctx, "test_table", map[string]interface{}{"Id": id, "Name": "after"}); err != nil {
+			return err
+		}
+
+		second, err := tx.ReadRow(ctx, "test_table", spanner.Key{id}, []string{"Name"}, nil)
+		if err != nil {
+			return err
+		}
+		secondTimestamp, err := tx.Timestamp()
+		if err != nil {
+			return err
+		}
+
+		if first["Name"] != "before" || second["Name"] != "before" {
+			t.Errorf("ReadRow() got %v and %v, want both to be %v", first["Name"], second["Name"], "before")
+		}
+		if !firstTimestamp.Equal(secondTimestamp) {
+			t.Errorf("Timestamp() got %v and %v, want the same snapshot", firstTimestamp, secondTimestamp)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadOnlyTransaction() error = %v", err)
+	}
+
+	// Outside of the transaction, the latest value is visible.
+	got, err := sproto.ReadRow(ctx, "test_table", spanner.Key{id}, []string{"Name"}, nil)
+	if err != nil {
+		t.Fatalf("ReadRow() error = %v", err)
+	}
+	if got["Name"] != "after" {
+		t.Errorf("ReadRow() got %v, want %v", got["Name"], "after")
+	}
+}
@@ -0,0 +1,112 @@
+package sproto
+
+import (
+	"context"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+/*
+TypedClient reads and writes the proto messages of type T stored in a column of a table, returning them as T rather
+than proto.Message, so that the call sites neither have to type-assert the results nor risk a panic on a wrong cast.
+
+It is a thin wrapper around the methods of Client, which document the behavior of each method in detail.
+
+Example:
+
+	books := sproto.NewTypedClient[*pb.Book](client, "Books", "Proto")
+	book, err := books.Read(ctx, spanner.Key{id}, nil)
+*/
+type TypedClient[T proto.Message] struct {
+	client     *Client
+	tableName  string
+	columnName string
+}
+
+/*
+NewTypedClient creates a TypedClient for the messages of type T stored in the provided column, which must be of type
+PROTO, of the provided table.
+*/
+func NewTypedClient[T proto.Message](client *Client, tableName string, columnName string) *TypedClient[T] {
+	return &TypedClient[T]{
+		client:     client,
+		tableName:  tableName,
+		columnName: columnName,
+	}
+}
+
+/*
+Read reads the message of the row identified by the provided row key, see Client.ReadProto.
+*/
+func (c *TypedClient[T]) Read(ctx context.Context, rowKey spanner.Key, readMask *fieldmaskpb.FieldMask) (T, error) {
+	message := newTypedMessage[T]()
+	if err := c.client.ReadProto(ctx, c.tableName, rowKey, c.columnName, message, readMask); err != nil {
+		var zero T
+		return zero, err
+	}
+	return message, nil
+}
+
+/*
+BatchRead reads the messages of the rows identified by the provided row keys, see Client.BatchReadProtos.
+*/
+func (c *TypedClient[T]) BatchRead(ctx context.Context, rowKeys []spanner.Key, readMask *fieldmaskpb.FieldMask) ([]T, error) {
+	messages, err := c.client.BatchReadProtos(ctx, c.tableName, rowKeys, c.columnName, newTypedMessage[T](), readMask)
+	if err != nil {
+		return nil, err
+	}
+	return castMessages[T](messages)
+}
+
+/*
+List lists the messages of the table, see Client.ListProtos.
+The second return value is the next page token which can be used to get the next page of results.
+*/
+func (c *TypedClient[T]) List(ctx context.Context, opts *ReadOptions) ([]T, string, error) {
+	return List[T](ctx, c.client, c.tableName, c.columnName, opts)
+}
+
+/*
+Query reads the messages of the rows matching the provided filter, see Client.QueryProtos.
+The second return value is the next page token which can be used to get the next page of results.
+*/
+func (c *TypedClient[T]) Query(ctx context.Context, filter *spanner.Statement, opts *ReadOptions) ([]T, string, error) {
+	rows, nextPageToken, err := c.client.QueryProtos(ctx, c.tableName, []string{c.columnName}, []proto.Message{newTypedMessage[T]()}, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	messages := make([]proto.Message, len(rows))
+	for i, row := range rows {
+		messages[i] = row[c.columnName]
+	}
+	res, err := castMessages[T](messages)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return res, nextPageToken, nil
+}
+
+/*
+Write writes the message to the row identified by the provided row key, see Client.WriteProto.
+*/
+func (c *TypedClient[T]) Write(ctx context.Context, rowKey spanner.Key, message T) error {
+	return c.client.WriteProto(ctx, c.tableName, rowKey, c.columnName, message)
+}
+
+/*
+Update merges the provided message into the message of the row identified by the provided row key, see
+Client.UpdateProto.
+*/
+func (c *TypedClient[T]) Update(ctx context.Context, rowKey spanner.Key, message T, updateMask *fieldmaskpb.FieldMask) error {
+	return c.client.UpdateProto(ctx, c.tableName, rowKey, c.columnName, message, updateMask)
+}
+
+// newTypedMessage returns a new empty message of type T, see newEmptyMessage.
+func newTypedMessage[T proto.Message]() T {
+	var message T
+	return newEmptyMessage(message).(T)
+}
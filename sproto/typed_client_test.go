@@ -0,0 +1,68 @@
+package sproto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func Test_newTypedMessage(t *testing.T) {
+	got := newTypedMessage[*wrapperspb.StringValue]()
+	if got == nil {
+		t.Fatalf("newTypedMessage() = nil, want an empty message")
+	}
+	got.Value = "set"
+	if other := newTypedMessage[*wrapperspb.StringValue](); other.GetValue() != "" {
+		t.Errorf("newTypedMessage() = %v, want a new message on every call", other)
+	}
+}
+
+func TestTypedClient(t *testing.T) {
+	ctx := context.Background()
+	id := time.Now().UnixNano()
+	keys := []spanner.Key{{id}, {id + 1}}
+	for i := range keys {
+		data, err := proto.Marshal(wrapperspb.Int64(id + int64(i)))
+		if err != nil {
+			t.Fatalf("proto.Marshal() error = %v", err)
+		}
+		if err := sproto.InsertRow(ctx, "test_table", map[string]interface{}{"Id": id + int64(i), "Data": data}); err != nil {
+			t.Fatalf("InsertRow() error = %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		_ = sproto.BatchDeleteRows(context.Background(), "test_table", keys)
+	})
+	values := NewTypedClient[*wrapperspb.Int64Value](sproto, "test_table", "Data")
+
+	got, err := values.Read(ctx, keys[0], nil)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got.GetValue() != id {
+		t.Errorf("Read() = %v, want %v", got.GetValue(), id)
+	}
+
+	batch, err := values.BatchRead(ctx, keys, nil)
+	if err != nil {
+		t.Fatalf("BatchRead() error = %v", err)
+	}
+	if len(batch) != len(keys) {
+		t.Errorf("BatchRead() got %d messages, want %d", len(batch), len(keys))
+	}
+
+	queried, _, err := values.Query(ctx, &spanner.Statement{
+		SQL:    "Id = @id",
+		Params: map[string]interface{}{"id": id + 1},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(queried) != 1 || queried[0].GetValue() != id+1 {
+		t.Errorf("Query() = %v, want the message %v", queried, id+1)
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
+	"cloud.google.com/go/civil"
 	"cloud.google.com/go/spanner"
+	"cloud.google.com/go/spanner/apiv1/spannerpb"
 	"dario.cat/mergo"
 	"github.com/mennanov/fmutils"
 	"google.golang.org/api/iterator"
@@ -19,6 +22,9 @@ import (
 
 // StreamResponse is a response for a stream
 // Call Next to get the next item from the stream
+//
+// Cancelling the context passed to the method returning the stream stops the underlying read, after which Next returns
+// the context error.
 type StreamResponse[T interface{}] struct {
 	wg  *sync.WaitGroup
 	ch  chan *T
@@ -33,11 +39,22 @@ func NewStreamResponse[T interface{}]() *StreamResponse[T] {
 	}
 }
 
-func (r *StreamResponse[T]) addItem(item *T) {
+/*
+addItem sends the item to the stream, blocking until it is received by Next. If the context is done first, the stream
+is closed with the context error instead and false is returned, so that the producer stops reading.
+*/
+func (r *StreamResponse[T]) addItem(ctx context.Context, item *T) bool {
 	// Increment the wait group
 	r.wg.Add(1)
-	// Add the item to the channel
-	r.ch <- item
+	// Add the item to the channel, unless the caller is gone
+	select {
+	case r.ch <- item:
+		return true
+	case <-ctx.Done():
+		r.wg.Done()
+		r.setError(ctx.Err())
+		return false
+	}
 }
 
 func (r *StreamResponse[T]) setError(err error) {
@@ -92,7 +109,13 @@ func newEmptyMessage(msg proto.Message) proto.Message {
 	return newMsg
 }
 
-// mergeUpdates merges the updates into the current message in line with the update mask
+/*
+mergeUpdates merges the updates into the current message in line with the update mask.
+
+The updates are merged using mergo, which only fills the fields not set in the current message. With an update mask,
+the masked fields of the current message are cleared beforehand, so that they are replaced by the ones in updates,
+even if these are not set, i.e. a masked field which is not set in updates is cleared.
+*/
 func mergeUpdates(current proto.Message, updates proto.Message, updateMask *fieldmaskpb.FieldMask) error {
 	// If current and updates are different types, return an error
 	if reflect.TypeOf(current) != reflect.TypeOf(updates) {
@@ -112,21 +135,22 @@ func mergeUpdates(current proto.Message, updates proto.Message, updateMask *fiel
 		return nil
 	}
 
+	// Apply Update Mask if provided, normalizing a copy to leave the caller's mask unchanged
+	if updateMask != nil && len(updateMask.GetPaths()) > 0 {
+		mask := proto.Clone(updateMask).(*fieldmaskpb.FieldMask)
+		mask.Normalize()
+		if !mask.IsValid(current) {
+			return ErrInvalidFieldMask
+		}
+		// Clear the masked fields, so that those not set in updates remain cleared after the merge.
+		fmutils.Prune(current, mask.GetPaths())
+	}
+
 	// If updates is empty, return nil
 	if proto.Size(updates) == 0 {
 		return nil
 	}
 
-	// Apply Update Mask if provided
-	if updateMask != nil {
-		updateMask.Normalize()
-		if !updateMask.IsValid(current) {
-			return ErrInvalidFieldMask
-		}
-		// Redact the request according to the provided field mask.
-		fmutils.Prune(current, updateMask.GetPaths())
-	}
-
 	// Merge the updates into the current message
 	err := mergo.Merge(current, updates)
 	if err != nil {
@@ -176,6 +200,213 @@ func parseStructPbValue(value *structpb.Value) interface{} {
 	return res
 }
 
+/*
+decodeKeyColumn decodes the column at the provided index of the row into a value that can be used in a spanner.Key.
+
+Unlike parseStructPbValue, the Spanner column type is taken into account, so that INT64 columns (which are encoded as
+strings on the wire) are returned as int64 and can be used as is to read or write the row again.
+*/
+func decodeKeyColumn(row *spanner.Row, i int) (interface{}, error) {
+	var gcv spanner.GenericColumnValue
+	if err := row.Column(i, &gcv); err != nil {
+		return nil, err
+	}
+
+	switch gcv.Type.GetCode() {
+	case spannerpb.TypeCode_INT64:
+		var v spanner.NullInt64
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+		return v.Int64, nil
+	case spannerpb.TypeCode_STRING:
+		var v spanner.NullString
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+		return v.StringVal, nil
+	case spannerpb.TypeCode_BOOL:
+		var v spanner.NullBool
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+		return v.Bool, nil
+	case spannerpb.TypeCode_FLOAT64:
+		var v spanner.NullFloat64
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+		return v.Float64, nil
+	case spannerpb.TypeCode_BYTES:
+		var v []byte
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case spannerpb.TypeCode_TIMESTAMP:
+		var v spanner.NullTime
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+		return v.Time, nil
+	case spannerpb.TypeCode_DATE:
+		var v spanner.NullDate
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		if !v.Valid {
+			return nil, nil
+		}
+		return v.Date, nil
+	default:
+		return parseStructPbValue(gcv.Value), nil
+	}
+}
+
+/*
+parseColumnValue decodes the column at the provided index of the row into a value for a row map.
+
+ARRAY columns of scalar types are decoded into typed Go slices (e.g. []string, []int64, []time.Time), which can be
+written as is using InsertRow/UpsertRow, with NULL elements decoded into the zero value of the element type.
+All other columns are decoded using parseStructPbValue.
+*/
+func parseColumnValue(row *spanner.Row, i int) (interface{}, error) {
+	var gcv spanner.GenericColumnValue
+	if err := row.Column(i, &gcv); err != nil {
+		return nil, err
+	}
+	if gcv.Type.GetCode() != spannerpb.TypeCode_ARRAY {
+		return parseStructPbValue(gcv.Value), nil
+	}
+	if _, ok := gcv.Value.GetKind().(*structpb.Value_NullValue); ok {
+		return nil, nil
+	}
+
+	switch gcv.Type.GetArrayElementType().GetCode() {
+	case spannerpb.TypeCode_STRING:
+		var v []spanner.NullString
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		res := make([]string, len(v))
+		for i, e := range v {
+			res[i] = e.StringVal
+		}
+		return res, nil
+	case spannerpb.TypeCode_INT64:
+		var v []spanner.NullInt64
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		res := make([]int64, len(v))
+		for i, e := range v {
+			res[i] = e.Int64
+		}
+		return res, nil
+	case spannerpb.TypeCode_FLOAT64:
+		var v []spanner.NullFloat64
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		res := make([]float64, len(v))
+		for i, e := range v {
+			res[i] = e.Float64
+		}
+		return res, nil
+	case spannerpb.TypeCode_BOOL:
+		var v []spanner.NullBool
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		res := make([]bool, len(v))
+		for i, e := range v {
+			res[i] = e.Bool
+		}
+		return res, nil
+	case spannerpb.TypeCode_BYTES:
+		var v [][]byte
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case spannerpb.TypeCode_TIMESTAMP:
+		var v []spanner.NullTime
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		res := make([]time.Time, len(v))
+		for i, e := range v {
+			res[i] = e.Time
+		}
+		return res, nil
+	case spannerpb.TypeCode_DATE:
+		var v []spanner.NullDate
+		if err := gcv.Decode(&v); err != nil {
+			return nil, err
+		}
+		res := make([]civil.Date, len(v))
+		for i, e := range v {
+			res[i] = e.Date
+		}
+		return res, nil
+	default:
+		return parseStructPbValue(gcv.Value), nil
+	}
+}
+
+/*
+rowToMap converts the row into a map of column names to column values, decoded using parseColumnValue.
+*/
+func rowToMap(row *spanner.Row) (map[string]interface{}, error) {
+	res := make(map[string]interface{}, row.Size())
+	for i, columnName := range row.ColumnNames() {
+		columnValue, err := parseColumnValue(row, i)
+		if err != nil {
+			return nil, err
+		}
+		res[columnName] = columnValue
+	}
+	return res, nil
+}
+
+/*
+encodeColumnValue converts Go slices which Spanner cannot encode as is into a supported type, i.e. []int and []int32
+into []int64 for ARRAY<INT64> columns. All other values are returned as is.
+*/
+func encodeColumnValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []int:
+		res := make([]int64, len(v))
+		for i, e := range v {
+			res[i] = int64(e)
+		}
+		return res
+	case []int32:
+		res := make([]int64, len(v))
+		for i, e := range v {
+			res[i] = int64(e)
+		}
+		return res
+	default:
+		return value
+	}
+}
+
 type primaryKeyColumn struct {
 	// The name of the column
 	columnName string
@@ -183,6 +414,8 @@ type primaryKeyColumn struct {
 	isGenerated bool
 	// Whether the column is stored
 	isStored bool
+	// Whether the column has a default value, e.g. GET_NEXT_SEQUENCE_VALUE(SEQUENCE seq) or GENERATE_UUID()
+	hasDefault bool
 }
 
 // NewPrimaryKeyColumn creates a new instance of primaryKeyColumn
@@ -224,14 +457,14 @@ func getPrimaryKeyColumns(ctx context.Context, client *spanner.Client, tableName
 	//}
 	stmt := spanner.Statement{
 		SQL: `
-			SELECT IC.COLUMN_NAME, C.IS_GENERATED, C.IS_STORED
+			SELECT IC.COLUMN_NAME, C.IS_GENERATED, C.IS_STORED, C.COLUMN_DEFAULT
 			FROM (
 			  SELECT COLUMN_NAME, ORDINAL_POSITION
 			  FROM INFORMATION_SCHEMA.INDEX_COLUMNS
 			  WHERE TABLE_NAME = @tableName AND INDEX_NAME = 'PRIMARY_KEY'
 			) AS IC
 			INNER JOIN (
-			  SELECT COLUMN_NAME, IS_GENERATED, IS_STORED
+			  SELECT COLUMN_NAME, IS_GENERATED, IS_STORED, COLUMN_DEFAULT
 			  FROM INFORMATION_SCHEMA.COLUMNS
 			  WHERE TABLE_NAME = @tableName
 			) AS C
@@ -258,7 +491,7 @@ func getPrimaryKeyColumns(ctx context.Context, client *spanner.Client, tableName
 		if err != nil {
 			return nil, err
 		}
-		var columnName, isGenerated, isStored *string
+		var columnName, isGenerated, isStored, columnDefault *string
 		if err := row.ColumnByName("COLUMN_NAME", &columnName); err != nil {
 			return nil, err
 		}
@@ -268,6 +501,9 @@ func getPrimaryKeyColumns(ctx context.Context, client *spanner.Client, tableName
 		if err := row.ColumnByName("IS_STORED", &isStored); err != nil {
 			return nil, err
 		}
+		if err := row.ColumnByName("COLUMN_DEFAULT", &columnDefault); err != nil {
+			return nil, err
+		}
 
 		col := &primaryKeyColumn{}
 		if columnName != nil {
@@ -288,6 +524,11 @@ func getPrimaryKeyColumns(ctx context.Context, client *spanner.Client, tableName
 		if isStored != nil {
 			col.isStored = *isStored == "YES"
 		}
+		// Keys populated from a sequence are not generated columns (IS_GENERATED is NEVER), they have a default
+		// such as GET_NEXT_SEQUENCE_VALUE(SEQUENCE seq) instead.
+		if columnDefault != nil {
+			col.hasDefault = *columnDefault != ""
+		}
 		columns = append(columns, col)
 	}
 
@@ -1,12 +1,17 @@
 package sproto
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
 	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/typepb"
 )
 
 func Test_newEmptyMessage(t *testing.T) {
@@ -163,3 +168,122 @@ func Test_parseStructPbValue(t *testing.T) {
 		})
 	}
 }
+
+func Test_mergeUpdates(t *testing.T) {
+	current := func() *typepb.Type {
+		return &typepb.Type{
+			Name:          "current",
+			Oneofs:        []string{"a", "b"},
+			SourceContext: &sourcecontextpb.SourceContext{FileName: "current.proto"},
+			Syntax:        typepb.Syntax_SYNTAX_PROTO3,
+		}
+	}
+	tests := []struct {
+		name       string
+		updates    *typepb.Type
+		updateMask *fieldmaskpb.FieldMask
+		want       *typepb.Type
+	}{
+		{
+			name:       "clear nested field via mask",
+			updates:    &typepb.Type{},
+			updateMask: &fieldmaskpb.FieldMask{Paths: []string{"source_context.file_name"}},
+			want: &typepb.Type{
+				Name:          "current",
+				Oneofs:        []string{"a", "b"},
+				SourceContext: &sourcecontextpb.SourceContext{},
+				Syntax:        typepb.Syntax_SYNTAX_PROTO3,
+			},
+		},
+		{
+			name:       "clear fields via mask",
+			updates:    &typepb.Type{Name: "ignored"},
+			updateMask: &fieldmaskpb.FieldMask{Paths: []string{"oneofs", "syntax", "source_context"}},
+			want:       &typepb.Type{Name: "current"},
+		},
+		{
+			name:       "replace masked fields",
+			updates:    &typepb.Type{Name: "updated", Oneofs: []string{"c"}},
+			updateMask: &fieldmaskpb.FieldMask{Paths: []string{"oneofs"}},
+			want: &typepb.Type{
+				Name:          "current",
+				Oneofs:        []string{"c"},
+				SourceContext: &sourcecontextpb.SourceContext{FileName: "current.proto"},
+				Syntax:        typepb.Syntax_SYNTAX_PROTO3,
+			},
+		},
+		{
+			name:       "fill unmasked fields not set",
+			updates:    &typepb.Type{Fields: []*typepb.Field{{Name: "added"}}, Oneofs: []string{"c"}},
+			updateMask: &fieldmaskpb.FieldMask{Paths: []string{"oneofs"}},
+			want: &typepb.Type{
+				Name:          "current",
+				Fields:        []*typepb.Field{{Name: "added"}},
+				Oneofs:        []string{"c"},
+				SourceContext: &sourcecontextpb.SourceContext{FileName: "current.proto"},
+				Syntax:        typepb.Syntax_SYNTAX_PROTO3,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := current()
+			if err := mergeUpdates(got, tt.updates, tt.updateMask); err != nil {
+				t.Fatalf("mergeUpdates() error = %v", err)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("mergeUpdates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	updateMask := &fieldmaskpb.FieldMask{Paths: []string{"syntax", "oneofs"}}
+	if err := mergeUpdates(current(), &typepb.Type{}, updateMask); err != nil {
+		t.Fatalf("mergeUpdates() error = %v", err)
+	}
+	if want := []string{"syntax", "oneofs"}; !reflect.DeepEqual(updateMask.GetPaths(), want) {
+		t.Errorf("mergeUpdates() changed the update mask paths to %v, want %v", updateMask.GetPaths(), want)
+	}
+
+	if err := mergeUpdates(current(), &typepb.Type{}, &fieldmaskpb.FieldMask{Paths: []string{"unknown"}}); err == nil {
+		t.Errorf("mergeUpdates() error = nil, want an error for an invalid mask")
+	}
+}
+
+func TestStreamResponse_addItem(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	res := NewStreamResponse[int]()
+
+	// The producer keeps adding items until the stream is cancelled, as the Stream methods do.
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		for i := 0; ; i++ {
+			if !res.addItem(ctx, &i) {
+				return
+			}
+		}
+	}()
+
+	if _, err := res.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	cancel()
+
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the producer did not exit after the context was cancelled")
+	}
+	// The items sent before the cancellation may still be received, then the context error is returned.
+	for {
+		_, err := res.Next()
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Next() error = %v, want %v", err, context.Canceled)
+		}
+		break
+	}
+}
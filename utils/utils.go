@@ -26,6 +26,52 @@ func Transform[T, U any](arr []T, fn func(T) U) []U {
 	return result
 }
 
+// Keys is a utility function for extracting the keys of a map.
+//
+// The keys are returned in no particular order, and an empty map results in an empty slice. For example:
+//
+//	m := map[string]int{"a": 1, "b": 2}
+//	keys := Keys(m)
+//	// keys = ["a", "b"] (in any order)
+func Keys[K comparable, V any](m map[K]V) []K {
+	result := make([]K, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+// Values is a utility function for extracting the values of a map.
+//
+// The values are returned in no particular order, and an empty map results in an empty slice. For example:
+//
+//	m := map[string]int{"a": 1, "b": 2}
+//	values := Values(m)
+//	// values = [1, 2] (in any order)
+func Values[K comparable, V any](m map[K]V) []V {
+	result := make([]V, 0, len(m))
+	for _, v := range m {
+		result = append(result, v)
+	}
+	return result
+}
+
+// Invert is a utility function for swapping the keys and values of a map.
+//
+// If several keys map to the same value, only one of them is kept in the result, and which one is not defined since
+// maps are iterated in no particular order. For example:
+//
+//	m := map[string]int{"a": 1, "b": 2}
+//	inverted := Invert(m)
+//	// inverted = map[int]string{1: "a", 2: "b"}
+func Invert[K comparable, V comparable](m map[K]V) map[V]K {
+	result := make(map[V]K, len(m))
+	for k, v := range m {
+		result[v] = k
+	}
+	return result
+}
+
 // Find is a utility function for finding the first element in a slice that satisfies a given predicate.
 //
 // It takes a slice and a predicate function as arguments. The predicate function should return true if the
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -108,6 +109,72 @@ func TestTransform(t *testing.T) {
 	}
 }
 
+func TestKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		m    map[string]int
+		want []string
+	}{
+		{name: "Simple", m: map[string]int{"a": 1, "b": 2, "c": 3}, want: []string{"a", "b", "c"}},
+		{name: "Empty", m: map[string]int{}, want: []string{}},
+		{name: "Nil", m: nil, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Keys(tt.m)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Keys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValues(t *testing.T) {
+	tests := []struct {
+		name string
+		m    map[string]int
+		want []int
+	}{
+		{name: "Simple", m: map[string]int{"a": 1, "b": 2, "c": 2}, want: []int{1, 2, 2}},
+		{name: "Empty", m: map[string]int{}, want: []int{}},
+		{name: "Nil", m: nil, want: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Values(tt.m)
+			sort.Ints(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Values() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvert(t *testing.T) {
+	tests := []struct {
+		name string
+		m    map[string]int
+		want map[int]string
+	}{
+		{name: "Simple", m: map[string]int{"a": 1, "b": 2}, want: map[int]string{1: "a", 2: "b"}},
+		{name: "Empty", m: map[string]int{}, want: map[int]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Invert(tt.m); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Invert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// On collisions, one of the keys is kept.
+	got := Invert(map[string]int{"a": 1, "b": 1})
+	if len(got) != 1 || (got[1] != "a" && got[1] != "b") {
+		t.Errorf("Invert() = %v, want a single entry for 1", got)
+	}
+}
+
 func TestFind(t *testing.T) {
 	type args[T any] struct {
 		arr []T
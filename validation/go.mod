@@ -2,6 +2,10 @@ module go.alis.build/validation
 
 go 1.23.1
 
-require google.golang.org/protobuf v1.35.2
+require (
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240903143218-8af14fe29dc1
+	google.golang.org/grpc v1.67.0
+	google.golang.org/protobuf v1.35.2
+)
 
 require github.com/google/go-cmp v0.6.0 // indirect
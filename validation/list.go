@@ -1,7 +1,6 @@
 package validation
 
 import (
-	"regexp"
 	"strings"
 )
 
@@ -189,11 +188,12 @@ func (l *StringList) EachNoneof(values ...string) *StringList {
 }
 
 // Adds a rule to the parent validator asserting that each string matches the given pattern.
+// An invalid pattern results in the rule not being satisfied.
 // If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
 func (l *StringList) EachMatches(pattern string) *StringList {
 	satisfied := true
 	for _, v := range l.value {
-		if !regexp.MustCompile(pattern).MatchString(v) {
+		if matched, err := matchString(pattern, v); err != nil || !matched {
 			satisfied = false
 			break
 		}
@@ -209,7 +209,7 @@ func (l *StringList) EachMatchesOneof(patterns ...string) *StringList {
 	for _, v := range l.value {
 		found := false
 		for _, pattern := range patterns {
-			if regexp.MustCompile(pattern).MatchString(v) {
+			if matched, err := matchString(pattern, v); err == nil && matched {
 				found = true
 				break
 			}
@@ -0,0 +1,60 @@
+package validation
+
+// Provides rules applicable to map values.
+type Map[K comparable, V any] struct {
+	standard[map[K]V]
+}
+
+// Returns a new Map[K, V] instance.
+func newMap[K comparable, V any](path string, value map[K]V) Map[K, V] {
+	return Map[K, V]{standard: newStandard(path, value)}
+}
+
+// Adds a rule to the parent validator asserting that the map is populated.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (m *Map[K, V]) IsPopulated() *Map[K, V] {
+	m.add("be populated", "is populated", len(m.value) > 0)
+	return m
+}
+
+// Adds a rule to the parent validator asserting that the map is empty.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (m *Map[K, V]) IsEmpty() *Map[K, V] {
+	m.add("be empty", "is empty", len(m.value) == 0)
+	return m
+}
+
+// Adds a rule to the parent validator asserting that the number of entries in the map is equal to the given length.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (m *Map[K, V]) LengthEq(eq int) *Map[K, V] {
+	m.add("have a length equal to %v", "has a length equal to %v", len(m.value) == eq, eq)
+	return m
+}
+
+// Adds a rule to the parent validator asserting that the number of entries in the map is greater than the given length.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (m *Map[K, V]) LengthGt(min int) *Map[K, V] {
+	m.add("have a length greater than %v", "has a length greater than %v", len(m.value) > min, min)
+	return m
+}
+
+// Adds a rule to the parent validator asserting that the number of entries in the map is greater than or equal to the given length.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (m *Map[K, V]) LengthGte(min int) *Map[K, V] {
+	m.add("have a length greater than or equal to %v", "has a length greater than or equal to %v", len(m.value) >= min, min)
+	return m
+}
+
+// Adds a rule to the parent validator asserting that the number of entries in the map is less than the given length.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (m *Map[K, V]) LengthLt(max int) *Map[K, V] {
+	m.add("have a length less than %v", "has a length less than %v", len(m.value) < max, max)
+	return m
+}
+
+// Adds a rule to the parent validator asserting that the number of entries in the map is less than or equal to the given length.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (m *Map[K, V]) LengthLte(max int) *Map[K, V] {
+	m.add("have a length less than or equal to %v", "has a length less than or equal to %v", len(m.value) <= max, max)
+	return m
+}
@@ -0,0 +1,46 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Adds a rule asserting that one of the fields of the given oneof is set on the message.
+// The rule is not satisfied if the message is nil or does not have a oneof with the given name.
+func (v *Validator) OneofSet(msg proto.Message, oneofName string) *CustomRule {
+	path := v.fullPath(oneofName)
+	oneof := oneofDescriptor(msg, oneofName)
+	if oneof == nil {
+		return v.Custom(fmt.Sprintf("%s must be a set oneof", path), false, path)
+	}
+
+	cases := make([]string, oneof.Fields().Len())
+	for i := range cases {
+		cases[i] = string(oneof.Fields().Get(i).Name())
+	}
+	satisfied := msg.ProtoReflect().WhichOneof(oneof) != nil
+	return v.Custom(fmt.Sprintf("%s must have one of %s set", path, strings.Join(cases, ", ")), satisfied, path)
+}
+
+// Adds a rule asserting that the given field is the one set for the given oneof on the message.
+// The rule is not satisfied if the message is nil or does not have a oneof with the given name.
+func (v *Validator) OneofIs(msg proto.Message, oneofName string, caseFieldName string) *CustomRule {
+	path := v.fullPath(oneofName)
+	satisfied := false
+	if oneof := oneofDescriptor(msg, oneofName); oneof != nil {
+		field := msg.ProtoReflect().WhichOneof(oneof)
+		satisfied = field != nil && string(field.Name()) == caseFieldName
+	}
+	return v.Custom(fmt.Sprintf("%s must have %s set", path, caseFieldName), satisfied, path)
+}
+
+// Returns the descriptor of the oneof with the given name, or nil if the message is nil or has no such oneof.
+func oneofDescriptor(msg proto.Message, oneofName string) protoreflect.OneofDescriptor {
+	if msg == nil || !msg.ProtoReflect().IsValid() {
+		return nil
+	}
+	return msg.ProtoReflect().Descriptor().Oneofs().ByName(protoreflect.Name(oneofName))
+}
@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestValidator_OneofSet(t *testing.T) {
+	tests := []struct {
+		name      string
+		msg       proto.Message
+		oneofName string
+		wantErr   bool
+	}{
+		{name: "set", msg: structpb.NewStringValue("a"), oneofName: "kind", wantErr: false},
+		{name: "unset", msg: &structpb.Value{}, oneofName: "kind", wantErr: true},
+		{name: "nil message", msg: (*structpb.Value)(nil), oneofName: "kind", wantErr: true},
+		{name: "unknown oneof", msg: structpb.NewStringValue("a"), oneofName: "type", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			v.OneofSet(tt.msg, tt.oneofName)
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("OneofSet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_OneofIs(t *testing.T) {
+	tests := []struct {
+		name          string
+		msg           proto.Message
+		caseFieldName string
+		wantErr       bool
+	}{
+		{name: "set", msg: structpb.NewStringValue("a"), caseFieldName: "string_value", wantErr: false},
+		{name: "unset", msg: &structpb.Value{}, caseFieldName: "string_value", wantErr: true},
+		{name: "wrong case", msg: structpb.NewBoolValue(true), caseFieldName: "string_value", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			v.OneofIs(tt.msg, "kind", tt.caseFieldName)
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("OneofIs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_OneofDescriptions(t *testing.T) {
+	v := NewValidator()
+	v.OneofSet(&structpb.Value{}, "kind")
+	v.OneofIs(structpb.NewBoolValue(true), "kind", "string_value")
+
+	want := []string{
+		"kind must have one of null_value, number_value, string_value, bool_value, struct_value, list_value set",
+		"kind must have string_value set",
+	}
+	rules := v.BrokenRules()
+	if len(rules) != len(want) {
+		t.Fatalf("BrokenRules() got %d rules, want %d", len(rules), len(want))
+	}
+	for i, rule := range rules {
+		if got := rule.Rule(); got != want[i] {
+			t.Errorf("Rule() = %q, want %q", got, want[i])
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Provides rules applicable to raw enum values, i.e. numbers or names, validated against a proto enum descriptor.
+type ProtoEnum struct {
+	standard[protoreflect.EnumNumber]
+	// The descriptor of the enum the value is validated against.
+	enum protoreflect.EnumDescriptor
+	// Indicates if the value corresponds to one of the values of the enum.
+	known bool
+}
+
+// Returns a temporary object for creating rules on an enum number, e.g. a proto enum field read as an int32.
+// A rule asserting that the number corresponds to one of the values of the enum is added straight away.
+func (v *Validator) ProtoEnum(path string, value int32, enum protoreflect.EnumType) *ProtoEnum {
+	number := protoreflect.EnumNumber(value)
+	var desc protoreflect.EnumDescriptor
+	known := false
+	if enum != nil {
+		desc = enum.Descriptor()
+		known = desc.Values().ByNumber(number) != nil
+	}
+	return v.protoEnum(path, number, desc, known)
+}
+
+// Returns a temporary object for creating rules on an enum name, e.g. "ACTIVE".
+// A rule asserting that the name corresponds to one of the values of the enum is added straight away.
+func (v *Validator) ProtoEnumName(path string, value string, enum protoreflect.EnumType) *ProtoEnum {
+	var number protoreflect.EnumNumber
+	var desc protoreflect.EnumDescriptor
+	known := false
+	if enum != nil {
+		desc = enum.Descriptor()
+		if enumValue := desc.Values().ByName(protoreflect.Name(value)); enumValue != nil {
+			number, known = enumValue.Number(), true
+		}
+	}
+	return v.protoEnum(path, number, desc, known)
+}
+
+func (v *Validator) protoEnum(path string, number protoreflect.EnumNumber, desc protoreflect.EnumDescriptor, known bool) *ProtoEnum {
+	r := &ProtoEnum{standard: newStandard(v.fullPath(path), number), enum: desc, known: known}
+	r.add("be one of %s", "is one of %s", known, strings.Join(r.names(true), ", "))
+	v.rules = append(v.rules, r)
+	return r
+}
+
+// Adds a rule to the parent validator asserting that the enum value is known and not the zero (UNSPECIFIED) value.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (e *ProtoEnum) IsSpecified() *ProtoEnum {
+	e.add("be specified (one of %s)", "is specified", e.known && e.value != 0, strings.Join(e.names(false), ", "))
+	return e
+}
+
+// Returns the names of the values of the enum, optionally excluding the zero value.
+func (e *ProtoEnum) names(includeZero bool) []string {
+	if e.enum == nil {
+		return nil
+	}
+	values := e.enum.Values()
+	names := make([]string, 0, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		if !includeZero && values.Get(i).Number() == 0 {
+			continue
+		}
+		names = append(names, string(values.Get(i).Name()))
+	}
+	return names
+}
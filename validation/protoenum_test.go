@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"testing"
+)
+
+func TestValidator_ProtoEnum(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     int32
+		specified bool
+		wantErr   bool
+	}{
+		{name: "valid", value: int32(User_ACTIVE), wantErr: false},
+		{name: "unknown", value: 7, wantErr: true},
+		{name: "unspecified allowed", value: 0, wantErr: false},
+		{name: "unspecified rejected", value: 0, specified: true, wantErr: true},
+		{name: "specified", value: int32(User_INACTIVE), specified: true, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			r := v.ProtoEnum("status", tt.value, User_UNSPECIFIED.Type())
+			if tt.specified {
+				r.IsSpecified()
+			}
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ProtoEnum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_ProtoEnumName(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		specified bool
+		wantErr   bool
+	}{
+		{name: "valid", value: "ACTIVE", wantErr: false},
+		{name: "unknown", value: "DELETED", wantErr: true},
+		{name: "wrong case", value: "active", wantErr: true},
+		{name: "unspecified allowed", value: "UNSPECIFIED", wantErr: false},
+		{name: "unspecified rejected", value: "UNSPECIFIED", specified: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			r := v.ProtoEnumName("status", tt.value, User_UNSPECIFIED.Type())
+			if tt.specified {
+				r.IsSpecified()
+			}
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ProtoEnumName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_ProtoEnum_Description(t *testing.T) {
+	v := NewValidator()
+	v.ProtoEnum("status", 7, User_UNSPECIFIED.Type()).IsSpecified()
+
+	want := "status must be one of UNSPECIFIED, ACTIVE, INACTIVE and be specified (one of ACTIVE, INACTIVE)"
+	if err := v.Validate(); err == nil || err.Error() != want {
+		t.Errorf("Validate() error = %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,46 @@
+package validation
+
+import (
+	"regexp"
+	"sync"
+)
+
+// Precompiled versions of the package's fixed patterns.
+var (
+	emailRegex      = regexp.MustCompile(emailRgx)
+	domainRegex     = regexp.MustCompile(domainRgx)
+	rootDomainRegex = regexp.MustCompile(rootDomainRgx)
+	subDomainRegex  = regexp.MustCompile(subDomainRgx)
+	phoneE164Regex  = regexp.MustCompile(phoneE164Rgx)
+)
+
+// regexCache holds the compiled caller-provided patterns, keyed by pattern.
+// Invalid patterns are cached as well, along with their compilation error.
+var regexCache sync.Map
+
+// cachedRegex is a regexCache entry.
+type cachedRegex struct {
+	regex *regexp.Regexp
+	err   error
+}
+
+// compileRegex returns the compiled pattern, compiling it only the first time it is used.
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := regexCache.Load(pattern); ok {
+		return cached.(cachedRegex).regex, cached.(cachedRegex).err
+	}
+
+	regex, err := regexp.Compile(pattern)
+	regexCache.Store(pattern, cachedRegex{regex: regex, err: err})
+	return regex, err
+}
+
+// matchString reports whether the value matches the pattern.
+// An error is returned if the pattern is invalid, in which case rules should be marked as not satisfied.
+func matchString(pattern string, value string) (bool, error) {
+	regex, err := compileRegex(pattern)
+	if err != nil {
+		return false, err
+	}
+	return regex.MatchString(value), nil
+}
@@ -0,0 +1,55 @@
+package validation
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestString_Matches_InvalidPattern(t *testing.T) {
+	tests := []struct {
+		name string
+		rule func(v *Validator)
+	}{
+		{name: "Matches", rule: func(v *Validator) { v.String("name", "abc").Matches("(") }},
+		{name: "NotMatch", rule: func(v *Validator) { v.String("name", "abc").NotMatch("(") }},
+		{name: "MatchesOneof", rule: func(v *Validator) { v.String("name", "abc").MatchesOneof("(", "[") }},
+		{name: "EachMatches", rule: func(v *Validator) { v.StringList("names", []string{"abc"}).EachMatches("(") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			tt.rule(v)
+			if err := v.Validate(); err == nil {
+				t.Errorf("Validate() error = nil, want an error for an invalid pattern")
+			}
+		})
+	}
+}
+
+func TestCompileRegex_Cached(t *testing.T) {
+	first, err := compileRegex(`^[a-z]+$`)
+	if err != nil {
+		t.Fatalf("compileRegex() error = %v", err)
+	}
+	second, _ := compileRegex(`^[a-z]+$`)
+	if first != second {
+		t.Errorf("compileRegex() returned a new *regexp.Regexp for a cached pattern")
+	}
+	if _, err := compileRegex(`(`); err == nil {
+		t.Errorf("compileRegex() error = nil, want an error for an invalid pattern")
+	}
+}
+
+func BenchmarkMatches(b *testing.B) {
+	const pattern = `^[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`
+	b.Run("Uncompiled", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = regexp.MatchString(pattern, "my-resource-123")
+		}
+	})
+	b.Run("Cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewValidator().String("name", "my-resource-123").Matches(pattern)
+		}
+	})
+}
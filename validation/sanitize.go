@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"strings"
+)
+
+// Describes a change made to a value by a sanitizer.
+type Normalization struct {
+	// Path of the normalized field.
+	Path string
+	// Description of the normalization, e.g. "trimmed whitespace".
+	Description string
+	// Value before the normalization.
+	Before string
+	// Value after the normalization.
+	After string
+}
+
+// Returns all the normalizations which changed a value, in the order in which they were applied.
+func (v *Validator) Normalizations() []Normalization {
+	return v.normalizations
+}
+
+// Applies fn to the referenced value, records the normalization if the value changed, and returns a temporary object
+// for creating rules on the normalized value.
+func (v *Validator) sanitize(path string, value *string, description string, fn func(string) string) *String {
+	if value == nil {
+		return v.String(path, "")
+	}
+	normalized := fn(*value)
+	if normalized != *value {
+		v.normalizations = append(v.normalizations, Normalization{
+			Path:        v.fullPath(path),
+			Description: description,
+			Before:      *value,
+			After:       normalized,
+		})
+		*value = normalized
+	}
+	return v.String(path, normalized)
+}
+
+// Removes leading and trailing whitespace from the referenced string value.
+// Returns a temporary object for creating rules on the normalized value, e.g. v.TrimSpace("name", &name).IsPopulated().
+func (v *Validator) TrimSpace(path string, value *string) *String {
+	return v.sanitize(path, value, "trimmed whitespace", strings.TrimSpace)
+}
+
+// Converts the referenced string value to lower case, which is typically used for email addresses.
+// Returns a temporary object for creating rules on the normalized value, e.g. v.ToLower("email", &email).IsEmail().
+func (v *Validator) ToLower(path string, value *string) *String {
+	return v.sanitize(path, value, "converted to lower case", strings.ToLower)
+}
+
+// Replaces each run of whitespace in the referenced string value with a single space, and removes leading and
+// trailing whitespace.
+// Returns a temporary object for creating rules on the normalized value.
+func (v *Validator) CollapseSpaces(path string, value *string) *String {
+	return v.sanitize(path, value, "collapsed whitespace", func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	})
+}
+
+// Removes the separators commonly used in phone numbers from the referenced string value, i.e. spaces, dashes, dots and
+// parentheses, and replaces the international call prefix 00 with a `+`.
+// Returns a temporary object for creating rules on the normalized value, e.g. v.NormalizePhone("phone", &phone).IsPhoneE164().
+func (v *Validator) NormalizePhone(path string, value *string) *String {
+	return v.sanitize(path, value, "removed phone number separators", func(s string) string {
+		s = phoneSeparatorsReplacer.Replace(strings.TrimSpace(s))
+		if strings.HasPrefix(s, "00") {
+			s = "+" + strings.TrimPrefix(s, "00")
+		}
+		return s
+	})
+}
+
+// phoneSeparatorsReplacer removes the separators used in phone numbers, including non-breaking spaces.
+var phoneSeparatorsReplacer = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "", "\u00a0", "")
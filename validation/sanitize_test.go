@@ -0,0 +1,86 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidator_Sanitizers(t *testing.T) {
+	tests := []struct {
+		name      string
+		sanitize  func(v *Validator, value *string) *String
+		value     string
+		wantValue string
+		wantErr   bool
+	}{
+		{
+			name:      "TrimSpace normalizes and validates",
+			sanitize:  func(v *Validator, value *string) *String { return v.TrimSpace("name", value).IsPopulated() },
+			value:     "  Jane Doe \n",
+			wantValue: "Jane Doe",
+		},
+		{
+			name:      "TrimSpace of whitespace only is not populated",
+			sanitize:  func(v *Validator, value *string) *String { return v.TrimSpace("name", value).IsPopulated() },
+			value:     "   ",
+			wantValue: "",
+			wantErr:   true,
+		},
+		{
+			name: "ToLower email",
+			sanitize: func(v *Validator, value *string) *String {
+				return v.ToLower("email", value).IsEmail()
+			},
+			value:     "Jane.Doe@Example.COM",
+			wantValue: "jane.doe@example.com",
+		},
+		{
+			name: "CollapseSpaces",
+			sanitize: func(v *Validator, value *string) *String {
+				return v.CollapseSpaces("display_name", value).LenLte(8)
+			},
+			value:     "  Jane \t  Doe  ",
+			wantValue: "Jane Doe",
+		},
+		{
+			name: "Sanitizers chained before validation",
+			sanitize: func(v *Validator, value *string) *String {
+				v.TrimSpace("email", value)
+				return v.ToLower("email", value).IsEmail()
+			},
+			value:     " not an email ",
+			wantValue: "not an email",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			value := tt.value
+			tt.sanitize(v, &value)
+			if value != tt.wantValue {
+				t.Errorf("value = %q, want %q", value, tt.wantValue)
+			}
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Normalizations(t *testing.T) {
+	v := NewValidator()
+	name, email := "Jane", " Jane@Example.com"
+	v.TrimSpace("name", &name)
+	v.TrimSpace("email", &email)
+	v.ToLower("email", &email)
+	v.TrimSpace("missing", nil)
+
+	want := []Normalization{
+		{Path: "email", Description: "trimmed whitespace", Before: " Jane@Example.com", After: "Jane@Example.com"},
+		{Path: "email", Description: "converted to lower case", Before: "Jane@Example.com", After: "jane@example.com"},
+	}
+	if got := v.Normalizations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Normalizations() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Returns a gRPC InvalidArgument status error describing all the broken rules, if any, and nil otherwise.
+// The status carries a BadRequest detail with a field violation for each field of each broken rule. If a code was set
+// on the rule using WithCode, the description of the violation is prefixed with it, e.g. "NAME_TAKEN: name must ...".
+func (v *Validator) StatusErr() error {
+	err := v.Validate()
+	if err == nil {
+		return nil
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, r := range v.BrokenRules() {
+		description := r.Rule()
+		if c, ok := r.(interface{ code() string }); ok && c.code() != "" {
+			description = c.code() + ": " + description
+		}
+		fields := r.Fields()
+		if len(fields) == 0 {
+			fields = []string{""}
+		}
+		for _, field := range fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: description,
+			})
+		}
+	}
+
+	st, detailsErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(badRequest)
+	if detailsErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return st.Err()
+}
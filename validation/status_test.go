@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidator_StatusErr(t *testing.T) {
+	v := NewValidator()
+	v.String("name", "").IsPopulated()
+	v.Int("age", 5).Gte(0)
+	v.Custom("email must be unique", false, "email").WithCode("EMAIL_TAKEN")
+
+	err := v.StatusErr()
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("StatusErr() = %v, want an InvalidArgument status", err)
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			violations = append(violations, badRequest.GetFieldViolations()...)
+		}
+	}
+	want := []struct{ field, description string }{
+		{"name", "name must be populated"},
+		{"email", "EMAIL_TAKEN: email must be unique"},
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("StatusErr() violations = %v, want %d", violations, len(want))
+	}
+	for i, w := range want {
+		if violations[i].GetField() != w.field || violations[i].GetDescription() != w.description {
+			t.Errorf("violation[%d] = %v, want {%s %s}", i, violations[i], w.field, w.description)
+		}
+	}
+}
+
+func TestValidator_StatusErr_Valid(t *testing.T) {
+	v := NewValidator()
+	v.String("name", "abc").IsPopulated()
+
+	if err := v.StatusErr(); err != nil {
+		t.Errorf("StatusErr() = %v, want nil", err)
+	}
+}
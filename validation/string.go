@@ -1,8 +1,9 @@
 package validation
 
 import (
-	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
@@ -10,6 +11,9 @@ const (
 	domainRgx     = `^([a-zA-Z0-9]+(-[a-zA-Z0-9]+)*\.)+[a-zA-Z]{2,}$`
 	rootDomainRgx = `^([a-zA-Z0-9]+(-[a-zA-Z0-9]+)*\.)[a-zA-Z]{2,}$`
 	subDomainRgx  = `^([a-zA-Z0-9]+(-[a-zA-Z0-9]+)*\.){2,}[a-zA-Z]{2,}$`
+	// E.164 numbers consist of a country code, which never starts with 0, and a subscriber number, together at least 7
+	// and at most 15 digits.
+	phoneE164Rgx = `^\+[1-9][0-9]{6,14}$`
 )
 
 type String struct {
@@ -163,10 +167,55 @@ func (s *String) LenLte(length int) *String {
 	return s
 }
 
+// Adds a rule to the parent validator asserting that the string value is at most the given number of bytes long when UTF-8 encoded.
+// This is useful for stores which limit values in bytes, since multi-byte characters count more than once.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (s *String) ByteLenLte(max int) *String {
+	s.add("be at most %v bytes long", "is at most %v bytes long", len(s.value) <= max, max)
+	return s
+}
+
+// Adds a rule to the parent validator asserting that the string value is exactly the given number of characters (runes) long.
+// Unlike LenEq, which counts bytes, multi-byte characters only count once.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (s *String) RuneLenEq(length int) *String {
+	s.add("be exactly %v characters long", "is exactly %v characters long", utf8.RuneCountInString(s.value) == length, length)
+	return s
+}
+
+// Adds a rule to the parent validator asserting that the string value is more than the given number of characters (runes) long.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (s *String) RuneLenGt(min int) *String {
+	s.add("be more than %v characters long", "is more than %v characters long", utf8.RuneCountInString(s.value) > min, min)
+	return s
+}
+
+// Adds a rule to the parent validator asserting that the string value is at least the given number of characters (runes) long.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (s *String) RuneLenGte(min int) *String {
+	s.add("be at least %v characters long", "is at least %v characters long", utf8.RuneCountInString(s.value) >= min, min)
+	return s
+}
+
+// Adds a rule to the parent validator asserting that the string value is less than the given number of characters (runes) long.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (s *String) RuneLenLt(max int) *String {
+	s.add("be less than %v characters long", "is less than %v characters long", utf8.RuneCountInString(s.value) < max, max)
+	return s
+}
+
+// Adds a rule to the parent validator asserting that the string value is at most the given number of characters (runes) long.
+// This is useful for stores which limit values in characters, such as Spanner STRING(N) columns.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (s *String) RuneLenLte(max int) *String {
+	s.add("be at most %v characters long", "is at most %v characters long", utf8.RuneCountInString(s.value) <= max, max)
+	return s
+}
+
 // Adds a rule to the parent validator asserting that the string value matches the given pattern.
 // If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
 func (s *String) Matches(pattern string) *String {
-	satisfied, err := regexp.MatchString(pattern, s.value)
+	satisfied, err := matchString(pattern, s.value)
 	if err != nil {
 		satisfied = false
 	}
@@ -179,7 +228,7 @@ func (s *String) Matches(pattern string) *String {
 func (s *String) MatchesOneof(patterns ...string) *String {
 	satisfied := false
 	for _, pattern := range patterns {
-		if matched, err := regexp.MatchString(pattern, s.value); err == nil && matched {
+		if matched, err := matchString(pattern, s.value); err == nil && matched {
 			satisfied = true
 			break
 		}
@@ -193,7 +242,7 @@ func (s *String) MatchesOneof(patterns ...string) *String {
 func (s *String) MatchesNoneof(patterns ...string) *String {
 	satisfied := true
 	for _, pattern := range patterns {
-		if matched, err := regexp.MatchString(pattern, s.value); err == nil && matched {
+		if matched, err := matchString(pattern, s.value); err == nil && matched {
 			satisfied = false
 			break
 		}
@@ -203,23 +252,19 @@ func (s *String) MatchesNoneof(patterns ...string) *String {
 }
 
 // Adds a rule to the parent validator asserting that the string value does not match the given pattern.
+// An invalid pattern results in the rule not being satisfied.
 // If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
 func (s *String) NotMatch(pattern string) *String {
-	satisfied, err := regexp.MatchString(pattern, s.value)
-	if err != nil {
-		satisfied = false
-	}
-	s.add("not match %v", "does not match %v", !satisfied, pattern)
+	matched, err := matchString(pattern, s.value)
+	satisfied := err == nil && !matched
+	s.add("not match %v", "does not match %v", satisfied, pattern)
 	return s
 }
 
 // Adds a rule to the parent validator asserting that the string value is a valid email.
 // If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
 func (s *String) IsEmail() *String {
-	satisfied, err := regexp.MatchString(emailRgx, s.value)
-	if err != nil {
-		satisfied = false
-	}
+	satisfied := emailRegex.MatchString(s.value)
 	s.add("be a valid email", "is a valid email", satisfied)
 	return s
 }
@@ -227,10 +272,7 @@ func (s *String) IsEmail() *String {
 // Adds a rule to the parent validator asserting that the string value is a valid domain.
 // If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
 func (s *String) IsDomain() *String {
-	satisfied, err := regexp.MatchString(domainRgx, s.value)
-	if err != nil {
-		satisfied = false
-	}
+	satisfied := domainRegex.MatchString(s.value)
 	s.add("be a valid domain", "is a valid domain", satisfied)
 	return s
 }
@@ -238,10 +280,7 @@ func (s *String) IsDomain() *String {
 // Adds a rule to the parent validator asserting that the string value is a valid root domain.
 // If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
 func (s *String) IsRootDomain() *String {
-	satisfied, err := regexp.MatchString(rootDomainRgx, s.value)
-	if err != nil {
-		satisfied = false
-	}
+	satisfied := rootDomainRegex.MatchString(s.value)
 	s.add("be a valid root domain", "is a valid root domain", satisfied)
 	return s
 }
@@ -249,10 +288,42 @@ func (s *String) IsRootDomain() *String {
 // Adds a rule to the parent validator asserting that the string value is a valid sub domain.
 // If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
 func (s *String) IsSubDomain() *String {
-	satisfied, err := regexp.MatchString(subDomainRgx, s.value)
-	if err != nil {
-		satisfied = false
-	}
+	satisfied := subDomainRegex.MatchString(s.value)
 	s.add("be a valid sub domain", "is a valid sub domain", satisfied)
 	return s
 }
+
+// Adds a rule to the parent validator asserting that the string value is a phone number in E.164 format, i.e. a `+`
+// followed by the country code and subscriber number, e.g. +14155552671. Separators such as spaces and dashes are not
+// allowed, use NormalizePhone to remove them first.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (s *String) IsPhoneE164() *String {
+	satisfied := phoneE164Regex.MatchString(s.value)
+	s.add("be a valid E.164 phone number", "is a valid E.164 phone number", satisfied)
+	return s
+}
+
+// Adds a rule to the parent validator asserting that the string value only contains printable characters, i.e. no
+// control characters (Unicode category Cc, including tabs and newlines) nor format characters (Unicode category Cf, such
+// as zero-width spaces and joiners). Invalid UTF-8 is rejected as well.
+// This is useful for single-line values shown in UIs, such as display names.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (s *String) IsPrintable() *String {
+	satisfied := utf8.ValidString(s.value) && !strings.ContainsFunc(s.value, func(r rune) bool {
+		return unicode.Is(unicode.Cc, r) || unicode.Is(unicode.Cf, r)
+	})
+	s.add("only contain printable characters", "only contains printable characters", satisfied)
+	return s
+}
+
+// Adds a rule to the parent validator asserting that the string value contains no control characters (Unicode category
+// Cc), other than tabs, newlines and carriage returns. Invalid UTF-8 is rejected as well.
+// This is useful for multi-line values, such as descriptions.
+// If wrapped inside Or, If or Then, the rule itself is not added, but rather combined with the intent of the wrapper and the other rules inside it.
+func (s *String) HasNoControlChars() *String {
+	satisfied := utf8.ValidString(s.value) && !strings.ContainsFunc(s.value, func(r rune) bool {
+		return unicode.Is(unicode.Cc, r) && r != '\t' && r != '\n' && r != '\r'
+	})
+	s.add("not contain control characters", "does not contain control characters", satisfied)
+	return s
+}
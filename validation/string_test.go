@@ -0,0 +1,205 @@
+package validation
+
+import "testing"
+
+func TestValidator_ByteLengthLte(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		max     int
+		wantErr bool
+	}{
+		{name: "ascii within limit", value: "hello", max: 5, wantErr: false},
+		{name: "ascii exceeds limit", value: "hello!", max: 5, wantErr: true},
+		// "héllo" is 5 characters but 6 bytes
+		{name: "multi-byte exceeds byte limit", value: "héllo", max: 5, wantErr: true},
+		// "日本語" is 3 characters but 9 bytes
+		{name: "cjk within byte limit", value: "日本語", max: 9, wantErr: false},
+		{name: "cjk exceeds byte limit", value: "日本語", max: 8, wantErr: true},
+		{name: "empty", value: "", max: 0, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			v.ByteLengthLte("name", tt.value, tt.max)
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ByteLengthLte() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_RuneLengthLte(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		max     int
+		wantErr bool
+	}{
+		{name: "ascii within limit", value: "hello", max: 5, wantErr: false},
+		{name: "ascii exceeds limit", value: "hello!", max: 5, wantErr: true},
+		// "héllo" is 5 characters but 6 bytes
+		{name: "multi-byte within rune limit", value: "héllo", max: 5, wantErr: false},
+		// "日本語" is 3 characters but 9 bytes
+		{name: "cjk within rune limit", value: "日本語", max: 3, wantErr: false},
+		{name: "cjk exceeds rune limit", value: "日本語", max: 2, wantErr: true},
+		{name: "emoji counts as a single rune", value: "👍", max: 1, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			v.RuneLengthLte("name", tt.value, tt.max)
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("RuneLengthLte() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestString_ByteAndRuneLenDescriptions(t *testing.T) {
+	v := NewValidator()
+	v.String("name", "日本語").ByteLenLte(4).RuneLenLte(2)
+	want := "name must be at most 4 bytes long and be at most 2 characters long"
+	if err := v.Validate(); err == nil || err.Error() != want {
+		t.Errorf("Validate() error = %v, want %v", err, want)
+	}
+}
+
+func TestValidator_Printable(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"ascii", "Jane Doe", false},
+		{"unicode", "Zoë 日本語 😀", false},
+		{"empty", "", false},
+		{"newline", "Jane\nDoe", true},
+		{"tab", "Jane\tDoe", true},
+		{"null", "Jane\x00", true},
+		{"zero-width space", "Jane\u200bDoe", true},
+		{"zero-width joiner", "Jane\u200dDoe", true},
+		{"bidi override", "\u202eeoD enaJ", true},
+		{"invalid utf-8", "Jane\xff", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			v.Printable("display_name", tt.value)
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Printable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_NoControlChars(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"unicode", "Zoë 日本語 😀", false},
+		{"newline and tab", "line 1\n\tline 2\r\n", false},
+		{"zero-width space", "Jane\u200bDoe", false},
+		{"null", "Jane\x00", true},
+		{"escape", "\x1b[31mred", true},
+		{"c1 control", "Jane\u0085Doe", true},
+		{"invalid utf-8", "Jane\xff", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			v.NoControlChars("description", tt.value)
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("NoControlChars() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_PhoneE164(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"us", "+14155552671", false},
+		{"uk", "+442071838750", false},
+		{"short country", "+6831234", false},
+		{"max length", "+123456789012345", false},
+		{"missing plus", "14155552671", true},
+		{"spaces", "+1 415 555 2671", true},
+		{"dashes", "+1-415-555-2671", true},
+		{"leading zero country code", "+0123456789", true},
+		{"too long", "+1234567890123456", true},
+		{"too short", "+12345", true},
+		{"letters", "+1415555CALL", true},
+		{"empty", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			v.PhoneE164("phone", tt.value)
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("PhoneE164() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_NormalizePhone(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		wantValue string
+		wantErr   bool
+	}{
+		{"separators", " +1 (415) 555-2671 ", "+14155552671", false},
+		{"dots", "+44.20.7183.8750", "+442071838750", false},
+		{"international prefix", "00 27 21 123 4567", "+27211234567", false},
+		{"national number", "(415) 555-2671", "4155552671", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			value := tt.value
+			v.NormalizePhone("phone", &value).IsPhoneE164()
+			if value != tt.wantValue {
+				t.Errorf("NormalizePhone() value = %q, want %q", value, tt.wantValue)
+			}
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestString_RuneLen(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		// "日本語" is 3 characters but 9 bytes
+		{name: "cjk within rune bounds", value: "日本語", wantErr: false},
+		{name: "ascii within rune bounds", value: "abcd", wantErr: false},
+		{name: "too short", value: "ab", wantErr: true},
+		{name: "too long", value: "héllo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			v.String("name", tt.value).RuneLenGte(3).RuneLenLt(5).RuneLenGt(2)
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("RuneLen() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	v := NewValidator()
+	v.String("name", "日本語").RuneLenEq(3)
+	if err := v.Validate(); err != nil {
+		t.Errorf("RuneLenEq() error = %v, want nil", err)
+	}
+}
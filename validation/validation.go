@@ -2,8 +2,10 @@ package validation
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -13,6 +15,10 @@ import (
 type Validator struct {
 	// List of validation rules.
 	rules []Rule
+	// Prefix added to the paths of all the rules, e.g. "emails[2]" when used inside Each.
+	prefix string
+	// List of normalizations applied by sanitizers.
+	normalizations []Normalization
 }
 
 // Defines the methods that a validation rule must implement.
@@ -46,6 +52,69 @@ func NewValidator() *Validator {
 	return &Validator{}
 }
 
+// Returns the path prefixed with the prefix of the validator, if any.
+func (v *Validator) fullPath(path string) string {
+	switch {
+	case v.prefix == "":
+		return path
+	case path == "":
+		return v.prefix
+	default:
+		return v.prefix + "." + path
+	}
+}
+
+// Invokes fn for each index of a list with count elements, passing a validator whose rule paths are prefixed with the
+// indexed path, e.g. "emails[2]". Use an empty path inside fn to refer to the element itself.
+//
+// Example:
+//
+//	v.Each("emails", len(emails), func(i int, v *Validator) {
+//		v.String("", emails[i]).IsEmail()
+//	})
+func (v *Validator) Each(path string, count int, fn func(i int, v *Validator)) {
+	for i := 0; i < count; i++ {
+		elem := &Validator{prefix: fmt.Sprintf("%s[%d]", v.fullPath(path), i)}
+		fn(i, elem)
+		v.rules = append(v.rules, elem.rules...)
+		v.normalizations = append(v.normalizations, elem.normalizations...)
+	}
+}
+
+// Invokes fn for each message of a repeated message field, passing the message along with a validator whose rule paths
+// are prefixed with the indexed path, e.g. "users[2]", same as Each. The rules of all the messages are consolidated
+// into the error of v. Assert the message to its concrete type inside fn to validate its fields.
+//
+// Example:
+//
+//	v.EachMessage("users", msgs, func(i int, m proto.Message, v *Validator) {
+//		user := m.(*pb.User)
+//		v.String("name", user.GetName()).IsPopulated()
+//	})
+func (v *Validator) EachMessage(path string, msgs []proto.Message, fn func(i int, m proto.Message, v *Validator)) {
+	v.Each(path, len(msgs), func(i int, v *Validator) {
+		fn(i, msgs[i], v)
+	})
+}
+
+// Appends all the rules and normalizations of the other validators to v, allowing validation to be composed from
+// validators built independently, e.g. one per concern. Conditional and Or rules are merged with the rules they wrap.
+//
+// Example:
+//
+//	v := validateName(req)
+//	v.Merge(validateAddress(req), validateContact(req))
+//	err := v.Validate()
+func (v *Validator) Merge(others ...*Validator) {
+	for _, o := range others {
+		if o == nil || o == v {
+			continue
+		}
+		v.rules = append(v.rules, o.rules...)
+		v.normalizations = append(v.normalizations, o.normalizations...)
+	}
+}
+
 // Returns all the rules that have been added.
 func (v *Validator) Rules() []Rule {
 	finalRules := []Rule{}
@@ -109,6 +178,23 @@ func (v *Validator) Or(rules ...Rule) {
 
 // Creates a ConditionalApplier that applies rules if all conditions are satisfied.
 func (v *Validator) If(conditions ...Condition) *ConditionalApplier {
+	return v.conditionalApplier(conditions, " and ", true)
+}
+
+// Creates a ConditionalApplier that applies rules if all conditions are satisfied, same as If.
+func (v *Validator) IfAll(conditions ...Condition) *ConditionalApplier {
+	return v.conditionalApplier(conditions, " and ", true)
+}
+
+// Creates a ConditionalApplier that applies rules if any of the conditions is satisfied.
+// Unlike Or, which requires any of the provided rules to be satisfied, IfAny only gates the rules passed to Then,
+// which are all required if any condition holds, e.g. v.IfAny(a, b).Then(c, d) requires c and d if a or b holds.
+func (v *Validator) IfAny(conditions ...Condition) *ConditionalApplier {
+	return v.conditionalApplier(conditions, " or ", false)
+}
+
+// Returns a ConditionalApplier for the conditions, combined with AND if all is true, otherwise with OR.
+func (v *Validator) conditionalApplier(conditions []Condition, separator string, all bool) *ConditionalApplier {
 	if len(conditions) == 0 {
 		return nil
 	}
@@ -119,12 +205,19 @@ func (v *Validator) If(conditions ...Condition) *ConditionalApplier {
 
 	// setup description and satisfied
 	descriptions := []string{}
-	satisfied := true
+	satisfied := all
 	for _, c := range conditions {
 		descriptions = append(descriptions, c.condition())
-		satisfied = satisfied && c.Satisfied()
+		if all {
+			satisfied = satisfied && c.Satisfied()
+		} else {
+			satisfied = satisfied || c.Satisfied()
+		}
+	}
+	description := strings.Join(descriptions, separator)
+	if !all && len(conditions) > 1 {
+		description = "either " + description
 	}
-	description := strings.Join(descriptions, " and ")
 
 	// return the conditional applier
 	return &ConditionalApplier{v: v, description: description, satisfied: satisfied}
@@ -184,6 +277,8 @@ type CustomRule struct {
 	paths []string
 	// Indicates if the rule is wrapped.
 	isWrapped bool
+	// Machine readable code of the rule, reported by StatusErr.
+	violationCode string
 }
 
 // Returns the rule description.
@@ -191,6 +286,17 @@ func (c *CustomRule) Rule() string {
 	return c.rule
 }
 
+// Sets a machine readable code, e.g. "NAME_TAKEN", which StatusErr reports alongside the description of the rule.
+func (c *CustomRule) WithCode(code string) *CustomRule {
+	c.violationCode = code
+	return c
+}
+
+// Returns the machine readable code of the rule, if any.
+func (c *CustomRule) code() string {
+	return c.violationCode
+}
+
 // Returns the condition description.
 func (c *CustomRule) condition() string {
 	return c.cond
@@ -235,220 +341,272 @@ func (v *Validator) CustomEvaluated(description string, satisfiedFunc func() boo
 
 // Returns a temporary object for creating rules on a string field.
 func (v *Validator) String(path, value string) *String {
-	r := &String{newStandard(path, value)}
+	r := &String{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
+// Adds a rule asserting that the string value is at most max bytes long when UTF-8 encoded.
+// It is shorthand for v.String(path, value).ByteLenLte(max).
+func (v *Validator) ByteLengthLte(path, value string, max int) *String {
+	return v.String(path, value).ByteLenLte(max)
+}
+
+// Adds a rule asserting that the string value is at most max characters (runes) long.
+// It is shorthand for v.String(path, value).RuneLenLte(max).
+func (v *Validator) RuneLengthLte(path, value string, max int) *String {
+	return v.String(path, value).RuneLenLte(max)
+}
+
+// Adds a rule asserting that the string value only contains printable characters.
+// It is shorthand for v.String(path, value).IsPrintable().
+func (v *Validator) Printable(path, value string) *String {
+	return v.String(path, value).IsPrintable()
+}
+
+// Adds a rule asserting that the string value contains no control characters, other than tabs and newlines.
+// It is shorthand for v.String(path, value).HasNoControlChars().
+func (v *Validator) NoControlChars(path, value string) *String {
+	return v.String(path, value).HasNoControlChars()
+}
+
+// Adds a rule asserting that the string value is a phone number in E.164 format.
+// It is shorthand for v.String(path, value).IsPhoneE164().
+func (v *Validator) PhoneE164(path, value string) *String {
+	return v.String(path, value).IsPhoneE164()
+}
+
 // Returns a temporary object for creating rules on an int field.
 func (v *Validator) Int(path string, value int) *Number[int] {
-	r := &Number[int]{newStandard(path, value)}
+	r := &Number[int]{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on an int8 field.
 func (v *Validator) Int8(path string, value int8) *Number[int8] {
-	r := &Number[int8]{newStandard(path, value)}
+	r := &Number[int8]{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on an int16 field.
 func (v *Validator) Int16(path string, value int16) *Number[int16] {
-	r := &Number[int16]{newStandard(path, value)}
+	r := &Number[int16]{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on an int32 field.
 func (v *Validator) Int32(path string, value int32) *Number[int32] {
-	r := &Number[int32]{newStandard(path, value)}
+	r := &Number[int32]{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on an int64 field.
 func (v *Validator) Int64(path string, value int64) *Number[int64] {
-	r := &Number[int64]{newStandard(path, value)}
+	r := &Number[int64]{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a float32 field.
 func (v *Validator) Float32(path string, value float32) *Number[float32] {
-	r := &Number[float32]{newStandard(path, value)}
+	r := &Number[float32]{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a float64 field.
 func (v *Validator) Float64(path string, value float64) *Number[float64] {
-	r := &Number[float64]{newStandard(path, value)}
+	r := &Number[float64]{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a uint field.
 func (v *Validator) Uint(path string, value uint) *Number[uint] {
-	r := &Number[uint]{newStandard(path, value)}
+	r := &Number[uint]{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a uint8 field.
 func (v *Validator) Uint8(path string, value uint8) *Number[uint8] {
-	r := &Number[uint8]{newStandard(path, value)}
+	r := &Number[uint8]{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a uint16 field.
 func (v *Validator) Uint16(path string, value uint16) *Number[uint16] {
-	r := &Number[uint16]{newStandard(path, value)}
+	r := &Number[uint16]{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a uint32 field.
 func (v *Validator) Uint32(path string, value uint32) *Number[uint32] {
-	r := &Number[uint32]{newStandard(path, value)}
+	r := &Number[uint32]{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a uint64 field.
 func (v *Validator) Uint64(path string, value uint64) *Number[uint64] {
-	r := &Number[uint64]{newStandard(path, value)}
+	r := &Number[uint64]{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a bool field.
 func (v *Validator) Bool(path string, value bool) *Bool {
-	r := &Bool{newStandard(path, value)}
+	r := &Bool{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a string list field.
 func (v *Validator) StringList(path string, value []string) *StringList {
-	r := &StringList{newList(path, value)}
+	r := &StringList{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on an int list field.
 func (v *Validator) IntList(path string, value []int) *NumberList[int] {
-	r := &NumberList[int]{newList(path, value)}
+	r := &NumberList[int]{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on an int8 list field.
 func (v *Validator) Int8List(path string, value []int8) *NumberList[int8] {
-	r := &NumberList[int8]{newList(path, value)}
+	r := &NumberList[int8]{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on an int16 list field.
 func (v *Validator) Int16List(path string, value []int16) *NumberList[int16] {
-	r := &NumberList[int16]{newList(path, value)}
+	r := &NumberList[int16]{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on an int32 list field.
 func (v *Validator) Int32List(path string, value []int32) *NumberList[int32] {
-	r := &NumberList[int32]{newList(path, value)}
+	r := &NumberList[int32]{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on an int64 list field.
 func (v *Validator) Int64List(path string, value []int64) *NumberList[int64] {
-	r := &NumberList[int64]{newList(path, value)}
+	r := &NumberList[int64]{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a float32 list field.
 func (v *Validator) Float32List(path string, value []float32) *NumberList[float32] {
-	r := &NumberList[float32]{newList(path, value)}
+	r := &NumberList[float32]{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a float64 list field.
 func (v *Validator) Float64List(path string, value []float64) *NumberList[float64] {
-	r := &NumberList[float64]{newList(path, value)}
+	r := &NumberList[float64]{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a uint list field.
 func (v *Validator) UintList(path string, value []uint) *NumberList[uint] {
-	r := &NumberList[uint]{newList(path, value)}
+	r := &NumberList[uint]{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a uint8 list field.
 func (v *Validator) Uint8List(path string, value []uint8) *NumberList[uint8] {
-	r := &NumberList[uint8]{newList(path, value)}
+	r := &NumberList[uint8]{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a uint16 list field.
 func (v *Validator) Uint16List(path string, value []uint16) *NumberList[uint16] {
-	r := &NumberList[uint16]{newList(path, value)}
+	r := &NumberList[uint16]{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a uint32 list field.
 func (v *Validator) Uint32List(path string, value []uint32) *NumberList[uint32] {
-	r := &NumberList[uint32]{newList(path, value)}
+	r := &NumberList[uint32]{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a uint64 list field.
 func (v *Validator) Uint64List(path string, value []uint64) *NumberList[uint64] {
-	r := &NumberList[uint64]{newList(path, value)}
+	r := &NumberList[uint64]{newList(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
+// Returns a temporary object for creating rules on a list field of any element type, e.g. a repeated message field.
+// This is a function rather than a method of the Validator, since methods cannot have type parameters.
+//
+//	validation.ListOf(v, "items", req.GetItems()).IsPopulated().LengthLte(100)
+func ListOf[T any](v *Validator, path string, value []T) *List[T] {
+	r := newList(v.fullPath(path), value)
+	v.rules = append(v.rules, &r)
+	return &r
+}
+
+// Returns a temporary object for creating rules on a map field.
+// This is a function rather than a method of the Validator, since methods cannot have type parameters.
+//
+//	validation.MapOf(v, "labels", req.GetLabels()).LengthLte(64)
+func MapOf[K comparable, V any](v *Validator, path string, value map[K]V) *Map[K, V] {
+	r := newMap(v.fullPath(path), value)
+	v.rules = append(v.rules, &r)
+	return &r
+}
+
 // Returns a temporary object for creating rules on an enum field.
 func (v *Validator) Enum(path string, value protoreflect.Enum) *Enum {
-	r := &Enum{newStandard(path, value)}
+	r := &Enum{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a timestamp field.
 func (v *Validator) Timestamp(path string, value *timestamppb.Timestamp) *Timestamp {
-	r := &Timestamp{newStandard(path, value)}
+	r := &Timestamp{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a duration field.
 func (v *Validator) Duration(path string, value *durationpb.Duration) *Duration {
-	r := &Duration{newStandard(path, value)}
+	r := &Duration{newStandard(v.fullPath(path), value)}
 	v.rules = append(v.rules, r)
 	return r
 }
 
 // Returns a temporary object for creating rules on a message field.
 func (v *Validator) MessageIsPopulated(path string, isPopulated bool) *CustomRule {
+	path = v.fullPath(path)
 	return v.Custom(path+" must be populated", isPopulated, path)
 }
 
 // Returns a temporary object for creating rules on a message field.
 func (v *Validator) EachMessagePopulated(path string, isPopulated bool) *CustomRule {
+	path = v.fullPath(path)
 	return v.Custom(path+" must have all values populated", isPopulated, path)
 }
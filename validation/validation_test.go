@@ -0,0 +1,228 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestValidator_Each(t *testing.T) {
+	tests := []struct {
+		name      string
+		emails    []string
+		wantPaths []string
+	}{
+		{
+			name:      "all valid",
+			emails:    []string{"a@example.com", "b@example.com"},
+			wantPaths: []string{},
+		},
+		{
+			name:      "mix of valid and invalid",
+			emails:    []string{"a@example.com", "not-an-email", "c@example.com", ""},
+			wantPaths: []string{"emails[1]", "emails[3]"},
+		},
+		{
+			name:      "empty list",
+			emails:    nil,
+			wantPaths: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			v.Each("emails", len(tt.emails), func(i int, v *Validator) {
+				v.String("", tt.emails[i]).IsEmail()
+			})
+
+			gotPaths := []string{}
+			for _, r := range v.BrokenRules() {
+				gotPaths = append(gotPaths, r.Fields()...)
+			}
+			if !reflect.DeepEqual(gotPaths, tt.wantPaths) {
+				t.Errorf("BrokenRules() paths = %v, want %v", gotPaths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestValidator_EachNested(t *testing.T) {
+	type contact struct {
+		name   string
+		emails []string
+	}
+	contacts := []contact{
+		{name: "Jane", emails: []string{"jane@example.com"}},
+		{name: "", emails: []string{"john@example.com", "john"}},
+	}
+
+	v := NewValidator()
+	v.Each("contacts", len(contacts), func(i int, v *Validator) {
+		v.String("name", contacts[i].name).IsPopulated()
+		v.Each("emails", len(contacts[i].emails), func(j int, v *Validator) {
+			v.String("", contacts[i].emails[j]).IsEmail()
+		})
+	})
+
+	want := "contacts[1].name must be populated; contacts[1].emails[1] must be a valid email"
+	if err := v.Validate(); err == nil || err.Error() != want {
+		t.Errorf("Validate() error = %v, want %v", err, want)
+	}
+}
+
+func TestValidator_EachMessage(t *testing.T) {
+	users := []proto.Message{
+		&User{Name: "Jane", Email: "jane@example.com"},
+		&User{Name: "", Email: "john@example.com"},
+		&User{Name: "Bob", Email: "bob"},
+	}
+
+	v := NewValidator()
+	v.EachMessage("users", users, func(i int, m proto.Message, v *Validator) {
+		user := m.(*User)
+		v.String("name", user.GetName()).IsPopulated()
+		v.String("email", user.GetEmail()).IsEmail()
+	})
+
+	want := "users[1].name must be populated; users[2].email must be a valid email"
+	if err := v.Validate(); err == nil || err.Error() != want {
+		t.Errorf("Validate() error = %v, want %v", err, want)
+	}
+}
+
+func TestValidator_Merge(t *testing.T) {
+	name := NewValidator()
+	name.String("name", "").IsPopulated()
+
+	contact := NewValidator()
+	email := contact.String("email", "not-an-email").IsEmail()
+	phone := contact.String("phone", "").IsPopulated()
+	contact.Or(email, phone)
+	contact.If(contact.String("name", "").IsPopulated()).Then(contact.String("age", "").IsPopulated())
+	contact.Int("age", 5).Gte(0)
+
+	v := NewValidator()
+	v.Merge(name, contact, nil)
+
+	if got, want := len(v.Rules()), len(name.Rules())+len(contact.Rules()); got != want {
+		t.Errorf("len(Rules()) = %v, want %v", got, want)
+	}
+
+	gotPaths := []string{}
+	for _, r := range v.BrokenRules() {
+		gotPaths = append(gotPaths, r.Fields()...)
+	}
+	wantPaths := []string{"name", "email", "phone"}
+	if !reflect.DeepEqual(gotPaths, wantPaths) {
+		t.Errorf("BrokenRules() paths = %v, want %v", gotPaths, wantPaths)
+	}
+	if v.Validate() == nil {
+		t.Errorf("Validate() = nil, want error")
+	}
+}
+
+func TestListOf(t *testing.T) {
+	type item struct{ id int }
+	tests := []struct {
+		name    string
+		value   []*item
+		wantErr bool
+	}{
+		{name: "within bounds", value: []*item{{1}, {2}}, wantErr: false},
+		{name: "too short", value: []*item{{1}}, wantErr: true},
+		{name: "too long", value: []*item{{1}, {2}, {3}, {4}}, wantErr: true},
+		{name: "nil", value: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			ListOf(v, "items", tt.value).LengthGte(2).LengthLt(4)
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ListOf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMapOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   map[string]string
+		wantErr bool
+	}{
+		{name: "within bounds", value: map[string]string{"a": "1", "b": "2"}, wantErr: false},
+		{name: "too many entries", value: map[string]string{"a": "1", "b": "2", "c": "3"}, wantErr: true},
+		{name: "empty", value: map[string]string{}, wantErr: true},
+		{name: "nil", value: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			MapOf(v, "labels", tt.value).IsPopulated().LengthLte(2)
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("MapOf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListOf_Description(t *testing.T) {
+	v := NewValidator()
+	MapOf(v, "labels", map[string]int{"a": 1}).LengthEq(2)
+	ListOf(v, "items", []bool{true}).IsEmpty()
+	err := v.Validate()
+	if err == nil {
+		t.Fatalf("Validate() error = nil, want an error")
+	}
+	for _, want := range []string{"labels must have a length equal to 2", "items must be empty"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error = %q, want it to contain %q", err.Error(), want)
+		}
+	}
+}
+
+func TestValidator_IfAllIfAny(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		phone   string
+		any     bool
+		wantErr bool
+	}{
+		{name: "all: both conditions hold", email: "a@b.c", phone: "+27821234567", any: false, wantErr: true},
+		{name: "all: one condition holds", email: "a@b.c", phone: "", any: false, wantErr: false},
+		{name: "all: no condition holds", email: "", phone: "", any: false, wantErr: false},
+		{name: "any: both conditions hold", email: "a@b.c", phone: "+27821234567", any: true, wantErr: true},
+		{name: "any: one condition holds", email: "", phone: "+27821234567", any: true, wantErr: true},
+		{name: "any: no condition holds", email: "", phone: "", any: true, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			conditions := []Condition{v.String("email", tt.email).IsPopulated(), v.String("phone", tt.phone).IsPopulated()}
+			applier := v.IfAll(conditions...)
+			if tt.any {
+				applier = v.IfAny(conditions...)
+			}
+			applier.Then(v.String("name", "").IsPopulated())
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_IfAny_Description(t *testing.T) {
+	v := NewValidator()
+	v.IfAny(v.String("email", "a@b.c").IsPopulated(), v.String("phone", "").IsPopulated()).Then(v.String("name", "").IsPopulated())
+	err := v.Validate()
+	if err == nil {
+		t.Fatalf("Validate() error = nil, want an error")
+	}
+	want := "if either email is populated or phone is populated, name must be populated"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("Validate() error = %q, want it to contain %q", err.Error(), want)
+	}
+}